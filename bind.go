@@ -0,0 +1,233 @@
+package nimbus
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	MIMEApplicationJSON    = "application/json"
+	MIMEApplicationXML     = "application/xml"
+	MIMETextXML            = "text/xml"
+	MIMEApplicationMsgPack = "application/msgpack"
+)
+
+// ErrUnsupportedMediaType is returned when no BodyDecoder is registered for a request's Content-Type.
+// Typed handlers respond with 415 Unsupported Media Type.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// BodyDecoder decodes a request body into target.
+// Decoders only bind values; validation against the schema happens afterwards,
+// so every decoder produces the same structured validation errors.
+type BodyDecoder interface {
+	Decode(ctx *Context, target any, schema *Schema) error
+}
+
+// BodyDecoderFunc adapts a function to the BodyDecoder interface.
+type BodyDecoderFunc func(ctx *Context, target any, schema *Schema) error
+
+// Decode calls f(ctx, target, schema).
+func (f BodyDecoderFunc) Decode(ctx *Context, target any, schema *Schema) error {
+	return f(ctx, target, schema)
+}
+
+// documentDecoder is implemented by the built-in decoders, which also return the decoded request
+// document (see lookupPresence) so missing and null fields can be told apart from zero values.
+type documentDecoder interface {
+	decodeDocument(ctx *Context, target any, schema *Schema) (any, error)
+}
+
+// documentDecoderFunc adapts a built-in decode function to BodyDecoder and documentDecoder.
+type documentDecoderFunc func(ctx *Context, target any, schema *Schema) (any, error)
+
+// Decode decodes the body, discarding the document.
+func (f documentDecoderFunc) Decode(ctx *Context, target any, schema *Schema) error {
+	_, err := f(ctx, target, schema)
+	return err
+}
+
+func (f documentDecoderFunc) decodeDocument(ctx *Context, target any, schema *Schema) (any, error) {
+	return f(ctx, target, schema)
+}
+
+// bodyDecoders maps media types (without parameters) to their decoders.
+var bodyDecoders = struct {
+	sync.RWMutex
+	m map[string]BodyDecoder
+}{
+	m: map[string]BodyDecoder{
+		MIMEApplicationJSON:     documentDecoderFunc(decodeJSON),
+		MIMEApplicationXML:      BodyDecoderFunc(decodeXML),
+		MIMETextXML:             BodyDecoderFunc(decodeXML),
+		MIMEApplicationForm:     documentDecoderFunc(decodeForm),
+		MIMEMultipartForm:       documentDecoderFunc(decodeForm),
+		MIMEApplicationMsgPack:  documentDecoderFunc(decodeMsgPack),
+		"application/x-msgpack": documentDecoderFunc(decodeMsgPack),
+	},
+}
+
+// RegisterBodyDecoder registers (or replaces) the decoder used for a media type,
+// e.g. "application/cbor". Register decoders at startup, before serving requests.
+//
+// Example:
+//
+//	nimbus.RegisterBodyDecoder("application/yaml", nimbus.BodyDecoderFunc(
+//	    func(ctx *nimbus.Context, target any, schema *nimbus.Schema) error {
+//	        return yaml.NewDecoder(ctx.Request.Body).Decode(target)
+//	    }))
+func RegisterBodyDecoder(mediaType string, decoder BodyDecoder) {
+	if decoder == nil {
+		panic("nimbus: RegisterBodyDecoder decoder is nil")
+	}
+	bodyDecoders.Lock()
+	defer bodyDecoders.Unlock()
+	bodyDecoders.m[strings.ToLower(mediaType)] = decoder
+}
+
+// lookupBodyDecoder finds the decoder for a Content-Type header value.
+// A missing Content-Type is treated as JSON; structured syntax suffixes
+// (e.g., application/problem+json) fall back to the JSON or XML decoder.
+func lookupBodyDecoder(contentType string) (BodyDecoder, string, bool) {
+	if contentType == "" {
+		contentType = MIMEApplicationJSON
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, contentType, false
+	}
+
+	bodyDecoders.RLock()
+	defer bodyDecoders.RUnlock()
+
+	if decoder, ok := bodyDecoders.m[mediaType]; ok {
+		return decoder, mediaType, true
+	}
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return bodyDecoders.m[MIMEApplicationJSON], mediaType, true
+	case strings.HasSuffix(mediaType, "+xml"):
+		return bodyDecoders.m[MIMEApplicationXML], mediaType, true
+	}
+	return nil, mediaType, false
+}
+
+// BindAndValidateBody decodes the request body using the decoder registered for its Content-Type
+// (JSON, XML, form, multipart and msgpack are built in) and validates it using a schema.
+// Returns an error wrapping ErrUnsupportedMediaType when no decoder matches.
+func (c *Context) BindAndValidateBody(target any, schema *Schema) error {
+	doc, err := c.decodeBody(target, schema)
+	return validateTarget(target, schema, doc, err, c.rules())
+}
+
+// decodeBody decodes the request body without validating it, returning the request document
+// from built-in decoders (nil for others)
+func (c *Context) decodeBody(target any, schema *Schema) (any, error) {
+	decoder, mediaType, ok := lookupBodyDecoder(c.GetHeader("Content-Type"))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+	}
+
+	if decoder, ok := decoder.(documentDecoder); ok {
+		return decoder.decodeDocument(c, target, schema)
+	}
+	return nil, decoder.Decode(c, target, schema)
+}
+
+// decodeJSON decodes a JSON request body, returning the generic document for presence checks
+func decodeJSON(ctx *Context, target any, schema *Schema) (any, error) {
+	return decodeJSONDocument(ctx.Request.Body, target, schema)
+}
+
+// decodeXML decodes an XML request body. No request document is built, so like Schema.Validate,
+// `required` can't tell a missing element from a zero value (use a pointer field for that).
+func decodeXML(ctx *Context, target any, _ *Schema) error {
+	if err := xml.NewDecoder(ctx.Request.Body).Decode(target); err != nil {
+		return fmt.Errorf("invalid XML: %w", err)
+	}
+	return nil
+}
+
+// decodeForm binds a URL-encoded or multipart form body
+func decodeForm(ctx *Context, target any, schema *Schema) (any, error) {
+	form, files, err := ctx.parseForm()
+	if err != nil {
+		return nil, err
+	}
+	return formDocument(form, files, schema), bindForm(form, files, target, schema)
+}
+
+// validateTarget validates a bound struct against its schema and ValidatedStruct implementation.
+// bindErr is the result of binding: conversion failures (ValidationErrors) are merged with schema
+// errors, dropping schema errors for those fields so a malformed value isn't also reported as missing.
+// Any other binding error is returned as is. doc is the request document used for presence checks (or nil)
+// and rules are the router's custom rules (or nil).
+func validateTarget(target any, schema *Schema, doc any, bindErr error, rules *ruleRegistry) error {
+	bindErrs, ok := bindErr.(ValidationErrors)
+	if bindErr != nil && !ok {
+		return bindErr
+	}
+
+	// Apply defaults and sanitizers before validating
+	if v := reflect.ValueOf(target); v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Type() == schema.structType {
+		schema.sanitize(v.Elem(), doc)
+	}
+
+	errs := bindErrs
+	for _, err := range schema.validate(target, doc, rules) {
+		if !hasFieldError(bindErrs, err.Field) {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// Check if the struct implements ValidatedStruct for custom validation
+	if validator, ok := target.(ValidatedStruct); ok {
+		if err := validator.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasFieldError reports whether errs contains an error for field
+func hasFieldError(errs ValidationErrors, field string) bool {
+	for _, err := range errs {
+		if err.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// typeError builds a validation error for a value that couldn't be converted to the field's type
+func typeError(fieldName, value string, err error) ValidationError {
+	return ValidationError{
+		Field:   fieldName,
+		Value:   value,
+		Tag:     "type",
+		Message: fmt.Sprintf("%s: %v", fieldName, err),
+	}
+}
+
+// sendBindError converts a binding error into a response:
+// validation errors use SendValidationError, unsupported media types are 415,
+// and anything else is a 400 with the given error code.
+func (c *Context) sendBindError(err error, code string) (any, int, error) {
+	var validationErrs ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return c.SendValidationError(validationErrs)
+	}
+	if errors.Is(err, ErrUnsupportedMediaType) {
+		return nil, http.StatusUnsupportedMediaType, NewAPIError("unsupported_media_type", err.Error())
+	}
+	return nil, http.StatusBadRequest, NewAPIError(code, err.Error())
+}
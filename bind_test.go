@@ -0,0 +1,293 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type TestOrderParams struct {
+	ID int `path:"id" validate:"min=1"`
+}
+
+type TestOrderBody struct {
+	Name     string `json:"name" xml:"name" form:"name" validate:"required,minlen=2"`
+	Quantity int    `json:"quantity" xml:"quantity" form:"quantity" validate:"min=1"`
+}
+
+type TestOrderQuery struct {
+	Limit int `json:"limit" validate:"max=100"`
+}
+
+var (
+	testOrderParamsValidator = NewValidator(&TestOrderParams{})
+	testOrderBodyValidator   = NewValidator(&TestOrderBody{})
+	testOrderQueryValidator  = NewValidator(&TestOrderQuery{})
+)
+
+func newTestOrderRouter() *Router {
+	router := NewRouter()
+	router.AddRoute(http.MethodPost, "/orders/:id", WithTyped(
+		func(ctx *Context, req *TypedRequest[TestOrderParams, TestOrderBody, TestOrderQuery]) (any, int, error) {
+			return map[string]any{"id": req.Params.ID, "name": req.Body.Name, "quantity": req.Body.Quantity}, 201, nil
+		}, testOrderParamsValidator, testOrderBodyValidator, testOrderQueryValidator))
+	return router
+}
+
+func decodeValidationDetails(t *testing.T, w *httptest.ResponseRecorder) []ValidationError {
+	t.Helper()
+	var resp struct {
+		Error   string            `json:"error"`
+		Details []ValidationError `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response body %q: %v", w.Body.String(), err)
+	}
+	if resp.Error != "validation_failed" {
+		t.Fatalf("expected validation_failed error, got %q", resp.Error)
+	}
+	return resp.Details
+}
+
+func TestBindAndValidateBody_ContentTypes(t *testing.T) {
+	router := newTestOrderRouter()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"json", "application/json", `{"name":"Widget","quantity":3}`},
+		{"json with charset", "application/json; charset=utf-8", `{"name":"Widget","quantity":3}`},
+		{"json suffix", "application/vnd.api+json", `{"name":"Widget","quantity":3}`},
+		{"missing content type", "", `{"name":"Widget","quantity":3}`},
+		{"xml", "application/xml", `<order><name>Widget</name><quantity>3</quantity></order>`},
+		{"text xml", "text/xml", `<order><name>Widget</name><quantity>3</quantity></order>`},
+		{"form", MIMEApplicationForm, `name=Widget&quantity=3`},
+		// {"name": "Widget", "quantity": 3}
+		{"msgpack", MIMEApplicationMsgPack, "\x82\xa4name\xa6Widget\xa8quantity\x03"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/orders/7", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusCreated {
+				t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var resp struct {
+				Data map[string]any `json:"data"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+			if resp.Data["name"] != "Widget" || resp.Data["quantity"] != float64(3) || resp.Data["id"] != float64(7) {
+				t.Errorf("unexpected response data: %v", resp.Data)
+			}
+		})
+	}
+}
+
+func TestBindAndValidateBody_UnsupportedMediaType(t *testing.T) {
+	router := newTestOrderRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/7", strings.NewReader("name: Widget"))
+	req.Header.Set("Content-Type", "application/yaml")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected status 415, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "unsupported_media_type") {
+		t.Errorf("expected unsupported_media_type error, got %s", w.Body.String())
+	}
+}
+
+func TestWithTyped_StructuredValidationErrors(t *testing.T) {
+	router := newTestOrderRouter()
+
+	tests := []struct {
+		name         string
+		target       string
+		contentType  string
+		body         string
+		expectedTags map[string]string // field -> tag
+	}{
+		{
+			name:         "json body",
+			target:       "/orders/7",
+			contentType:  "application/json",
+			body:         `{"name":"W","quantity":0}`,
+			expectedTags: map[string]string{"name": "minlen", "quantity": "min"},
+		},
+		{
+			name:         "form body with invalid number",
+			target:       "/orders/7",
+			contentType:  MIMEApplicationForm,
+			body:         `name=Widget&quantity=lots`,
+			expectedTags: map[string]string{"quantity": "type"},
+		},
+		{
+			// {"name": "Widget", "quantity": "lots"}
+			name:         "msgpack body with wrong type",
+			target:       "/orders/7",
+			contentType:  MIMEApplicationMsgPack,
+			body:         "\x82\xa4name\xa6Widget\xa8quantity\xa4lots",
+			expectedTags: map[string]string{"quantity": "type"},
+		},
+		{
+			name:         "path param",
+			target:       "/orders/0",
+			contentType:  "application/json",
+			body:         `{"name":"Widget","quantity":1}`,
+			expectedTags: map[string]string{"id": "min"},
+		},
+		{
+			name:         "path param type",
+			target:       "/orders/abc",
+			contentType:  "application/json",
+			body:         `{"name":"Widget","quantity":1}`,
+			expectedTags: map[string]string{"id": "type"},
+		},
+		{
+			name:         "query param",
+			target:       "/orders/7?limit=500",
+			contentType:  "application/json",
+			body:         `{"name":"Widget","quantity":1}`,
+			expectedTags: map[string]string{"limit": "max"},
+		},
+		{
+			name:         "query param type",
+			target:       "/orders/7?limit=ten",
+			contentType:  "application/json",
+			body:         `{"name":"Widget","quantity":1}`,
+			expectedTags: map[string]string{"limit": "type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+
+			details := decodeValidationDetails(t, w)
+			if len(details) != len(tt.expectedTags) {
+				t.Fatalf("expected %d errors, got %v", len(tt.expectedTags), details)
+			}
+			for _, d := range details {
+				if tt.expectedTags[d.Field] != d.Tag {
+					t.Errorf("unexpected error %s/%s: %s", d.Field, d.Tag, d.Message)
+				}
+			}
+		})
+	}
+
+	// Malformed JSON is reported with its byte offset
+	req := httptest.NewRequest(http.MethodPost, "/orders/7", strings.NewReader(`{"name":`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for malformed JSON, got %d: %s", w.Code, w.Body.String())
+	}
+	if details := decodeValidationDetails(t, w); len(details) != 1 || details[0].Tag != "syntax" || details[0].Offset != 8 {
+		t.Errorf("expected syntax error at offset 8, got %+v", details)
+	}
+}
+
+func TestRegisterBodyDecoder(t *testing.T) {
+	const mediaType = "text/x-order"
+	RegisterBodyDecoder(mediaType, BodyDecoderFunc(func(ctx *Context, target any, schema *Schema) error {
+		// "name;quantity"
+		var body strings.Builder
+		buf := make([]byte, 64)
+		n, _ := ctx.Request.Body.Read(buf)
+		body.Write(buf[:n])
+		name, quantity, _ := strings.Cut(body.String(), ";")
+		order := target.(*TestOrderBody)
+		order.Name = name
+		order.Quantity = len(quantity)
+		return nil
+	}))
+	defer func() {
+		bodyDecoders.Lock()
+		delete(bodyDecoders.m, mediaType)
+		bodyDecoders.Unlock()
+	}()
+
+	router := newTestOrderRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/1", strings.NewReader("Widget;xx"))
+	req.Header.Set("Content-Type", mediaType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Custom decoders get the same validation as built-in ones
+	req = httptest.NewRequest(http.MethodPost, "/orders/1", strings.NewReader("W;"))
+	req.Header.Set("Content-Type", mediaType)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest || len(decodeValidationDetails(t, w)) != 2 {
+		t.Errorf("expected 2 validation errors, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestDecodeMsgPack_Types(t *testing.T) {
+	type payload struct {
+		Neg   int      `json:"neg"`
+		Big   uint64   `json:"big"`
+		Float float64  `json:"float"`
+		Ok    bool     `json:"ok"`
+		Nil   *string  `json:"nil"`
+		List  []string `json:"list"`
+		Data  []byte   `json:"data"`
+	}
+
+	// {"neg": -300, "big": 2^32, "float": 1.5, "ok": true, "nil": nil, "list": ["a", "b"], "data": bin("hi")}
+	data := "\x87" +
+		"\xa3neg\xd1\xfe\xd4" +
+		"\xa3big\xcf\x00\x00\x00\x01\x00\x00\x00\x00" +
+		"\xa5float\xcb\x3f\xf8\x00\x00\x00\x00\x00\x00" +
+		"\xa2ok\xc3" +
+		"\xa3nil\xc0" +
+		"\xa4list\x92\xa1a\xa1b" +
+		"\xa4data\xc4\x02hi"
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(data))
+	ctx := NewContext(httptest.NewRecorder(), req)
+	defer ctx.Release()
+
+	var p payload
+	if _, err := decodeMsgPack(ctx, &p, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Neg != -300 || p.Big != 1<<32 || p.Float != 1.5 || !p.Ok || p.Nil != nil ||
+		len(p.List) != 2 || p.List[1] != "b" || string(p.Data) != "hi" {
+		t.Errorf("unexpected decode result: %+v", p)
+	}
+
+	for _, invalid := range []string{"\x82\xa4name", "\xc1", "\xdc\xff\xff", "\x01\x02"} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(invalid))
+		ctx := NewContext(httptest.NewRecorder(), req)
+		if _, err := decodeMsgPack(ctx, &p, nil); err == nil {
+			t.Errorf("expected error for invalid msgpack %q", invalid)
+		}
+		ctx.Release()
+	}
+}
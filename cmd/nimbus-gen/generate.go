@@ -0,0 +1,878 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Files written by every run; other files in the output directory belong to the user
+const (
+	modelsFile = "models_gen.go"
+	routesFile = "routes_gen.go"
+)
+
+// config configures a generation run
+type config struct {
+	SpecPath string // OpenAPI document to read
+	OutDir   string // Directory to write the generated package to
+	Package  string // Package name; defaults to the package already in OutDir, or its base name
+}
+
+// goStruct is a generated struct type
+type goStruct struct {
+	Name   string
+	Doc    []string
+	Fields []goField
+}
+
+// goField is a field of a generated struct; an empty Name embeds Type
+type goField struct {
+	Name string
+	Type string
+	Tag  string
+	Doc  []string
+}
+
+// route is an operation's handler and the Go types of its request and response
+type route struct {
+	op       *operation
+	handler  string // Handler function name
+	params   string // Path parameter struct, or "" if none
+	body     string // Request body struct, or "" if none
+	query    string // Query, header and cookie parameter struct, or "" if none
+	response string // Typed response struct for WithTypedResponse, or "" for any
+}
+
+// group is the routes registered by one RegisterXRoutes function
+type group struct {
+	name   string // Go name (Users for RegisterUsersRoutes)
+	label  string // Tag or path segment the group was named after
+	prefix string // Common static path prefix
+	routes []*route
+}
+
+// generator builds Go types for a document's schemas and operations
+type generator struct {
+	doc        *document
+	structs    []*goStruct
+	names      map[string]bool   // Go type names in use
+	components map[string]string // Go types of component schemas by schema name
+	resolving  map[string]bool   // Component schemas being converted, to break cycles
+	pathParams map[string]string // Path parameter structs by their fields
+	warnings   []string
+}
+
+// generate writes models_gen.go and routes_gen.go to config.OutDir and adds stubs to the group handler
+// files for operations without a handler. Files are only written when their content changes and
+// existing handlers are never modified, so running it again after editing the spec is safe.
+// It returns the paths of the files it wrote and any warnings about spec features it skipped.
+func generate(cfg config) (written []string, warnings []string, err error) {
+	doc, err := loadSpec(cfg.SpecPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading %s: %w", cfg.SpecPath, err)
+	}
+	ops, err := doc.operations()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.MkdirAll(cfg.OutDir, 0o755); err != nil {
+		return nil, nil, err
+	}
+
+	existing, pkg, err := scanPackage(cfg.OutDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.Package != "" {
+		pkg = cfg.Package
+	}
+	if pkg == "" {
+		abs, err := filepath.Abs(cfg.OutDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		pkg = strings.ToLower(strings.Join(words(filepath.Base(abs)), ""))
+		if !token.IsIdentifier(pkg) {
+			pkg = "api"
+		}
+	}
+
+	g := &generator{
+		doc:        doc,
+		names:      make(map[string]bool),
+		components: make(map[string]string),
+		resolving:  make(map[string]bool),
+		pathParams: make(map[string]string),
+	}
+	g.componentSchemas()
+	groups := g.groups(ops)
+
+	header := fmt.Sprintf("// Code generated by nimbus-gen from %s. DO NOT EDIT.\n\npackage %s\n", filepath.Base(cfg.SpecPath), pkg)
+	files := map[string][]byte{modelsFile: g.models(header)}
+	if len(groups) > 0 {
+		files[routesFile] = g.routes(header, groups)
+	}
+	for _, name := range []string{modelsFile, routesFile} {
+		src, ok := files[name]
+		if !ok {
+			continue
+		}
+		changed, err := writeGoFile(filepath.Join(cfg.OutDir, name), src)
+		if err != nil {
+			return written, g.warnings, err
+		}
+		if changed {
+			written = append(written, filepath.Join(cfg.OutDir, name))
+		}
+	}
+
+	// Handler files are the user's; only stubs for missing handlers are added
+	for _, grp := range groups {
+		path := filepath.Join(cfg.OutDir, snakeCase(grp.name)+".go")
+		changed, err := addHandlerStubs(path, pkg, grp, existing)
+		if err != nil {
+			return written, g.warnings, err
+		}
+		if changed {
+			written = append(written, path)
+		}
+	}
+	return written, g.warnings, nil
+}
+
+// writeGoFile formats src and writes it to path if its content changed
+func writeGoFile(path string, src []byte) (bool, error) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return false, fmt.Errorf("formatting %s: %w\n%s", filepath.Base(path), err, src)
+	}
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, formatted) {
+		return false, nil
+	}
+	return true, os.WriteFile(path, formatted, 0o644)
+}
+
+func (g *generator) warnf(format string, args ...any) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, args...))
+}
+
+// typeName reserves a unique Go type name based on name
+func (g *generator) typeName(name string) string {
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// componentSchemas generates a struct for each object schema in components/schemas.
+// Names are reserved first so schemas can refer to each other in any order.
+func (g *generator) componentSchemas() {
+	schemas := g.doc.root.obj("components").obj("schemas")
+	if schemas == nil {
+		return
+	}
+	var structs []string
+	for _, name := range schemas.keys {
+		schema := schemas.obj(name)
+		if schema == nil || schema.has("$ref") || !g.isStruct(schema) {
+			continue
+		}
+		g.components[name] = g.typeName(goName(name))
+		structs = append(structs, name)
+	}
+	for _, name := range structs {
+		schema := schemas.obj(name)
+		doc := []string{fmt.Sprintf("%s is the %s schema", g.components[name], name)}
+		if description := schema.str("description"); description != "" {
+			doc = append(doc, "")
+			doc = append(doc, strings.Split(strings.TrimSpace(description), "\n")...)
+		}
+		g.buildStruct(g.components[name], schema, doc)
+	}
+}
+
+// schemaType returns a schema's type and whether it allows null: nullable (3.0) or a type
+// array containing "null" (3.1). Schemas with several non-null types have type "".
+func schemaType(schema *mapping) (string, bool) {
+	nullable := schema.boolean("nullable")
+	switch t := schema.get("type").(type) {
+	case string:
+		return t, nullable || t == "null"
+	case []any:
+		var types []string
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "null" {
+				types = append(types, s)
+			} else if ok {
+				nullable = true
+			}
+		}
+		if len(types) == 1 {
+			return types[0], nullable
+		}
+		return "", nullable
+	}
+	switch {
+	case schema.has("properties"):
+		return "object", nullable
+	case schema.has("items"):
+		return "array", nullable
+	}
+	return "", nullable
+}
+
+// isStruct reports whether a schema generates a struct: an object with properties, or an allOf of them
+func (g *generator) isStruct(schema *mapping) bool {
+	return g.isStructDepth(schema, 0)
+}
+
+func (g *generator) isStructDepth(schema *mapping, depth int) bool {
+	schema = g.doc.resolve(schema)
+	if schema == nil || depth > 16 {
+		return false
+	}
+	if members := schema.list("allOf"); len(members) > 0 {
+		for _, member := range members {
+			if m, _ := member.(*mapping); !g.isStructDepth(m, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+	typ, _ := schemaType(schema)
+	return (typ == "object" || typ == "") && len(schema.obj("properties").fields()) > 0
+}
+
+// goType returns the Go type for a schema, generating structs for inline objects named after hint
+func (g *generator) goType(schema *mapping, hint string) string {
+	if schema == nil {
+		return "any"
+	}
+	if ref := schema.str("$ref"); ref != "" {
+		return g.refType(ref, hint)
+	}
+
+	typ, nullable := schemaType(schema)
+	var t string
+	allOf := schema.list("allOf")
+	switch {
+	case len(allOf) == 1:
+		member, _ := allOf[0].(*mapping)
+		t = g.goType(member, hint)
+	case len(allOf) > 1 && g.isStruct(schema):
+		t = g.buildStruct(g.typeName(hint), schema, nil)
+	case len(allOf) > 1, schema.has("oneOf"), schema.has("anyOf"):
+		t = "any"
+	case typ == "string":
+		switch schema.str("format") {
+		case "date-time":
+			t = "time.Time"
+		case "byte":
+			t = "[]byte" // encoding/json uses base64 for []byte
+		default:
+			t = "string"
+		}
+	case typ == "integer":
+		switch schema.str("format") {
+		case "int32":
+			t = "int32"
+		case "int64":
+			t = "int64"
+		default:
+			t = "int"
+		}
+	case typ == "number":
+		t = "float64"
+		if schema.str("format") == "float" {
+			t = "float32"
+		}
+	case typ == "boolean":
+		t = "bool"
+	case typ == "array":
+		t = "[]" + g.goType(schema.obj("items"), hint+"Item")
+	case typ == "object" && g.isStruct(schema):
+		t = g.buildStruct(g.typeName(hint), schema, nil)
+	case typ == "object":
+		t = "map[string]any"
+		if values := schema.obj("additionalProperties"); values != nil {
+			t = "map[string]" + g.goType(values, hint+"Value")
+		}
+	default:
+		t = "any"
+	}
+
+	if nullable && t != "any" && !strings.HasPrefix(t, "nimbus.Optional[") {
+		t = "nimbus.Optional[" + t + "]"
+	}
+	return t
+}
+
+// refType returns the Go type for a $ref
+func (g *generator) refType(ref, hint string) string {
+	name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+	if !ok {
+		target := g.doc.lookup(ref)
+		if target == nil {
+			g.warnf("%s: unresolved $ref, using any", ref)
+			return "any"
+		}
+		return g.goType(target, hint)
+	}
+	if t, ok := g.components[name]; ok {
+		return t
+	}
+	target := g.doc.lookup(ref)
+	if target == nil {
+		g.warnf("%s: unresolved $ref, using any", ref)
+		return "any"
+	}
+	if g.resolving[name] {
+		return "any" // Recursive non-object schema
+	}
+	g.resolving[name] = true
+	t := g.goType(target, goName(name))
+	g.components[name] = t
+	return t
+}
+
+// buildStruct generates a struct named name for an object schema. Members of allOf that refer to
+// component structs are embedded; properties of other members are merged into the struct.
+func (g *generator) buildStruct(name string, schema *mapping, doc []string) string {
+	st := &goStruct{Name: name, Doc: doc}
+	g.structs = append(g.structs, st)
+
+	used := make(map[string]bool)
+	var add func(schema *mapping)
+	add = func(schema *mapping) {
+		for _, member := range schema.list("allOf") {
+			m, _ := member.(*mapping)
+			if ref := m.str("$ref"); ref != "" && g.isStruct(m) {
+				embedded := g.refType(ref, name)
+				if !used[embedded] {
+					used[embedded] = true
+					st.Fields = append(st.Fields, goField{Type: embedded})
+				}
+				continue
+			}
+			add(g.doc.resolve(m))
+		}
+
+		required := make(map[string]bool)
+		for _, item := range schema.list("required") {
+			if s, ok := item.(string); ok {
+				required[s] = true
+			}
+		}
+		properties := schema.obj("properties")
+		for _, prop := range properties.fields() {
+			propSchema := properties.obj(prop)
+			fieldName := uniqueField(goName(prop), used)
+			fieldType := g.goType(propSchema, name+goName(prop))
+			tags := [][2]string{{"json", prop}}
+			tags = append(tags, g.fieldTags(propSchema, fieldType, required[prop])...)
+			st.Fields = append(st.Fields, goField{
+				Name: fieldName,
+				Type: fieldType,
+				Tag:  structTag(tags),
+				Doc:  descriptionLines(g.doc.resolve(propSchema)),
+			})
+		}
+	}
+	add(g.doc.resolve(schema))
+	return name
+}
+
+// describe sets the doc comment of a generated struct that doesn't have one
+func (g *generator) describe(name string, doc ...string) {
+	for _, st := range g.structs {
+		if st.Name == name && len(st.Doc) == 0 {
+			st.Doc = doc
+		}
+	}
+}
+
+// uniqueField returns name, or name with a number appended if a field already uses it
+func uniqueField(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// descriptionLines returns a schema's description as comment lines
+func descriptionLines(schema *mapping) []string {
+	description := strings.TrimSpace(schema.str("description"))
+	if description == "" {
+		return nil
+	}
+	return strings.Split(description, "\n")
+}
+
+// fieldTags returns the validate, default and example tags for a field
+func (g *generator) fieldTags(schema *mapping, fieldType string, required bool) [][2]string {
+	var tags [][2]string
+	rules := g.rules(schema)
+	if required {
+		rules = append([]string{"required"}, rules...)
+	}
+	if len(rules) > 0 {
+		tags = append(tags, [2]string{"validate", strings.Join(rules, ",")})
+	}
+
+	resolved := g.doc.resolve(schema)
+	if value, ok := scalarTag(resolved.get("default"), fieldType); ok {
+		tags = append(tags, [2]string{"default", value})
+	}
+	example := resolved.get("example")
+	if examples := resolved.list("examples"); example == nil && len(examples) > 0 {
+		example = examples[0] // 3.1 examples array
+	}
+	if value, ok := scalarTag(example, fieldType); ok {
+		tags = append(tags, [2]string{"example", value})
+	}
+	return tags
+}
+
+// rules returns the validator rules for a schema's constraints
+func (g *generator) rules(schema *mapping) []string {
+	schema = g.doc.resolve(schema)
+	if schema == nil || g.isStruct(schema) {
+		return nil // Nested structs are validated by their own tags
+	}
+	if members := schema.list("allOf"); len(members) == 1 {
+		m, _ := members[0].(*mapping)
+		return g.rules(m)
+	}
+
+	var rules []string
+	number := func(key string) (string, bool) {
+		n, ok := schema.num(key)
+		return strconv.FormatFloat(n, 'f', -1, 64), ok
+	}
+	typ, _ := schemaType(schema)
+	switch typ {
+	case "string":
+		if n, ok := number("minLength"); ok {
+			rules = append(rules, "minlen="+n)
+		}
+		if n, ok := number("maxLength"); ok {
+			rules = append(rules, "maxlen="+n)
+		}
+		if pattern := schema.str("pattern"); pattern != "" {
+			if _, err := regexp.Compile(pattern); err != nil {
+				g.warnf("pattern %q isn't a valid Go regexp, skipping it", pattern)
+			} else {
+				rules = append(rules, "pattern="+strings.ReplaceAll(pattern, ",", `\,`))
+			}
+		}
+		switch format := schema.str("format"); format {
+		case "email", "uuid", "ipv4", "ipv6", "hostname":
+			rules = append(rules, format)
+		case "uri", "url":
+			rules = append(rules, "url")
+		}
+		if enum := enumRule(schema.list("enum")); enum != "" {
+			rules = append(rules, enum)
+		}
+	case "integer", "number":
+		if n, ok := number("minimum"); ok {
+			if schema.boolean("exclusiveMinimum") {
+				rules = append(rules, "gt="+n)
+			} else {
+				rules = append(rules, "min="+n)
+			}
+		}
+		if n, ok := number("exclusiveMinimum"); ok {
+			rules = append(rules, "gt="+n)
+		}
+		if n, ok := number("maximum"); ok {
+			if schema.boolean("exclusiveMaximum") {
+				rules = append(rules, "lt="+n)
+			} else {
+				rules = append(rules, "max="+n)
+			}
+		}
+		if n, ok := number("exclusiveMaximum"); ok {
+			rules = append(rules, "lt="+n)
+		}
+	case "array":
+		if n, ok := number("minItems"); ok {
+			rules = append(rules, "minitems="+n)
+		}
+		if n, ok := number("maxItems"); ok {
+			rules = append(rules, "maxitems="+n)
+		}
+		if schema.boolean("uniqueItems") {
+			rules = append(rules, "unique")
+		}
+		if items := g.rules(schema.obj("items")); len(items) > 0 {
+			rules = append(append(rules, "dive"), items...)
+		}
+	}
+	return rules
+}
+
+// enumRule returns the enum rule for string enum values, or "" if they can't be written as one
+func enumRule(values []any) string {
+	var names []string
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok || s == "" || strings.ContainsAny(s, ",|") {
+			return ""
+		}
+		names = append(names, s)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return "enum=" + strings.Join(names, "|")
+}
+
+// scalarTag formats a default or example value for a field of type fieldType. Only values
+// the validator can parse for the field are returned, since invalid tags panic in NewValidator.
+func scalarTag(value any, fieldType string) (string, bool) {
+	fieldType = strings.TrimSuffix(strings.TrimPrefix(fieldType, "nimbus.Optional["), "]")
+	switch v := value.(type) {
+	case string:
+		if fieldType == "time.Time" {
+			_, err := time.Parse(time.RFC3339, v)
+			return v, err == nil
+		}
+		return v, fieldType == "string"
+	case bool:
+		return strconv.FormatBool(v), fieldType == "bool"
+	case float64:
+		switch fieldType {
+		case "float32", "float64":
+			return strconv.FormatFloat(v, 'f', -1, 64), true
+		case "int", "int32", "int64":
+			return strconv.FormatFloat(v, 'f', -1, 64), v == float64(int64(v))
+		}
+	}
+	return "", false
+}
+
+// structTag formats struct tags, quoting values so they can hold any character
+func structTag(tags [][2]string) string {
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		parts[i] = tag[0] + ":" + strconv.Quote(tag[1])
+	}
+	tag := strings.Join(parts, " ")
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// groups builds the route groups for the operations and generates their request types
+func (g *generator) groups(ops []*operation) []*group {
+	var groups []*group
+	byName := make(map[string]*group)
+	paths := make(map[*group][]string)
+	for _, op := range ops {
+		label := groupLabel(op)
+		name := goName(label)
+		grp, exists := byName[name]
+		if !exists {
+			grp = &group{name: name, label: label}
+			byName[name] = grp
+			groups = append(groups, grp)
+		}
+		grp.routes = append(grp.routes, g.route(op))
+		paths[grp] = append(paths[grp], op.Path)
+	}
+	for _, grp := range groups {
+		grp.prefix = commonPrefix(paths[grp])
+	}
+	return groups
+}
+
+// groupLabel returns the name of an operation's group: its first tag, or the first path segment
+// that isn't "api" or a version
+func groupLabel(op *operation) string {
+	if len(op.Tags) > 0 {
+		return op.Tags[0]
+	}
+	for _, segment := range strings.Split(op.Path, "/") {
+		if segment == "" || segment == "api" || strings.HasPrefix(segment, "{") || versionSegment.MatchString(segment) {
+			continue
+		}
+		return segment
+	}
+	return "root"
+}
+
+var versionSegment = regexp.MustCompile(`^v\d+$`)
+
+// commonPrefix returns the longest static path prefix shared by paths ("/api/v1/users" for
+// "/api/v1/users" and "/api/v1/users/{id}")
+func commonPrefix(paths []string) string {
+	var prefix []string
+	for i, path := range paths {
+		segments := strings.Split(strings.Trim(path, "/"), "/")
+		if i == 0 {
+			for _, segment := range segments {
+				if segment == "" || strings.Contains(segment, "{") {
+					break
+				}
+				prefix = append(prefix, segment)
+			}
+			continue
+		}
+		n := 0
+		for n < len(prefix) && n < len(segments) && prefix[n] == segments[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	if len(prefix) == 0 {
+		return ""
+	}
+	return "/" + strings.Join(prefix, "/")
+}
+
+// routerPath converts an OpenAPI path template to a router path ("/users/{id}" is "/users/:id")
+func routerPath(path string) string {
+	return templateParam.ReplaceAllString(path, ":$1")
+}
+
+var templateParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// route generates the parameter, body and response types for an operation
+func (g *generator) route(op *operation) *route {
+	base := goName(op.ID)
+	r := &route{op: op, handler: lowerCamel(op.ID)}
+
+	var pathFields, queryFields []goField
+	var kinds []string
+	pathUsed, queryUsed := make(map[string]bool), make(map[string]bool)
+	for _, param := range op.Parameters {
+		name, in := param.str("name"), param.str("in")
+		schema := param.obj("schema")
+		if schema == nil {
+			schema = contentSchema(param.obj("content"))
+		}
+		fieldType := g.goType(schema, base+goName(name))
+		if in == "query" && strings.HasPrefix(fieldType, "map[") {
+			fieldType = "map[string]string" // deepObject parameters bind to string maps
+		}
+
+		tags := g.fieldTags(schema, fieldType, param.boolean("required") && in != "path")
+		doc := descriptionLines(param)
+		switch in {
+		case "path":
+			field := goField{Name: uniqueField(goName(name), pathUsed), Type: fieldType, Doc: doc}
+			field.Tag = structTag(append([][2]string{{"path", name}}, tags...))
+			pathFields = append(pathFields, field)
+			continue
+		case "query":
+			tags = append([][2]string{{"json", name}, {"query", name}}, tags...)
+			kinds = appendUnique(kinds, "query parameters")
+		case "header":
+			tags = append([][2]string{{"header", name}}, tags...)
+			kinds = appendUnique(kinds, "headers")
+		case "cookie":
+			tags = append([][2]string{{"cookie", name}}, tags...)
+			kinds = appendUnique(kinds, "cookies")
+		default:
+			g.warnf("%s %s: unknown parameter location %q for %s", op.Method, op.Path, in, name)
+			continue
+		}
+		queryFields = append(queryFields, goField{Name: uniqueField(goName(name), queryUsed), Type: fieldType, Tag: structTag(tags), Doc: doc})
+	}
+
+	if len(pathFields) > 0 {
+		// Operations on the same path share their path parameter struct
+		key := fmt.Sprint(pathFields)
+		if r.params = g.pathParams[key]; r.params == "" {
+			r.params = g.typeName(base + "Params")
+			g.pathParams[key] = r.params
+			g.structs = append(g.structs, &goStruct{
+				Name:   r.params,
+				Doc:    []string{fmt.Sprintf("%s holds path parameters for %s", r.params, op.Path)},
+				Fields: pathFields,
+			})
+		}
+	}
+	if len(queryFields) > 0 {
+		r.query = g.typeName(base + "Query")
+		g.structs = append(g.structs, &goStruct{
+			Name:   r.query,
+			Doc:    []string{fmt.Sprintf("%s holds %s for %s", r.query, joinList(kinds), r.handler)},
+			Fields: queryFields,
+		})
+	}
+
+	if op.Body != nil {
+		if g.isStruct(op.Body) {
+			r.body = g.goType(op.Body, base+"Request")
+			g.describe(r.body, fmt.Sprintf("%s is the request body for %s", r.body, r.handler))
+		} else {
+			g.warnf("%s %s: only object request bodies are bound, skipping the body", op.Method, op.Path)
+		}
+	}
+
+	// Responses documented with nimbus's envelope ({"success": true, "data": ...}) return the data
+	response := op.Response
+	if resolved := g.doc.resolve(response); resolved.obj("properties").has("success") && resolved.obj("properties").has("data") {
+		response = resolved.obj("properties").obj("data")
+	}
+	if response != nil && g.isStruct(response) {
+		r.response = g.goType(response, base+"Response")
+		g.describe(r.response, fmt.Sprintf("%s is the response body for %s", r.response, r.handler))
+	}
+	return r
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, item := range list {
+		if item == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// joinList joins items as an English list ("a, b and c")
+func joinList(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// models renders models_gen.go
+func (g *generator) models(header string) []byte {
+	var body bytes.Buffer
+	for _, st := range g.structs {
+		writeComment(&body, "", st.Doc)
+		fmt.Fprintf(&body, "type %s struct {\n", st.Name)
+		for _, field := range st.Fields {
+			writeComment(&body, "\t", field.Doc)
+			if field.Name == "" {
+				fmt.Fprintf(&body, "\t%s\n", field.Type)
+				continue
+			}
+			fmt.Fprintf(&body, "\t%s %s %s\n", field.Name, field.Type, field.Tag)
+		}
+		body.WriteString("}\n\n")
+	}
+
+	var imports []string
+	if bytes.Contains(body.Bytes(), []byte("time.Time")) {
+		imports = append(imports, `"time"`)
+	}
+	if bytes.Contains(body.Bytes(), []byte("nimbus.")) {
+		imports = append(imports, "", `"github.com/DylanHalstead/nimbus"`)
+	}
+
+	var src bytes.Buffer
+	src.WriteString(header)
+	writeImports(&src, imports)
+	src.Write(body.Bytes())
+	return src.Bytes()
+}
+
+// routes renders routes_gen.go
+func (g *generator) routes(header string, groups []*group) []byte {
+	var src bytes.Buffer
+	src.WriteString(header)
+	writeImports(&src, []string{`"net/http"`, "", `"github.com/DylanHalstead/nimbus"`})
+
+	// Validators are shared by routes using the same type
+	validators := make(map[string]string)
+	var order []string
+	validator := func(typ string) string {
+		if typ == "" {
+			return "nil"
+		}
+		if _, exists := validators[typ]; !exists {
+			validators[typ] = lowerCamel(typ) + "Validator"
+			order = append(order, typ)
+		}
+		return validators[typ]
+	}
+	var registrations bytes.Buffer
+	for _, grp := range groups {
+		fmt.Fprintf(&registrations, "// Register%sRoutes registers the %s routes\n", grp.name, grp.label)
+		fmt.Fprintf(&registrations, "func Register%sRoutes(router *nimbus.Router, middleware ...nimbus.Middleware) {\n", grp.name)
+		fmt.Fprintf(&registrations, "group := router.Group(%q, middleware...)\n", grp.prefix)
+		for _, r := range grp.routes {
+			comment := r.op.Method + " " + r.op.Path
+			if r.op.Summary != "" {
+				comment += " - " + strings.TrimSpace(strings.Split(r.op.Summary, "\n")[0])
+			}
+			wrap := "WithTyped"
+			if r.response != "" {
+				wrap = "WithTypedResponse"
+			}
+			fmt.Fprintf(&registrations, "\n// %s\n", comment)
+			fmt.Fprintf(&registrations, "group.AddRoute(%s, %q,\nnimbus.%s(%s, %s, %s, %s))\n",
+				methodConstant(r.op.Method), routerPath(strings.TrimPrefix(r.op.Path, grp.prefix)),
+				wrap, r.handler, validator(r.params), validator(r.body), validator(r.query))
+		}
+		registrations.WriteString("}\n\n")
+	}
+
+	if len(order) > 0 {
+		src.WriteString("var (\n")
+		for _, typ := range order {
+			fmt.Fprintf(&src, "%s = nimbus.NewValidator(&%s{})\n", validators[typ], typ)
+		}
+		src.WriteString(")\n\n")
+	}
+	src.Write(registrations.Bytes())
+	return src.Bytes()
+}
+
+// methodConstant returns the net/http constant for a method (http.MethodGet for GET)
+func methodConstant(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+		http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace:
+		return "http.Method" + method[:1] + strings.ToLower(method[1:])
+	}
+	return strconv.Quote(method)
+}
+
+// writeImports writes an import block; "" separates standard library and module imports
+func writeImports(b *bytes.Buffer, imports []string) {
+	if len(imports) == 0 {
+		b.WriteString("\n")
+		return
+	}
+	if imports[0] == "" {
+		imports = imports[1:]
+	}
+	b.WriteString("\nimport (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(b, "\t%s\n", imp)
+	}
+	b.WriteString(")\n\n")
+}
+
+// writeComment writes lines as a // comment
+func writeComment(b *bytes.Buffer, indent string, lines []string) {
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			fmt.Fprintf(b, "%s//\n", indent)
+			continue
+		}
+		fmt.Fprintf(b, "%s// %s\n", indent, line)
+	}
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// runGenerate generates the spec into dir, returning the names of the files written
+func runGenerate(t *testing.T, spec, dir string) []string {
+	t.Helper()
+	written, _, err := generate(config{SpecPath: spec, OutDir: dir, Package: "api"})
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	names := make([]string, len(written))
+	for i, path := range written {
+		names[i] = filepath.Base(path)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	written := runGenerate(t, "testdata/petstore.yaml", dir)
+	if want := []string{"models_gen.go", "pets.go", "routes_gen.go", "stores.go"}; !reflect.DeepEqual(written, want) {
+		t.Fatalf("written = %v, want %v", written, want)
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range written {
+		if _, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0); err != nil {
+			t.Errorf("%s isn't valid Go: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{
+			file: "models_gen.go",
+			want: []string{
+				"// Code generated by nimbus-gen from petstore.yaml. DO NOT EDIT.",
+				"type Pet struct {\n\tNewPet\n",
+				"Name       string            `json:\"name\" validate:\"required,minlen=1\" example:\"Rex\"`",
+				"OwnerEmail string            `json:\"owner_email\" validate:\"email\"`",
+				"PhotoURLs []string  `json:\"photo_urls\" validate:\"unique,dive,url\"`",
+				"BornAt    time.Time `json:\"born_at\"`",
+				"Limit     int      `json:\"limit\" query:\"limit\" validate:\"min=1,max=100\" default:\"20\"`",
+				"Status    string   `json:\"status\" query:\"status\" validate:\"enum=available|pending|sold\"`",
+				"XTenantID string   `header:\"X-Tenant-ID\" validate:\"required\"`",
+				"// The pet's ID\n\tPetID int64 `path:\"petId\" validate:\"min=1\"`",
+				"Name   nimbus.Optional[string] `json:\"name\" validate:\"maxlen=50\"`",
+				"Weight float64                 `json:\"weight\" validate:\"gt=0\"`",
+				"Notes    string    `json:\"notes\" validate:\"pattern=^[a-z\\\\, ]*$\"`",
+				"type PostStoresStoreIDOrdersResponse struct",
+			},
+		},
+		{
+			file: "routes_gen.go",
+			want: []string{
+				"= nimbus.NewValidator(&GetPetParams{})",
+				"func RegisterPetsRoutes(router *nimbus.Router, middleware ...nimbus.Middleware) {\n\tgroup := router.Group(\"/api/v1/pets\", middleware...)",
+				"// GET /api/v1/pets - List pets\n\tgroup.AddRoute(http.MethodGet, \"\",\n\t\tnimbus.WithTyped(listPets, nil, nil, listPetsQueryValidator))",
+				"nimbus.WithTypedResponse(createPet, nil, newPetValidator, nil))",
+				"group.AddRoute(http.MethodDelete, \"/:petId\",\n\t\tnimbus.WithTyped(deletePet, getPetParamsValidator, nil, nil))",
+				"group.AddRoute(http.MethodPost, \"/:storeId/orders\",",
+			},
+		},
+		{
+			file: "pets.go",
+			want: []string{
+				"package api\n\nimport (\n\t\"net/http\"\n\n\t\"github.com/DylanHalstead/nimbus\"\n)",
+				"// getPet handles GET /api/v1/pets/{petId} - Get a pet\nfunc getPet(ctx *nimbus.Context, req *nimbus.TypedRequest[GetPetParams, struct{}, struct{}]) (*Pet, int, error) {",
+				"func updatePet(ctx *nimbus.Context, req *nimbus.TypedRequest[GetPetParams, UpdatePetRequest, struct{}]) (any, int, error) {",
+				"return nil, http.StatusNotImplemented, nimbus.NewAPIError(\"not_implemented\", \"listPets is not implemented\")",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			src := readFile(t, filepath.Join(dir, tt.file))
+			for _, want := range tt.want {
+				if !strings.Contains(src, want) {
+					t.Errorf("%s doesn't contain %q:\n%s", tt.file, want, src)
+				}
+			}
+		})
+	}
+
+	// Path parameters shared by the operations on a path generate one struct
+	if models := readFile(t, filepath.Join(dir, "models_gen.go")); strings.Contains(models, "DeletePetParams") {
+		t.Error("expected deletePet to reuse GetPetParams")
+	}
+}
+
+func TestGenerate_Idempotent(t *testing.T) {
+	dir := t.TempDir()
+	runGenerate(t, "testdata/petstore.yaml", dir)
+	before := make(map[string]string)
+	for _, name := range []string{"models_gen.go", "routes_gen.go", "pets.go", "stores.go"} {
+		before[name] = readFile(t, filepath.Join(dir, name))
+	}
+
+	if written := runGenerate(t, "testdata/petstore.yaml", dir); len(written) != 0 {
+		t.Errorf("second run wrote %v, want nothing", written)
+	}
+	for name, src := range before {
+		if got := readFile(t, filepath.Join(dir, name)); got != src {
+			t.Errorf("%s changed on the second run", name)
+		}
+	}
+}
+
+func TestGenerate_PreservesHandlers(t *testing.T) {
+	dir := t.TempDir()
+	runGenerate(t, "testdata/petstore.yaml", dir)
+
+	// Implement a handler, and move another to its own file
+	pets := filepath.Join(dir, "pets.go")
+	stub := `return nil, http.StatusNotImplemented, nimbus.NewAPIError("not_implemented", "getPet is not implemented")`
+	impl := `return &Pet{ID: req.Params.PetID}, http.StatusOK, nil`
+	src := strings.Replace(readFile(t, pets), stub, impl, 1)
+	deleteStart := strings.Index(src, "// deletePet handles")
+	deleteEnd := deleteStart + strings.Index(src[deleteStart:], "\n}\n") + 3
+	moved := src[deleteStart:deleteEnd]
+	src = src[:deleteStart] + src[deleteEnd:]
+	if err := os.WriteFile(pets, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "delete.go"), []byte("package api\n\n"+moved), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Add an operation to the spec
+	spec := strings.Replace(readFile(t, "testdata/petstore.yaml"), "    delete:\n      operationId: deletePet\n",
+		"    put:\n      operationId: replacePet\n      tags: [pets]\n      responses:\n        '204':\n          description: Replaced\n    delete:\n      operationId: deletePet\n", 1)
+	specPath := filepath.Join(t.TempDir(), "petstore.yaml")
+	if err := os.WriteFile(specPath, []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if written, want := runGenerate(t, specPath, dir), []string{"pets.go", "routes_gen.go"}; !reflect.DeepEqual(written, want) {
+		t.Errorf("written = %v, want %v", written, want)
+	}
+	got := readFile(t, pets)
+	if !strings.HasPrefix(got, src) {
+		t.Errorf("existing code in pets.go was modified:\n%s", got)
+	}
+	if !strings.Contains(got, "func replacePet(") {
+		t.Errorf("pets.go is missing the replacePet stub:\n%s", got)
+	}
+	if strings.Contains(got, "func deletePet(") {
+		t.Errorf("deletePet stub was added again although it is declared in delete.go:\n%s", got)
+	}
+}
+
+func TestGenerate_AddsImports(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "no imports",
+			src:  "package api\n\nvar x = 1\n",
+			want: "package api\n\nimport (\n\t\"net/http\"\n\n\t\"github.com/DylanHalstead/nimbus\"\n)\n\nvar x = 1\n",
+		},
+		{
+			name: "single import",
+			src:  "package api\n\nimport \"fmt\"\n\nvar _ = fmt.Sprint\n",
+			want: "import (\n\t\"fmt\"\n\t\"net/http\"\n\n\t\"github.com/DylanHalstead/nimbus\"\n)\n",
+		},
+		{
+			name: "grouped imports",
+			src:  "package api\n\nimport (\n\t\"fmt\"\n\n\t\"github.com/oklog/ulid/v2\"\n)\n\nvar _ = fmt.Sprint\nvar _ ulid.ULID\n",
+			want: "import (\n\t\"fmt\"\n\t\"net/http\"\n\n\t\"github.com/DylanHalstead/nimbus\"\n\t\"github.com/oklog/ulid/v2\"\n)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "stores.go"), []byte(tt.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			runGenerate(t, "testdata/petstore.yaml", dir)
+			if got := readFile(t, filepath.Join(dir, "stores.go")); !strings.Contains(got, tt.want) {
+				t.Errorf("stores.go = %s, want it to contain %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_JSONSpec(t *testing.T) {
+	spec := `{
+		"openapi": "3.1.0",
+		"info": {"title": "Notes", "version": "1"},
+		"paths": {
+			"/notes/{id}": {
+				"get": {
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Note"}}}}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Note": {
+					"type": "object",
+					"required": ["title"],
+					"properties": {
+						"title": {"type": "string", "maxLength": 80},
+						"score": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 5},
+						"archived_at": {"type": ["string", "null"], "format": "date-time"}
+					}
+				}
+			}
+		}
+	}`
+	specPath := filepath.Join(t.TempDir(), "notes.json")
+	if err := os.WriteFile(specPath, []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	runGenerate(t, specPath, dir)
+
+	models := readFile(t, filepath.Join(dir, "models_gen.go"))
+	for _, want := range []string{
+		"Title      string                     `json:\"title\" validate:\"required,maxlen=80\"`",
+		"Score      float64                    `json:\"score\" validate:\"gt=0,lt=5\"`",
+		"ArchivedAt nimbus.Optional[time.Time] `json:\"archived_at\"`",
+		"type GetNotesIDParams struct",
+	} {
+		if !strings.Contains(models, want) {
+			t.Errorf("models_gen.go doesn't contain %q:\n%s", want, models)
+		}
+	}
+	if notes := readFile(t, filepath.Join(dir, "notes.go")); !strings.Contains(notes, "func getNotesID(ctx *nimbus.Context, req *nimbus.TypedRequest[GetNotesIDParams, struct{}, struct{}]) (*Note, int, error)") {
+		t.Errorf("notes.go is missing the getNotesID stub:\n%s", notes)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{
+			name:    "swagger 2",
+			spec:    "swagger: '2.0'\n",
+			wantErr: `unsupported OpenAPI version ""`,
+		},
+		{
+			name:    "duplicate operationId",
+			spec:    "openapi: 3.0.0\npaths:\n  /a:\n    get:\n      operationId: op\n  /b:\n    get:\n      operationId: op\n",
+			wantErr: `operationId "op" is used by both GET /a and GET /b`,
+		},
+		{
+			name:    "invalid YAML",
+			spec:    "openapi: 3.0.0\npaths: &paths {}\n",
+			wantErr: "anchors, aliases and tags aren't supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specPath := filepath.Join(t.TempDir(), "spec.yaml")
+			if err := os.WriteFile(specPath, []byte(tt.spec), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, _, err := generate(config{SpecPath: specPath, OutDir: t.TempDir()})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("generate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      string
+		wantLower string
+	}{
+		{"user_id", "UserID", "userID"},
+		{"createdAt", "CreatedAt", "createdAt"},
+		{"X-Request-ID", "XRequestID", "xRequestID"},
+		{"photo_urls", "PhotoURLs", "photoURLs"},
+		{"HTTPServer", "HTTPServer", "httpServer"},
+		{"id", "ID", "id"},
+		{"2fa", "X2fa", "x2fa"},
+		{"type", "Type", "typeHandler"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := goName(tt.input); got != tt.want {
+				t.Errorf("goName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if got := lowerCamel(tt.input); got != tt.wantLower {
+				t.Errorf("lowerCamel(%q) = %q, want %q", tt.input, got, tt.wantLower)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const nimbusImport = "github.com/DylanHalstead/nimbus"
+
+// scanPackage returns the functions declared in the package in dir, outside generated files
+// and tests, and the package's name ("" if dir has no Go files)
+func scanPackage(dir string) (map[string]bool, string, error) {
+	funcs := make(map[string]bool)
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, "", err
+	}
+
+	var pkg string
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, "", fmt.Errorf("parsing %s: %w", path, err)
+		}
+		pkg = f.Name.Name
+		if name == modelsFile || name == routesFile {
+			continue
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+				funcs[fn.Name.Name] = true
+			}
+		}
+	}
+	return funcs, pkg, nil
+}
+
+// addHandlerStubs appends a stub to the handler file at path for each route of grp whose handler
+// isn't declared in the package, creating the file if needed. Existing code is left as it is.
+func addHandlerStubs(path, pkg string, grp *group, existing map[string]bool) (bool, error) {
+	var stubs bytes.Buffer
+	for _, r := range grp.routes {
+		if existing[r.handler] {
+			continue
+		}
+		existing[r.handler] = true
+		writeStub(&stubs, r)
+	}
+	if stubs.Len() == 0 {
+		return false, nil
+	}
+
+	src, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		src, err = fmt.Appendf(nil, "package %s\n", pkg), nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if src, err = ensureImports(path, src, "net/http", nimbusImport); err != nil {
+		return false, err
+	}
+
+	// The file isn't reformatted, so code outside the stubs stays byte for byte as it was
+	src = append(bytes.TrimRight(src, "\n"), "\n\n"...)
+	src = append(src, bytes.TrimRight(stubs.Bytes(), "\n")...)
+	return true, os.WriteFile(path, append(src, '\n'), 0o644)
+}
+
+// writeStub writes a handler that returns 501 Not Implemented
+func writeStub(b *bytes.Buffer, r *route) {
+	params, body, query, response := "struct{}", "struct{}", "struct{}", "any"
+	if r.params != "" {
+		params = r.params
+	}
+	if r.body != "" {
+		body = r.body
+	}
+	if r.query != "" {
+		query = r.query
+	}
+	if r.response != "" {
+		response = "*" + r.response
+	}
+
+	comment := fmt.Sprintf("%s handles %s %s", r.handler, r.op.Method, r.op.Path)
+	if r.op.Summary != "" {
+		comment += " - " + strings.TrimSpace(strings.Split(r.op.Summary, "\n")[0])
+	}
+	fmt.Fprintf(b, "// %s\n", comment)
+	fmt.Fprintf(b, "func %s(ctx *nimbus.Context, req *nimbus.TypedRequest[%s, %s, %s]) (%s, int, error) {\n", r.handler, params, body, query, response)
+	fmt.Fprintf(b, "\treturn nil, http.StatusNotImplemented, nimbus.NewAPIError(\"not_implemented\", %q)\n", r.handler+" is not implemented")
+	b.WriteString("}\n\n")
+}
+
+// ensureImports adds the imports a file is missing for the stubs appended to it
+func ensureImports(path string, src []byte, imports ...string) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.ImportsOnly)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	have := make(map[string]bool)
+	for _, spec := range f.Imports {
+		if value, err := strconv.Unquote(spec.Path.Value); err == nil {
+			have[value] = true
+		}
+	}
+	// Standard library imports go in the first group, others in the last
+	var std, other []string
+	for _, imp := range imports {
+		if have[imp] {
+			continue
+		}
+		if strings.Contains(strings.Split(imp, "/")[0], ".") {
+			other = append(other, strconv.Quote(imp))
+		} else {
+			std = append(std, strconv.Quote(imp))
+		}
+	}
+	if len(std)+len(other) == 0 {
+		return src, nil
+	}
+
+	block := func(existing ...string) string {
+		lines := append(existing, std...)
+		if len(other) > 0 && len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		var b strings.Builder
+		b.WriteString("import (\n")
+		for _, line := range append(lines, other...) {
+			if line != "" {
+				b.WriteString("\t" + line)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(")")
+		return b.String()
+	}
+
+	// Add to the first import declaration (turning a single import into a block),
+	// or after the package clause
+	start := fset.Position(f.Name.End()).Offset
+	end, decl := start, "\n\n"+block()
+	for _, d := range f.Decls {
+		gen, ok := d.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		start, end = fset.Position(gen.Pos()).Offset, fset.Position(gen.End()).Offset
+		if !gen.Lparen.IsValid() {
+			decl = block(string(src[fset.Position(gen.Specs[0].Pos()).Offset:end]))
+			break
+		}
+		lparen, rparen := fset.Position(gen.Lparen).Offset+1, fset.Position(gen.Rparen).Offset
+		decl = string(src[start:lparen])
+		if len(std) > 0 {
+			decl += "\n\t" + strings.Join(std, "\n\t")
+		}
+		decl += string(src[lparen:rparen])
+		if len(other) > 0 {
+			decl += "\t" + strings.Join(other, "\n\t") + "\n"
+		}
+		decl += ")"
+
+		// Sort the block's groups like gofmt; the rest of the file is left as it is
+		formatted, err := format.Source([]byte("package p\n\n" + decl))
+		if err != nil {
+			return nil, fmt.Errorf("adding imports to %s: %w", path, err)
+		}
+		decl = strings.TrimSpace(strings.TrimPrefix(string(formatted), "package p\n\n"))
+		break
+	}
+
+	result := make([]byte, 0, len(src)+len(decl))
+	result = append(result, src[:start]...)
+	result = append(result, decl...)
+	return append(result, src[end:]...), nil
+}
@@ -0,0 +1,48 @@
+// Command nimbus-gen scaffolds a nimbus API from an OpenAPI 3.x document (JSON or YAML).
+//
+// It writes models_gen.go, with a struct for each object schema and the path, query and body types
+// of each operation (tagged for binding and validation), and routes_gen.go, with the validators
+// and a RegisterXRoutes function per tag. Handlers go in one file per tag (users.go for the users
+// tag): stubs returning 501 Not Implemented are added for operations without a handler, and
+// existing handlers are never touched, so it can be run again whenever the spec changes.
+//
+// Usage:
+//
+//	nimbus-gen -spec openapi.yaml -out ./api [-package api]
+//
+// Or from a go:generate directive in the output package:
+//
+//	//go:generate go run github.com/DylanHalstead/nimbus/cmd/nimbus-gen -spec openapi.yaml -out .
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	var cfg config
+	flag.StringVar(&cfg.SpecPath, "spec", "", "OpenAPI 3.x document (JSON or YAML)")
+	flag.StringVar(&cfg.OutDir, "out", ".", "output package directory")
+	flag.StringVar(&cfg.Package, "package", "", "package name (default: the existing package, or the directory name)")
+	flag.Parse()
+
+	if cfg.SpecPath == "" {
+		fmt.Fprintln(os.Stderr, "nimbus-gen: -spec is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	written, warnings, err := generate(cfg)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "nimbus-gen: warning: %s\n", warning)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "nimbus-gen: %v\n", err)
+		os.Exit(1)
+	}
+	for _, path := range written {
+		fmt.Println("wrote", path)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// initialisms are words written in upper case in Go names (user_id is UserID)
+var initialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "CSV": true, "DNS": true, "EOF": true,
+	"GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"JWT": true, "OS": true, "SKU": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true,
+	"TTL": true, "UDP": true, "UI": true, "UID": true, "URI": true, "URL": true, "UTC": true,
+	"UUID": true, "XML": true,
+}
+
+// words splits a name into words at separators and case changes ("userID" is "user", "ID")
+func words(name string) []string {
+	var result []string
+	runes := []rune(name)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				result = append(result, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		prev := runes[i-1]
+		lowerToUpper := unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev))
+		// The last capital of an acronym starts the next word ("HTTPServer" is "HTTP", "Server")
+		acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || acronymEnd {
+			result = append(result, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		result = append(result, string(runes[start:]))
+	}
+	return result
+}
+
+// goName converts a spec name to an exported Go name ("user_id" is "UserID")
+func goName(name string) string {
+	var b strings.Builder
+	for _, word := range words(name) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		if singular, ok := strings.CutSuffix(word, "s"); ok && initialisms[strings.ToUpper(singular)] {
+			b.WriteString(strings.ToUpper(singular) + "s") // photo_urls is PhotoURLs
+			continue
+		}
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	result := b.String()
+	if result == "" || unicode.IsDigit([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
+
+// lowerCamel converts a spec name to an unexported Go name ("GetUserByID" is "getUserByID")
+func lowerCamel(name string) string {
+	parts := words(goName(name))
+	parts[0] = strings.ToLower(parts[0])
+	result := strings.Join(parts, "")
+	if token.IsKeyword(result) {
+		result += "Handler"
+	}
+	return result
+}
+
+// snakeCase converts a Go name to a file name ("PetStore" is "pet_store")
+func snakeCase(name string) string {
+	parts := words(name)
+	for i, part := range parts {
+		parts[i] = strings.ToLower(part)
+	}
+	return strings.Join(parts, "_")
+}
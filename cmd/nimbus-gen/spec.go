@@ -0,0 +1,343 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mapping is a decoded JSON object or YAML mapping that remembers its key order,
+// so generated structs list fields in the order the spec declares them
+type mapping struct {
+	keys   []string
+	values map[string]any
+}
+
+func newMapping() *mapping {
+	return &mapping{values: make(map[string]any)}
+}
+
+func (m *mapping) set(key string, value any) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// fields returns the keys in order; a nil mapping has none
+func (m *mapping) fields() []string {
+	if m == nil {
+		return nil
+	}
+	return m.keys
+}
+
+func (m *mapping) get(key string) any {
+	if m == nil {
+		return nil
+	}
+	return m.values[key]
+}
+
+func (m *mapping) has(key string) bool {
+	if m == nil {
+		return false
+	}
+	_, ok := m.values[key]
+	return ok
+}
+
+// str returns the string value of key, or "" if it isn't a string
+func (m *mapping) str(key string) string {
+	s, _ := m.get(key).(string)
+	return s
+}
+
+// obj returns the mapping value of key, or nil if it isn't a mapping
+func (m *mapping) obj(key string) *mapping {
+	o, _ := m.get(key).(*mapping)
+	return o
+}
+
+// list returns the sequence value of key, or nil if it isn't a sequence
+func (m *mapping) list(key string) []any {
+	l, _ := m.get(key).([]any)
+	return l
+}
+
+// num returns the number value of key and whether it is a number
+func (m *mapping) num(key string) (float64, bool) {
+	n, ok := m.get(key).(float64)
+	return n, ok
+}
+
+// boolean returns the boolean value of key, or false if it isn't a boolean
+func (m *mapping) boolean(key string) bool {
+	b, _ := m.get(key).(bool)
+	return b
+}
+
+// document is a loaded OpenAPI 3.x document
+type document struct {
+	root *mapping
+}
+
+// operation is an OpenAPI operation with its parameters, request body and success response resolved
+type operation struct {
+	ID         string
+	Method     string // HTTP method (e.g. "GET")
+	Path       string // OpenAPI path template (e.g. "/users/{id}")
+	Summary    string
+	Tags       []string
+	Parameters []*mapping // Parameter objects with $refs resolved
+	Body       *mapping   // JSON request body schema, or nil
+	Response   *mapping   // Success response schema, or nil
+}
+
+// openAPIMethods lists operation keys of a path item in the order they are generated
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// loadSpec reads an OpenAPI 3.x document from a JSON or YAML file
+func loadSpec(path string) (*document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseSpec(data)
+}
+
+// parseSpec parses an OpenAPI 3.x document. JSON is detected by a leading '{'.
+func parseSpec(data []byte) (*document, error) {
+	var value any
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		value, err = decodeJSON(data)
+	} else {
+		value, err = parseYAML(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := value.(*mapping)
+	if !ok {
+		return nil, fmt.Errorf("document must be an object")
+	}
+	if version := root.str("openapi"); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, expected 3.x", version)
+	}
+	return &document{root: root}, nil
+}
+
+// decodeJSON decodes a JSON document, keeping object key order
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	value, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected content after JSON document")
+	}
+	return value, nil
+}
+
+func decodeJSONValue(dec *json.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			m := newMapping()
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				m.set(key.(string), value)
+			}
+			_, err := dec.Token() // '}'
+			return m, err
+		case '[':
+			items := []any{}
+			for dec.More() {
+				item, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+			_, err := dec.Token() // ']'
+			return items, err
+		}
+		return nil, fmt.Errorf("unexpected %v", t)
+	case json.Number:
+		return strconv.ParseFloat(t.String(), 64)
+	}
+	return token, nil
+}
+
+// resolve follows $ref chains within the document, returning nil for refs it can't resolve
+func (d *document) resolve(m *mapping) *mapping {
+	for i := 0; m != nil && m.has("$ref"); i++ {
+		if i > 32 {
+			return nil // Circular reference
+		}
+		m = d.lookup(m.str("$ref"))
+	}
+	return m
+}
+
+// lookup returns the value a local JSON pointer ref ("#/components/schemas/User") points to
+func (d *document) lookup(ref string) *mapping {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	current := d.root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if current = current.obj(token); current == nil {
+			return nil
+		}
+	}
+	return current
+}
+
+// operations returns the document's operations in path and method order
+func (d *document) operations() ([]*operation, error) {
+	var ops []*operation
+	seen := make(map[string]string)
+
+	paths := d.root.obj("paths")
+	if paths == nil {
+		return nil, nil
+	}
+	for _, path := range paths.keys {
+		item := d.resolve(paths.obj(path))
+		if item == nil {
+			continue
+		}
+		for _, method := range openAPIMethods {
+			raw := item.obj(method)
+			if raw == nil {
+				continue
+			}
+
+			op := &operation{
+				ID:         raw.str("operationId"),
+				Method:     strings.ToUpper(method),
+				Path:       path,
+				Summary:    raw.str("summary"),
+				Parameters: d.parameters(item.list("parameters"), raw.list("parameters")),
+				Body:       d.requestBodySchema(raw.obj("requestBody")),
+				Response:   d.successSchema(raw.obj("responses")),
+			}
+			for _, tag := range raw.list("tags") {
+				if s, ok := tag.(string); ok {
+					op.Tags = append(op.Tags, s)
+				}
+			}
+			if op.ID == "" {
+				op.ID = defaultOperationID(method, path)
+			}
+
+			location := op.Method + " " + path
+			if previous, exists := seen[op.ID]; exists {
+				return nil, fmt.Errorf("operationId %q is used by both %s and %s", op.ID, previous, location)
+			}
+			seen[op.ID] = location
+			ops = append(ops, op)
+		}
+	}
+	return ops, nil
+}
+
+// parameters merges path item and operation parameters; operation parameters override
+// path item parameters with the same name and location
+func (d *document) parameters(pathParams, opParams []any) []*mapping {
+	var params []*mapping
+	index := make(map[string]int)
+	for _, list := range [][]any{pathParams, opParams} {
+		for _, raw := range list {
+			m, _ := raw.(*mapping)
+			param := d.resolve(m)
+			if param == nil || param.str("name") == "" {
+				continue
+			}
+			key := param.str("in") + ":" + param.str("name")
+			if i, exists := index[key]; exists {
+				params[i] = param
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// requestBodySchema returns the schema of a request body's JSON content (or its first content type)
+func (d *document) requestBodySchema(body *mapping) *mapping {
+	return contentSchema(d.resolve(body).obj("content"))
+}
+
+// successSchema returns the schema of the lowest 2xx response with content
+func (d *document) successSchema(responses *mapping) *mapping {
+	if responses == nil {
+		return nil
+	}
+	codes := make([]string, 0, len(responses.keys))
+	for _, code := range responses.keys {
+		if n, err := strconv.Atoi(code); err == nil && n >= 200 && n < 300 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if code == strconv.Itoa(http.StatusNoContent) {
+			continue
+		}
+		if schema := contentSchema(d.resolve(responses.obj(code)).obj("content")); schema != nil {
+			return schema
+		}
+	}
+	return nil
+}
+
+// contentSchema returns the schema of the JSON media type in content, or of its first media type
+func contentSchema(content *mapping) *mapping {
+	if content == nil || len(content.keys) == 0 {
+		return nil
+	}
+	for _, mediaType := range content.keys {
+		if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			return content.obj(mediaType).obj("schema")
+		}
+	}
+	return content.obj(content.keys[0]).obj("schema")
+}
+
+// defaultOperationID derives an operationId for an operation without one (GET /users/{id} is "getUsersID")
+func defaultOperationID(method, path string) string {
+	id := method
+	for _, segment := range strings.Split(path, "/") {
+		if segment = strings.Trim(segment, "{}"); segment != "" {
+			id += goName(segment)
+		}
+	}
+	return id
+}
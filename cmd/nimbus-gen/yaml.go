@@ -0,0 +1,459 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML parses the YAML subset OpenAPI documents use: block mappings and sequences, flow
+// collections ([a, b], {a: 1}), plain and quoted scalars, literal (|) and folded (>) block scalars,
+// and comments. Anchors, aliases, tags and multiple documents aren't supported.
+// Mappings decode to *mapping, sequences to []any and numbers to float64.
+func parseYAML(data []byte) (any, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "---" && len(p.lines) == 0 {
+			continue
+		}
+		if strings.ContainsRune(raw[:len(raw)-len(strings.TrimLeft(raw, " \t"))], '\t') {
+			return nil, fmt.Errorf("line %d: tabs can't be used for indentation", i+1)
+		}
+		p.lines = append(p.lines, yamlLine{
+			number: i + 1,
+			indent: len(raw) - len(strings.TrimLeft(raw, " ")),
+			text:   strings.TrimRight(raw, " "),
+		})
+	}
+
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	value, err := p.parseBlock(p.lines[p.pos].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.skipBlank(); p.pos < len(p.lines) {
+		return nil, p.errorf("unexpected content %q", p.current().content())
+	}
+	return value, nil
+}
+
+// yamlLine is a source line; text keeps the indentation so block scalars can be rebuilt
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+// content returns the line without indentation and comments
+func (l yamlLine) content() string {
+	return strings.TrimSpace(stripComment(l.text[min(l.indent, len(l.text)):]))
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func (p *yamlParser) current() yamlLine {
+	return p.lines[p.pos]
+}
+
+func (p *yamlParser) errorf(format string, args ...any) error {
+	line := 0
+	if p.pos < len(p.lines) {
+		line = p.lines[p.pos].number
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// skipBlank skips empty and comment-only lines
+func (p *yamlParser) skipBlank() {
+	for p.pos < len(p.lines) && p.current().content() == "" {
+		p.pos++
+	}
+}
+
+// parseBlock parses the mapping or sequence starting at the current line
+func (p *yamlParser) parseBlock(indent int) (any, error) {
+	if isSequenceItem(p.current().content()) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+func (p *yamlParser) parseSequence(indent int) (any, error) {
+	items := []any{}
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		line := p.current()
+		content := line.content()
+		if line.indent < indent || !isSequenceItem(content) {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf("bad indentation of a sequence item")
+		}
+
+		rest := strings.TrimLeft(strings.TrimPrefix(content, "-"), " ")
+		if rest == "" {
+			// The item is the block on the following lines
+			p.pos++
+			item, err := p.parseNested(indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+
+		// Parse the item's content as if it started a block at its own column
+		offset := line.indent + len(content) - len(rest)
+		p.lines[p.pos] = yamlLine{number: line.number, indent: offset, text: line.text}
+		if isSequenceItem(rest) || splitKey(rest) != nil {
+			item, err := p.parseBlock(offset)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+		p.pos++
+		item, err := p.parseInlineValue(rest, indent)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (p *yamlParser) parseMapping(indent int) (any, error) {
+	m := newMapping()
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		line := p.current()
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf("bad indentation of a mapping entry")
+		}
+		content := line.content()
+		if isSequenceItem(content) {
+			break
+		}
+		kv := splitKey(content)
+		if kv == nil {
+			return nil, p.errorf("expected a mapping entry, got %q", content)
+		}
+		key, err := parseKey(kv[0])
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		if m.has(key) {
+			return nil, p.errorf("duplicate key %q", key)
+		}
+
+		p.pos++
+		var value any
+		if kv[1] == "" {
+			value, err = p.parseNested(indent)
+		} else {
+			value, err = p.parseInlineValue(kv[1], indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m.set(key, value)
+	}
+	return m, nil
+}
+
+// parseNested parses the value of a key or item whose content is on the following lines.
+// A mapping value may be a sequence at the key's own indentation.
+func (p *yamlParser) parseNested(parentIndent int) (any, error) {
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	line := p.current()
+	if line.indent > parentIndent || line.indent == parentIndent && isSequenceItem(line.content()) {
+		return p.parseBlock(line.indent)
+	}
+	return nil, nil
+}
+
+// parseInlineValue parses a value written after "key:" or "-", including block scalars and
+// plain scalars continued on more indented lines
+func (p *yamlParser) parseInlineValue(value string, parentIndent int) (any, error) {
+	switch {
+	case strings.HasPrefix(value, "&"), strings.HasPrefix(value, "*"), strings.HasPrefix(value, "!"):
+		p.pos--
+		return nil, p.errorf("anchors, aliases and tags aren't supported")
+	case value[0] == '|' || value[0] == '>':
+		return p.parseBlockScalar(value, parentIndent)
+	case value[0] == '[' || value[0] == '{' || value[0] == '"' || value[0] == '\'':
+		// Flow collections and quoted strings may continue on the following lines
+		for !flowComplete(value) && p.pos < len(p.lines) {
+			value += " " + p.current().content()
+			p.pos++
+		}
+		parsed, rest, err := parseFlow(value)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		if strings.TrimSpace(rest) != "" {
+			return nil, p.errorf("unexpected %q after value", rest)
+		}
+		return parsed, nil
+	}
+
+	// Plain scalars fold continuation lines into one line
+	for p.skipBlank(); p.pos < len(p.lines) && p.current().indent > parentIndent; p.skipBlank() {
+		value += " " + p.current().content()
+		p.pos++
+	}
+	return plainScalar(value), nil
+}
+
+// parseBlockScalar parses a literal (|) or folded (>) block scalar
+func (p *yamlParser) parseBlockScalar(header string, parentIndent int) (any, error) {
+	folded := header[0] == '>'
+	chomp := strings.TrimLeft(header[1:], "0123456789")
+
+	var lines []string
+	blockIndent := -1
+	for p.pos < len(p.lines) {
+		line := p.current()
+		if strings.TrimSpace(line.text) == "" {
+			lines = append(lines, "")
+			p.pos++
+			continue
+		}
+		if line.indent <= parentIndent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = line.indent
+		}
+		lines = append(lines, line.text[min(blockIndent, line.indent):])
+		p.pos++
+	}
+
+	// Trailing blank lines belong to the following content
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		p.pos--
+	}
+
+	var text string
+	if folded {
+		var b strings.Builder
+		for i, line := range lines {
+			// Line breaks fold into spaces, and each blank line into a line break
+			switch {
+			case i == 0 || lines[i-1] == "" && line != "":
+			case line == "":
+				b.WriteString("\n")
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(line)
+		}
+		text = b.String()
+	} else {
+		text = strings.Join(lines, "\n")
+	}
+	if chomp != "-" && text != "" {
+		text += "\n"
+	}
+	return text, nil
+}
+
+// isSequenceItem reports whether a line starts a sequence item
+func isSequenceItem(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+// splitKey splits "key: value" into key and value, returning nil if content isn't a mapping entry.
+// The separator is the first colon followed by a space or the end of line outside quotes.
+func splitKey(content string) []string {
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == '[' || c == '{':
+			if i == 0 {
+				return nil // Flow collection
+			}
+		case c == ':' && (i+1 == len(content) || content[i+1] == ' '):
+			return []string{strings.TrimSpace(content[:i]), strings.TrimSpace(content[i+1:])}
+		}
+	}
+	return nil
+}
+
+// parseKey unquotes a mapping key
+func parseKey(key string) (string, error) {
+	if key != "" && (key[0] == '"' || key[0] == '\'') {
+		value, rest, err := parseQuoted(key)
+		if err != nil || rest != "" {
+			return "", fmt.Errorf("invalid key %s", key)
+		}
+		return value, nil
+	}
+	return key, nil
+}
+
+// stripComment removes a trailing comment (" #" outside quotes)
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.ContainsRune(" [{,:", rune(s[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// flowComplete reports whether a flow collection or quoted string is closed
+func flowComplete(s string) bool {
+	_, _, err := parseFlow(s)
+	return err == nil
+}
+
+// parseFlow parses a flow value at the start of s, returning the rest
+func parseFlow(s string) (any, string, error) {
+	s = strings.TrimLeft(s, " ")
+	if s == "" {
+		return nil, "", fmt.Errorf("unexpected end of flow value")
+	}
+	switch s[0] {
+	case '"', '\'':
+		return parseQuoted(s)
+	case '[':
+		items := []any{}
+		s = strings.TrimLeft(s[1:], " ")
+		for {
+			if strings.HasPrefix(s, "]") {
+				return items, s[1:], nil
+			}
+			item, rest, err := parseFlow(s)
+			if err != nil {
+				return nil, "", err
+			}
+			items = append(items, item)
+			if s, err = flowSeparator(rest, ']'); err != nil {
+				return nil, "", err
+			}
+		}
+	case '{':
+		m := newMapping()
+		s = strings.TrimLeft(s[1:], " ")
+		for {
+			if strings.HasPrefix(s, "}") {
+				return m, s[1:], nil
+			}
+			key, rest, err := parseFlow(s)
+			if err != nil {
+				return nil, "", err
+			}
+			rest = strings.TrimLeft(rest, " ")
+			if !strings.HasPrefix(rest, ":") {
+				return nil, "", fmt.Errorf("expected ':' in flow mapping")
+			}
+			value, rest, err := parseFlow(rest[1:])
+			if err != nil {
+				return nil, "", err
+			}
+			m.set(fmt.Sprint(key), value)
+			if s, err = flowSeparator(rest, '}'); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	// Plain scalar, ending at a flow indicator
+	end := strings.IndexAny(s, ",]}")
+	if colon := strings.Index(s, ": "); colon >= 0 && (end < 0 || colon < end) {
+		end = colon
+	}
+	if end < 0 {
+		end = len(s)
+	}
+	return plainScalar(strings.TrimSpace(s[:end])), s[end:], nil
+}
+
+// flowSeparator consumes the "," between flow items, leaving the closing bracket in place
+func flowSeparator(s string, closing byte) (string, error) {
+	s = strings.TrimLeft(s, " ")
+	switch {
+	case strings.HasPrefix(s, ","):
+		return strings.TrimLeft(s[1:], " "), nil
+	case s != "" && s[0] == closing:
+		return s, nil
+	}
+	return "", fmt.Errorf("expected ',' or '%c' in flow collection", closing)
+}
+
+// parseQuoted parses a single or double quoted string at the start of s, returning the rest
+func parseQuoted(s string) (string, string, error) {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote && quote == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++ // Escaped single quote
+		case s[i] == quote:
+			if quote == '\'' {
+				return strings.ReplaceAll(s[1:i], "''", "'"), s[i+1:], nil
+			}
+			value, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", fmt.Errorf("invalid string %s", s[:i+1])
+			}
+			return value, s[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("unterminated string %s", s)
+}
+
+// plainScalar resolves a plain scalar to null, a boolean, a number or a string
+func plainScalar(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return float64(n)
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && strings.ContainsAny(s, ".eE") && !strings.ContainsAny(s, "xX") {
+		return f
+	}
+	return s
+}
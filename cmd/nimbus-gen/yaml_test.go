@@ -0,0 +1,161 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// plain converts parsed mappings to map[string]any for comparisons
+func plain(value any) any {
+	switch v := value.(type) {
+	case *mapping:
+		m := make(map[string]any, len(v.keys))
+		for _, key := range v.keys {
+			m[key] = plain(v.values[key])
+		}
+		return m
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = plain(item)
+		}
+		return items
+	}
+	return value
+}
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  any
+	}{
+		{
+			name:  "nested mappings",
+			input: "info:\n  title: Pets\n  version: 1.0.0\n",
+			want:  map[string]any{"info": map[string]any{"title": "Pets", "version": "1.0.0"}},
+		},
+		{
+			name:  "scalars",
+			input: "a: 1\nb: 2.5\nc: true\nd: null\ne: ~\nf: '200'\ng: \"x\\ty\"\nh: 'it''s'\n",
+			want:  map[string]any{"a": 1.0, "b": 2.5, "c": true, "d": nil, "e": nil, "f": "200", "g": "x\ty", "h": "it's"},
+		},
+		{
+			name:  "sequence of mappings",
+			input: "params:\n  - name: id\n    in: path\n  - name: q\n",
+			want:  map[string]any{"params": []any{map[string]any{"name": "id", "in": "path"}, map[string]any{"name": "q"}}},
+		},
+		{
+			name:  "sequence at the key's indentation",
+			input: "tags:\n- a\n- b\nnext: 1\n",
+			want:  map[string]any{"tags": []any{"a", "b"}, "next": 1.0},
+		},
+		{
+			name:  "nested sequences",
+			input: "- - a\n  - b\n- c\n",
+			want:  []any{[]any{"a", "b"}, "c"},
+		},
+		{
+			name:  "flow collections",
+			input: "tags: [pets, 'a, b']\nschema: {type: string, enum: [x, y]}\nempty: []\nnone: {}\n",
+			want: map[string]any{
+				"tags":   []any{"pets", "a, b"},
+				"schema": map[string]any{"type": "string", "enum": []any{"x", "y"}},
+				"empty":  []any{},
+				"none":   map[string]any{},
+			},
+		},
+		{
+			name:  "multi-line flow collection",
+			input: "required: [\n  id,\n  name\n]\n",
+			want:  map[string]any{"required": []any{"id", "name"}},
+		},
+		{
+			name:  "comments",
+			input: "# header\na: 1 # trailing\nb: 'x # y'\nc: a#b\n",
+			want:  map[string]any{"a": 1.0, "b": "x # y", "c": "a#b"},
+		},
+		{
+			name:  "quoted keys and colons in values",
+			input: "'200':\n  description: \"OK: done\"\nurl: https://example.com/a\n\"/users/{id}\": x\n",
+			want:  map[string]any{"200": map[string]any{"description": "OK: done"}, "url": "https://example.com/a", "/users/{id}": "x"},
+		},
+		{
+			name:  "literal block scalar",
+			input: "description: |\n  line one\n\n  line two\nnext: 1\n",
+			want:  map[string]any{"description": "line one\n\nline two\n", "next": 1.0},
+		},
+		{
+			name:  "folded block scalar with strip chomping",
+			input: "description: >-\n  one\n  two\n\n  three\n",
+			want:  map[string]any{"description": "one two\nthree"},
+		},
+		{
+			name:  "multi-line plain scalar",
+			input: "summary: a long\n  summary\nnext: 1\n",
+			want:  map[string]any{"summary": "a long summary", "next": 1.0},
+		},
+		{
+			name:  "empty value",
+			input: "a:\nb: 1\n",
+			want:  map[string]any{"a": nil, "b": 1.0},
+		},
+		{
+			name:  "document marker",
+			input: "---\na: 1\n",
+			want:  map[string]any{"a": 1.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("parseYAML() error = %v", err)
+			}
+			if !reflect.DeepEqual(plain(got), tt.want) {
+				t.Errorf("parseYAML() = %#v, want %#v", plain(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYAML_KeyOrder(t *testing.T) {
+	got, err := parseYAML([]byte("zeta: 1\nalpha: 2\nmid: {b: 1, a: 2}\n"))
+	if err != nil {
+		t.Fatalf("parseYAML() error = %v", err)
+	}
+	m := got.(*mapping)
+	if want := []string{"zeta", "alpha", "mid"}; !reflect.DeepEqual(m.keys, want) {
+		t.Errorf("keys = %v, want %v", m.keys, want)
+	}
+	if want := []string{"b", "a"}; !reflect.DeepEqual(m.obj("mid").keys, want) {
+		t.Errorf("flow keys = %v, want %v", m.obj("mid").keys, want)
+	}
+}
+
+func TestParseYAML_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"anchor", "a: &x 1\n", "anchors, aliases and tags aren't supported"},
+		{"alias", "a: *x\n", "anchors, aliases and tags aren't supported"},
+		{"tab indentation", "a:\n\tb: 1\n", "tabs can't be used for indentation"},
+		{"duplicate key", "a: 1\na: 2\n", `duplicate key "a"`},
+		{"bad indentation", "a:\n    b: 1\n  c: 2\n", "line 3"},
+		{"not a mapping entry", "a: 1\njust text\n", "expected a mapping entry"},
+		{"unterminated string", "a: 'x\n", "unterminated string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseYAML([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseYAML() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
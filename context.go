@@ -2,6 +2,7 @@ package nimbus
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/url"
@@ -34,7 +35,9 @@ var contextPool = sync.Pool{
 // Access context.Context via c.Request.Context() for cancellation, timeouts, and tracing.
 // It is request-scoped and should be passed through the handler chain.
 type Context struct {
-	Writer  http.ResponseWriter
+	// Writer wraps the original http.ResponseWriter and tracks status, size, and whether
+	// headers have been committed. Use Writer.Unwrap() to reach the original writer.
+	Writer  ResponseWriter
 	Request *http.Request
 	// PathParams contains extracted path parameters from the route (e.g., :id, :name).
 	PathParams map[string]string
@@ -46,6 +49,12 @@ type Context struct {
 	// Used to pass data between middleware and handlers (e.g., request_id, user, validated_body).
 	// Private to force use of the Context.Set and Context.Get methods.
 	values map[string]any
+	// writer is the pooled ResponseWriter backing Writer (stored by value to avoid an allocation).
+	writer responseWriter
+	// router is the router serving this request (nil for contexts created outside ServeHTTP).
+	router *Router
+	// locale overrides the Accept-Language negotiation for validation messages (see SetLocale).
+	locale string
 }
 
 // NewContext grabs a context from the pool and initializes it.
@@ -53,7 +62,8 @@ type Context struct {
 // timeouts, and distributed tracing.
 func NewContext(w http.ResponseWriter, r *http.Request) *Context {
 	ctx := contextPool.Get().(*Context)
-	ctx.Writer = w
+	ctx.writer.reset(w)
+	ctx.Writer = &ctx.writer
 	ctx.Request = r
 	return ctx
 }
@@ -61,7 +71,10 @@ func NewContext(w http.ResponseWriter, r *http.Request) *Context {
 // Reset the context for reuse.
 func (c *Context) reset() {
 	c.Writer = nil
+	c.writer.reset(nil)
 	c.Request = nil
+	c.router = nil
+	c.locale = ""
 
 	// Strategy: Keep maps allocated if they're small (≤8 entries = 1 bucket)
 	// Only recreate if they grew too large (to prevent memory bloat from pooling huge maps)
@@ -122,27 +135,53 @@ func (c *Context) Query(name string) string {
 }
 
 // Bind and validate query parameters using a schema to a struct.
+// Fields tagged `header` or `cookie` are bound from the request's headers and cookies.
 func (c *Context) BindAndValidateQuery(target any, schema *Schema) error {
-	return ValidateQuery(c.Request.URL.Query(), target, schema)
+	doc, err := bindQuery(c.Request.URL.Query(), target, schema)
+	if doc != nil && schema.hasHeaders {
+		if headerErr := bindHeaders(c.Request, target, schema, doc); headerErr != nil {
+			var errs, headerErrs ValidationErrors
+			if !errors.As(headerErr, &headerErrs) {
+				return headerErr
+			}
+			if err == nil || errors.As(err, &errs) {
+				err = append(errs, headerErrs...)
+			}
+		}
+	}
+	return validateTarget(target, schema, doc, err, c.rules())
+}
+
+// Bind request headers and cookies to a struct using the "header" and "cookie" tags and validate it using a schema.
+func (c *Context) BindAndValidateHeaders(target any, schema *Schema) error {
+	doc := make(map[string]any)
+	return validateTarget(target, schema, doc, bindHeaders(c.Request, target, schema, doc), c.rules())
 }
 
 // Bind and validate JSON using a schema to a struct.
 func (c *Context) BindAndValidateJSON(target any, schema *Schema) error {
-	body, err := io.ReadAll(c.Request.Body)
-	if err != nil {
-		return err
-	}
+	doc, err := decodeJSONDocument(c.Request.Body, target, schema)
+	return validateTarget(target, schema, doc, err, c.rules())
+}
 
-	return ValidateJSON(body, target, schema)
+// Bind path parameters to a struct using the "path" tag and validate it using a schema.
+func (c *Context) BindAndValidatePathParams(target any, schema *Schema) error {
+	return validateTarget(target, schema, nil, populatePathParams(c.PathParams, target), c.rules())
 }
 
 // Set writer with standardized validation error response.
+// Messages are translated into the request's Locale when a message catalog is registered for it.
 // Returns (nil, 0, nil) to signal the handler that the response has been written.
 func (c *Context) SendValidationError(errors ValidationErrors) (any, int, error) {
+	locale := resolveLocale(c.Locale())
+	message := "Request validation failed"
+	if localized, ok := lookupMessage(locale, "validation_failed"); ok {
+		message = localized
+	}
 	return c.JSON(http.StatusBadRequest, map[string]any{
 		"error":   "validation_failed",
-		"message": "Request validation failed",
-		"details": errors,
+		"message": message,
+		"details": errors.Localize(locale),
 	})
 }
 
@@ -0,0 +1,381 @@
+package nimbus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// TestingT is the part of testing.TB used by ContractTest
+type TestingT interface {
+	Errorf(format string, args ...any)
+}
+
+// ContractTest returns a middleware for tests that checks live traffic against the router's OpenAPI spec
+// and reports mismatches with t.Errorf:
+//
+//   - requests the server accepts (2xx) with parameters or a body the spec doesn't allow
+//   - responses with a status or body the spec doesn't document
+//   - routes missing from the spec
+//
+// Requests rejected by the server aren't reported, so tests can still send invalid requests on purpose.
+// The spec is generated on the first request (so routes registered later are included) and linted
+// (see OpenAPISpec.Lint). Add it with Router.Use before other middleware so their responses are checked.
+//
+// Example:
+//
+//	func TestUsersAPI(t *testing.T) {
+//	    router := newRouter()
+//	    router.Use(router.ContractTest(t, apiConfig))
+//	    ...
+//	}
+func (r *Router) ContractTest(t TestingT, config OpenAPIConfig) Middleware {
+	var spec *OpenAPISpec
+	var specOnce sync.Once
+
+	return func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			specOnce.Do(func() {
+				spec = r.GenerateOpenAPI(config)
+				for _, issue := range spec.Lint() {
+					t.Errorf("nimbus: invalid OpenAPI spec: %s", issue)
+				}
+			})
+
+			method, path := ctx.Request.Method, ctx.Request.URL.Path
+			op, pathItem := spec.findOperation(method, path, ctx.PathParams)
+			var requestIssues []string
+			if op != nil {
+				requestIssues = spec.checkRequest(ctx, op, pathItem)
+			}
+
+			// Record the response, rendering it here so the body sent to the client is checked
+			recorder := &contractRecorder{ResponseWriter: ctx.Writer}
+			ctx.Writer = recorder
+			data, statusCode, err := next(ctx)
+			if statusCode != 0 || err != nil {
+				r.executeHandler(ctx, func(*Context) (any, int, error) { return data, statusCode, err })
+			}
+			ctx.Writer = recorder.ResponseWriter
+
+			status := recorder.Status()
+			if op == nil {
+				if status != http.StatusNotFound && status != http.StatusMethodNotAllowed {
+					t.Errorf("nimbus: contract violation: %s %s: route isn't documented", method, path)
+				}
+				return nil, 0, nil
+			}
+
+			var issues []string
+			if status >= 200 && status < 300 {
+				issues = requestIssues
+			}
+			if recorder.Written() {
+				issues = append(issues, spec.checkResponse(op, status, recorder.Header(), recorder.body.Bytes())...)
+			}
+			for _, issue := range issues {
+				t.Errorf("nimbus: contract violation: %s %s: %s", method, path, issue)
+			}
+			return nil, 0, nil
+		}
+	}
+}
+
+// contractRecorder records the response body for ContractTest
+type contractRecorder struct {
+	ResponseWriter
+	body bytes.Buffer
+}
+
+// Write writes and records body bytes
+func (w *contractRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// ReadFrom copies from r through Write so the body is recorded
+func (w *contractRecorder) ReadFrom(r io.Reader) (int64, error) {
+	return io.Copy(struct{ io.Writer }{w}, r)
+}
+
+// findOperation returns the operation documenting a request and its path item (nil if undocumented).
+// The path template is matched by substituting the request's path parameters.
+func (spec *OpenAPISpec) findOperation(method, path string, params map[string]string) (*OpenAPIOperation, *OpenAPIPath) {
+	for _, template := range slices.Sorted(maps.Keys(spec.Paths)) {
+		matches := templateParamRegex.FindAllStringSubmatch(template, -1)
+		if len(matches) != len(params) {
+			continue
+		}
+		expanded := template
+		for _, match := range matches {
+			value, ok := params[match[1]]
+			if !ok {
+				expanded = ""
+				break
+			}
+			expanded = strings.Replace(expanded, match[0], value, 1)
+		}
+		if expanded != path && strings.ReplaceAll(expanded, "//", "/") != path {
+			continue
+		}
+
+		item := spec.Paths[template]
+		if slot := item.operation(method); slot != nil && *slot != nil {
+			return *slot, &item
+		}
+	}
+	return nil, nil
+}
+
+// checkRequest checks a request's parameters and body against its operation.
+// The body is read and restored for the handler.
+func (spec *OpenAPISpec) checkRequest(ctx *Context, op *OpenAPIOperation, item *OpenAPIPath) []string {
+	var issues []string
+	for _, param := range append(slices.Clone(item.Parameters), op.Parameters...) {
+		issues = append(issues, spec.checkParameter(ctx, param)...)
+	}
+
+	if op.RequestBody == nil || ctx.Request.Body == nil {
+		return issues
+	}
+	body, err := io.ReadAll(ctx.Request.Body)
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return append(issues, fmt.Sprintf("reading request body: %v", err))
+	}
+	if len(body) == 0 {
+		if op.RequestBody.Required {
+			issues = append(issues, "request body is required")
+		}
+		return issues
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request.Header.Get("Content-Type"))
+	if mediaType == "" {
+		mediaType = MIMEApplicationJSON
+	}
+	content, ok := op.RequestBody.Content[mediaType]
+	if !ok {
+		return append(issues, fmt.Sprintf("request content type %s isn't documented", mediaType))
+	}
+	if mediaType == MIMEApplicationJSON {
+		issues = append(issues, spec.checkJSON("request body", body, content.Schema)...)
+	}
+	return issues
+}
+
+// checkParameter checks the value of a path, query, header or cookie parameter
+func (spec *OpenAPISpec) checkParameter(ctx *Context, param OpenAPIParameter) []string {
+	var values []string
+	switch param.In {
+	case "path":
+		if value, ok := ctx.PathParams[param.Name]; ok {
+			values = []string{value}
+		}
+	case "query":
+		values = ctx.Request.URL.Query()[param.Name]
+	case "header":
+		for _, value := range ctx.Request.Header.Values(param.Name) {
+			values = append(values, strings.Split(value, ",")...)
+		}
+	case "cookie":
+		if cookie, err := ctx.Request.Cookie(param.Name); err == nil {
+			values = []string{cookie.Value}
+		}
+	}
+
+	location := fmt.Sprintf("%s parameter %s", param.In, param.Name)
+	if len(values) == 0 {
+		if param.Required {
+			return []string{location + " is required"}
+		}
+		return nil
+	}
+
+	schema := spec.resolveSchema(param.Schema)
+	if schema == nil {
+		return nil
+	}
+	var issues []string
+	switch schema.Type {
+	case "object":
+		// deepObject parameters (filter[key]=value) are bound by key and not checked
+	case "array":
+		items := make([]any, len(values))
+		for i, raw := range values {
+			items[i] = parameterValue(strings.TrimSpace(raw), spec.resolveSchema(schema.Items))
+		}
+		spec.checkValue(location, items, schema, &issues)
+	default:
+		spec.checkValue(location, parameterValue(values[0], schema), schema, &issues)
+	}
+	return issues
+}
+
+// parameterValue converts a raw parameter to the JSON value it represents, so it's checked like
+// a body value. Values that don't parse are kept as strings and fail the type check.
+func parameterValue(raw string, schema *OpenAPISchema) any {
+	if schema == nil {
+		return raw
+	}
+	switch schema.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err == nil {
+			return json.Number(raw)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
+
+// checkResponse checks a response's status and JSON body against its operation.
+// A 204 is accepted for operations documenting a 200, since nil data is sent as 204 No Content.
+func (spec *OpenAPISpec) checkResponse(op *OpenAPIOperation, status int, header http.Header, body []byte) []string {
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+	if !ok {
+		if _, documented := op.Responses["200"]; status == http.StatusNoContent && documented {
+			return nil
+		}
+		return []string{fmt.Sprintf("response status %d isn't documented", status)}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	content, ok := resp.Content[MIMEApplicationJSON]
+	if len(body) == 0 || mediaType != MIMEApplicationJSON || !ok {
+		return nil
+	}
+	return spec.checkJSON(fmt.Sprintf("response %d body", status), body, content.Schema)
+}
+
+// checkJSON checks a JSON document against a schema
+func (spec *OpenAPISpec) checkJSON(location string, data []byte, schema *OpenAPISchema) []string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return []string{fmt.Sprintf("%s isn't valid JSON: %v", location, err)}
+	}
+
+	var issues []string
+	spec.checkValue(location, value, schema, &issues)
+	return issues
+}
+
+// resolveSchema follows a $ref to its component schema (nil if it doesn't resolve)
+func (spec *OpenAPISpec) resolveSchema(schema *OpenAPISchema) *OpenAPISchema {
+	for schema != nil && schema.Ref != "" {
+		schema = spec.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+// checkValue checks a decoded JSON value against a schema, appending a message for each mismatch
+func (spec *OpenAPISpec) checkValue(location string, value any, schema *OpenAPISchema, issues *[]string) {
+	schema = spec.resolveSchema(schema)
+	if schema == nil {
+		return
+	}
+	report := func(format string, args ...any) {
+		*issues = append(*issues, location+" "+fmt.Sprintf(format, args...))
+	}
+
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			report("is null, expected %s", schema.Type)
+		}
+		return
+	}
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(allowed any) bool {
+		return fmt.Sprint(allowed) == fmt.Sprint(value)
+	}) {
+		report("is %v, expected one of %v", value, schema.Enum)
+	}
+
+	switch v := value.(type) {
+	case string:
+		if schema.Type != "" && schema.Type != "string" {
+			report("is a string, expected %s", schema.Type)
+			return
+		}
+		length := utf8.RuneCountInString(v)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			report("is shorter than %d characters", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			report("is longer than %d characters", *schema.MaxLength)
+		}
+		if schema.Pattern != "" {
+			if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(v) {
+				report("doesn't match pattern %s", schema.Pattern)
+			}
+		}
+	case json.Number:
+		n, _ := v.Float64()
+		if schema.Type == "integer" {
+			if _, err := v.Int64(); err != nil {
+				report("is %s, expected integer", v)
+				return
+			}
+		} else if schema.Type != "" && schema.Type != "number" {
+			report("is a number, expected %s", schema.Type)
+			return
+		}
+		if schema.Minimum != nil && (n < *schema.Minimum || schema.ExclusiveMinimum && n == *schema.Minimum) {
+			report("is %s, below the minimum %v", v, *schema.Minimum)
+		}
+		if schema.Maximum != nil && (n > *schema.Maximum || schema.ExclusiveMaximum && n == *schema.Maximum) {
+			report("is %s, above the maximum %v", v, *schema.Maximum)
+		}
+	case bool:
+		if schema.Type != "" && schema.Type != "boolean" {
+			report("is a boolean, expected %s", schema.Type)
+		}
+	case []any:
+		if schema.Type != "" && schema.Type != "array" {
+			report("is an array, expected %s", schema.Type)
+			return
+		}
+		if schema.MinItems != nil && len(v) < *schema.MinItems {
+			report("has fewer than %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(v) > *schema.MaxItems {
+			report("has more than %d items", *schema.MaxItems)
+		}
+		for i, item := range v {
+			spec.checkValue(fmt.Sprintf("%s[%d]", location, i), item, schema.Items, issues)
+		}
+	case map[string]any:
+		if schema.Type != "" && schema.Type != "object" {
+			report("is an object, expected %s", schema.Type)
+			return
+		}
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				report("is missing required property %s", name)
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			propSchema, ok := schema.Properties[name]
+			if !ok {
+				propSchema = schema.AdditionalProperties
+			}
+			spec.checkValue(location+"."+name, v[name], propSchema, issues)
+		}
+	}
+}
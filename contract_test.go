@@ -0,0 +1,90 @@
+package nimbus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// contractT records the errors reported by ContractTest
+type contractT struct {
+	errors []string
+}
+
+func (t *contractT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+type TestContractPrice struct {
+	Amount float64 `json:"amount" validate:"min=0"`
+	Label  string  `json:"label" validate:"required"`
+}
+
+func newTestContractRouter(ct *contractT) *Router {
+	router := newTestHandleRouter()
+	router.AddRoute(http.MethodGet, "/prices/:id", WithTypedResponse(
+		func(ctx *Context, req *TypedRequest[TestOrderParams, struct{}, struct{}]) (*TestContractPrice, int, error) {
+			if req.Params.ID == 99 {
+				return &TestContractPrice{Amount: -1, Label: "refund"}, http.StatusOK, nil
+			}
+			return &TestContractPrice{Amount: 9.99, Label: "standard"}, http.StatusOK, nil
+		}, testOrderParamsValidator, nil, nil))
+	router.AddRoute(http.MethodGet, "/search", func(ctx *Context) (any, int, error) {
+		return []string{"widget"}, http.StatusOK, nil
+	})
+	router.Route(http.MethodGet, "/search").WithDoc(RouteMetadata{QuerySchema: NewSchema(TestAPIQuery{})})
+	router.AddRoute(http.MethodPost, "/search", func(ctx *Context) (any, int, error) {
+		return nil, http.StatusAccepted, nil
+	})
+	router.Use(router.ContractTest(ct, OpenAPIConfig{Title: "Contract", Version: "1.0.0"}))
+	return router
+}
+
+func TestContractTest(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		status   int
+		expected []string // Substrings of the reported violations
+	}{
+		{"valid request and response", http.MethodPut, "/items/7?dry_run=true", `{"name":"Widget"}`, http.StatusOK, nil},
+		{"rejected invalid request", http.MethodPut, "/items/0", `{"name":"W"}`, http.StatusBadRequest, nil},
+		{"error response", http.MethodPut, "/items/404", `{"name":"Widget"}`, http.StatusNotFound, []string{"response status 404 isn't documented"}},
+		{"typed response", http.MethodGet, "/prices/1", "", http.StatusOK, nil},
+		{"drifted response", http.MethodGet, "/prices/99", "", http.StatusOK, []string{"response 200 body.data.amount is -1, below the minimum 0"}},
+		{"accepted invalid request", http.MethodGet, "/search?page=first&limit=500", "", http.StatusOK, []string{
+			"query parameter page is a string, expected integer",
+			"query parameter limit is 500, above the maximum 100",
+		}},
+		{"undocumented status", http.MethodPost, "/search", "", http.StatusAccepted, []string{"response status 202 isn't documented"}},
+		{"unknown route", http.MethodGet, "/missing", "", http.StatusNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := &contractT{}
+			router := newTestContractRouter(ct)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("X-Tenant-ID", "acme")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if len(ct.errors) != len(tt.expected) {
+				t.Fatalf("expected %d violations, got %v", len(tt.expected), ct.errors)
+			}
+			for i, expected := range tt.expected {
+				if !strings.Contains(ct.errors[i], expected) {
+					t.Errorf("expected violation containing %q, got %q", expected, ct.errors[i])
+				}
+			}
+		})
+	}
+}
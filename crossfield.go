@@ -0,0 +1,360 @@
+package nimbus
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// crossFieldRule compares a field against a sibling field (eqfield, nefield, gtfield, gtefield, ltfield, ltefield)
+type crossFieldRule struct {
+	tag   string
+	field string
+}
+
+// conditionalRule makes a field required depending on sibling fields
+// (required_if, required_unless, required_with, required_without)
+type conditionalRule struct {
+	tag    string
+	fields []string
+	values []string // Expected values for required_if/required_unless (parallel to fields)
+}
+
+var crossFieldDescriptions = map[string]string{
+	"eqfield":  "equal to",
+	"nefield":  "different from",
+	"gtfield":  "greater than",
+	"gtefield": "greater than or equal to",
+	"ltfield":  "less than",
+	"ltefield": "less than or equal to",
+}
+
+// parseConditionalRule parses "required_if=status active role admin" style parameters.
+// required_if/required_unless take field/value pairs; required_with/required_without take field names.
+func parseConditionalRule(tag, param string) conditionalRule {
+	parts := strings.Fields(param)
+	rule := conditionalRule{tag: tag}
+
+	if tag == "required_if" || tag == "required_unless" {
+		if len(parts)%2 != 0 {
+			panic(fmt.Sprintf("%s expects field/value pairs, got %q", tag, param))
+		}
+		for i := 0; i < len(parts); i += 2 {
+			rule.fields = append(rule.fields, parts[i])
+			rule.values = append(rule.values, parts[i+1])
+		}
+		return rule
+	}
+
+	rule.fields = parts
+	return rule
+}
+
+// StructValidator validates a whole struct and reports errors against its fields.
+// Field names in the returned errors are relative to the struct; they are prefixed
+// with the struct's path when it is nested (e.g., "shipping.zip").
+type StructValidator func(data any) ValidationErrors
+
+// AddStructValidator adds a struct-level validation function, run after field validation.
+// Use it for rules involving several fields that can't be expressed with tags.
+//
+// Example:
+//
+//	schema.AddStructValidator(func(data any) nimbus.ValidationErrors {
+//	    r := data.(*DateRange)
+//	    if r.End.Sub(r.Start) > 30*24*time.Hour {
+//	        return nimbus.ValidationErrors{{Field: "end", Tag: "range", Message: "range must be at most 30 days"}}
+//	    }
+//	    return nil
+//	})
+func (s *Schema) AddStructValidator(validator StructValidator) *Schema {
+	s.structValidators = append(s.structValidators, validator)
+	return s
+}
+
+// AddStructValidator adds a typed struct-level validation function to the validator's schema.
+//
+// Example:
+//
+//	var dateRangeValidator = nimbus.NewValidator(&DateRange{}).
+//	    AddStructValidator(func(r *DateRange) nimbus.ValidationErrors { ... })
+func (v *Validator[T]) AddStructValidator(validator func(*T) ValidationErrors) *Validator[T] {
+	v.Schema.AddStructValidator(func(data any) ValidationErrors {
+		if typed, ok := data.(*T); ok {
+			return validator(typed)
+		}
+		return nil
+	})
+	return v
+}
+
+// checkFieldReferences panics if a cross-field or conditional rule references an unknown field,
+// so typos are caught when the schema is built rather than silently ignored.
+func (s *Schema) checkFieldReferences() {
+	for _, fieldName := range s.order {
+		rule := s.fields[fieldName]
+		for _, cond := range rule.conditions {
+			for _, ref := range cond.fields {
+				if _, ok := s.siblingField(ref); !ok {
+					panic(fmt.Sprintf("field %s: %s references unknown field %s", fieldName, cond.tag, ref))
+				}
+			}
+		}
+		for _, cmp := range rule.crossFields {
+			if _, ok := s.siblingField(cmp.field); !ok {
+				panic(fmt.Sprintf("field %s: %s references unknown field %s", fieldName, cmp.tag, cmp.field))
+			}
+		}
+	}
+}
+
+// siblingField resolves the index of a referenced field by schema name (JSON name) or Go field name
+func (s *Schema) siblingField(name string) ([]int, bool) {
+	if rule, ok := s.fields[name]; ok {
+		return rule.index, true
+	}
+	if field, ok := s.structType.FieldByName(name); ok {
+		return field.Index, true
+	}
+	return nil, false
+}
+
+// siblingValue returns the value of a referenced field
+func (s *Schema) siblingValue(v reflect.Value, name string) reflect.Value {
+	index, _ := s.siblingField(name)
+	return v.FieldByIndex(index)
+}
+
+// conditionallyRequired returns the first conditional rule that makes the field required, if any
+func (s *Schema) conditionallyRequired(v reflect.Value, rule fieldRule) (conditionalRule, bool) {
+	for _, cond := range rule.conditions {
+		if s.conditionMet(v, cond) {
+			return cond, true
+		}
+	}
+	return conditionalRule{}, false
+}
+
+func (s *Schema) conditionMet(v reflect.Value, cond conditionalRule) bool {
+	switch cond.tag {
+	case "required_if", "required_unless":
+		// All field/value pairs must match for required_if
+		matched := true
+		for i, field := range cond.fields {
+			if !valueEquals(s.siblingValue(v, field), cond.values[i]) {
+				matched = false
+				break
+			}
+		}
+		return matched == (cond.tag == "required_if")
+	case "required_with":
+		// Any of the fields is present
+		for _, field := range cond.fields {
+			if !s.siblingValue(v, field).IsZero() {
+				return true
+			}
+		}
+		return false
+	case "required_without":
+		// Any of the fields is missing
+		for _, field := range cond.fields {
+			if s.siblingValue(v, field).IsZero() {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// conditionalMessage describes why a field is required
+func conditionalMessage(fieldName string, cond conditionalRule) string {
+	switch cond.tag {
+	case "required_if":
+		conditions := make([]string, len(cond.fields))
+		for i, field := range cond.fields {
+			conditions[i] = fmt.Sprintf("%s is %s", field, cond.values[i])
+		}
+		return fmt.Sprintf("%s is required when %s", fieldName, strings.Join(conditions, " and "))
+	case "required_unless":
+		conditions := make([]string, len(cond.fields))
+		for i, field := range cond.fields {
+			conditions[i] = fmt.Sprintf("%s is %s", field, cond.values[i])
+		}
+		return fmt.Sprintf("%s is required unless %s", fieldName, strings.Join(conditions, " and "))
+	case "required_with":
+		return fmt.Sprintf("%s is required when %s is present", fieldName, strings.Join(cond.fields, " or "))
+	default:
+		return fmt.Sprintf("%s is required when %s is missing", fieldName, strings.Join(cond.fields, " or "))
+	}
+}
+
+// validateCrossFields compares a field against its referenced sibling fields
+func (s *Schema) validateCrossFields(fieldPath string, v, fieldValue reflect.Value, rule fieldRule) ValidationErrors {
+	var errors ValidationErrors
+
+	for _, cmp := range rule.crossFields {
+		other := s.siblingValue(v, cmp.field)
+
+		var ok bool
+		switch cmp.tag {
+		case "eqfield":
+			ok = reflect.DeepEqual(indirectValue(fieldValue), indirectValue(other))
+		case "nefield":
+			ok = !reflect.DeepEqual(indirectValue(fieldValue), indirectValue(other))
+		default:
+			result, comparable := compareValues(fieldValue, other)
+			if !comparable {
+				// The other field is empty or of an incomparable type; nothing to compare against
+				continue
+			}
+			switch cmp.tag {
+			case "gtfield":
+				ok = result > 0
+			case "gtefield":
+				ok = result >= 0
+			case "ltfield":
+				ok = result < 0
+			case "ltefield":
+				ok = result <= 0
+			}
+		}
+
+		if !ok {
+			errors = append(errors, ValidationError{
+				Field:   fieldPath,
+				Value:   indirectValue(fieldValue),
+				Tag:     cmp.tag,
+				Message: fmt.Sprintf("%s must be %s %s", fieldPath, crossFieldDescriptions[cmp.tag], cmp.field),
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateStructLevel runs struct validators and nested ValidatedStruct implementations.
+// The top-level ValidatedStruct is run by the binding functions instead.
+func (s *Schema) validateStructLevel(path string, v reflect.Value) ValidationErrors {
+	if len(s.structValidators) == 0 && path == "" {
+		return nil
+	}
+
+	// Struct validators receive a pointer, matching how handlers receive bound structs
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	if v.CanAddr() {
+		ptr = v.Addr()
+	}
+	data := ptr.Interface()
+
+	var errors ValidationErrors
+	for _, validator := range s.structValidators {
+		errors = append(errors, prefixErrors(path, validator(data))...)
+	}
+
+	if path != "" {
+		if validated, ok := data.(ValidatedStruct); ok {
+			if err := validated.Validate(); err != nil {
+				if validationErrs, ok := err.(ValidationErrors); ok {
+					errors = append(errors, prefixErrors(path, validationErrs)...)
+				} else {
+					errors = append(errors, ValidationError{
+						Field:   path,
+						Tag:     "struct",
+						Message: err.Error(),
+					})
+				}
+			}
+		}
+	}
+
+	return errors
+}
+
+// prefixErrors prefixes error field names with a nested struct's path
+func prefixErrors(path string, errs ValidationErrors) ValidationErrors {
+	if path == "" {
+		return errs
+	}
+	prefixed := make(ValidationErrors, len(errs))
+	for i, err := range errs {
+		if err.Field == "" {
+			err.Field = path
+		} else {
+			err.Field = path + "." + err.Field
+		}
+		prefixed[i] = err
+	}
+	return prefixed
+}
+
+// indirectValue dereferences pointers and Optional values and returns the underlying value
+// (nil for nil pointers and unset or null Optionals)
+func indirectValue(v reflect.Value) any {
+	if v.IsValid() && v.CanInterface() {
+		if optional, ok := v.Interface().(optionalValue); ok {
+			if optional.presence() != presencePresent {
+				return nil
+			}
+			v = v.Field(0)
+		}
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// valueEquals compares a field value to a tag parameter by its string form
+func valueEquals(v reflect.Value, expected string) bool {
+	value := indirectValue(v)
+	if value == nil {
+		return expected == ""
+	}
+	return fmt.Sprint(value) == expected
+}
+
+// compareValues compares numbers, strings, durations and times.
+// Returns false if either value is nil or the values can't be ordered.
+func compareValues(a, b reflect.Value) (int, bool) {
+	av, bv := indirectValue(a), indirectValue(b)
+	if av == nil || bv == nil {
+		return 0, false
+	}
+
+	if at, ok := av.(time.Time); ok {
+		if bt, ok := bv.(time.Time); ok {
+			return at.Compare(bt), true
+		}
+		return 0, false
+	}
+
+	if af, ok := toFloat(av); ok {
+		if bf, ok := toFloat(bv); ok {
+			switch {
+			case af < bf:
+				return -1, true
+			case af > bf:
+				return 1, true
+			}
+			return 0, true
+		}
+		return 0, false
+	}
+
+	if as, ok := av.(string); ok {
+		if bs, ok := bv.(string); ok {
+			return strings.Compare(as, bs), true
+		}
+	}
+
+	return 0, false
+}
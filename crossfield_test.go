@@ -0,0 +1,208 @@
+package nimbus
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type TestShipmentRequest struct {
+	Method      string    `json:"method" validate:"required,oneof=pickup|delivery"`
+	Address     string    `json:"address" validate:"required_if=method delivery"`
+	Email       string    `json:"email" validate:"required_without=Phone"`
+	Phone       string    `json:"phone"`
+	GiftMessage string    `json:"gift_message"`
+	GiftWrap    string    `json:"gift_wrap" validate:"required_with=gift_message"`
+	Password    string    `json:"password" validate:"minlen=8"`
+	Confirm     string    `json:"confirm" validate:"eqfield=password"`
+	MinWeight   float64   `json:"min_weight"`
+	MaxWeight   float64   `json:"max_weight" validate:"gtefield=min_weight"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end" validate:"gtfield=start"`
+}
+
+func validShipmentRequest() TestShipmentRequest {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return TestShipmentRequest{
+		Method:    "delivery",
+		Address:   "1 Main St",
+		Email:     "a@example.com",
+		Password:  "secret123",
+		Confirm:   "secret123",
+		MinWeight: 1,
+		MaxWeight: 1,
+		Start:     start,
+		End:       start.Add(time.Hour),
+	}
+}
+
+func TestSchema_Validate_CrossField(t *testing.T) {
+	schema := NewSchema(TestShipmentRequest{})
+
+	if errs := schema.Validate(validShipmentRequest()); len(errs) > 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(r *TestShipmentRequest)
+		field    string
+		expected string // Empty means valid
+	}{
+		{"required_if met", func(r *TestShipmentRequest) { r.Address = "" }, "address", "required_if"},
+		{"required_if not met", func(r *TestShipmentRequest) { r.Method, r.Address = "pickup", "" }, "", ""},
+		{"required_without met", func(r *TestShipmentRequest) { r.Email = "" }, "email", "required_without"},
+		{"required_without not met", func(r *TestShipmentRequest) { r.Email, r.Phone = "", "555-0100" }, "", ""},
+		{"required_with met", func(r *TestShipmentRequest) { r.GiftMessage = "Happy birthday" }, "gift_wrap", "required_with"},
+		{"eqfield", func(r *TestShipmentRequest) { r.Confirm = "secret124" }, "confirm", "eqfield"},
+		{"gtefield", func(r *TestShipmentRequest) { r.MaxWeight = 0.5 }, "max_weight", "gtefield"},
+		{"gtfield time", func(r *TestShipmentRequest) { r.End = r.Start }, "end", "gtfield"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validShipmentRequest()
+			tt.modify(&req)
+
+			errs := schema.Validate(req)
+			if tt.expected == "" {
+				if len(errs) > 0 {
+					t.Fatalf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %v", errs)
+			}
+			if errs[0].Field != tt.field || errs[0].Tag != tt.expected {
+				t.Errorf("expected %s/%s, got %s/%s: %s", tt.field, tt.expected, errs[0].Field, errs[0].Tag, errs[0].Message)
+			}
+		})
+	}
+}
+
+func TestSchema_Validate_CrossFieldMessages(t *testing.T) {
+	schema := NewSchema(TestShipmentRequest{})
+
+	req := validShipmentRequest()
+	req.Address = ""
+	req.Confirm = "other"
+
+	expected := map[string]string{
+		"address": "address is required when method is delivery",
+		"confirm": "confirm must be equal to password",
+	}
+	errs := schema.Validate(req)
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+	for _, err := range errs {
+		if expected[err.Field] != err.Message {
+			t.Errorf("unexpected message for %s: %q", err.Field, err.Message)
+		}
+	}
+}
+
+func TestNewSchema_UnknownFieldReference(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for unknown referenced field")
+		}
+	}()
+
+	type badRequest struct {
+		Confirm string `json:"confirm" validate:"eqfield=passwrd"`
+	}
+	NewSchema(badRequest{})
+}
+
+type TestBooking struct {
+	Guest string    `json:"guest" validate:"required"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func TestValidator_AddStructValidator(t *testing.T) {
+	validator := NewValidator(&TestBooking{}).
+		AddStructValidator(func(b *TestBooking) ValidationErrors {
+			if b.End.Sub(b.Start) > 30*24*time.Hour {
+				return ValidationErrors{{Field: "end", Tag: "max_range", Message: "stay must be at most 30 days"}}
+			}
+			return nil
+		})
+	validator.Schema.AddStructValidator(func(data any) ValidationErrors {
+		if strings.EqualFold(data.(*TestBooking).Guest, "banned") {
+			return ValidationErrors{{Field: "guest", Tag: "banned", Message: "guest is not allowed"}}
+		}
+		return nil
+	})
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if errs := validator.Schema.Validate(TestBooking{Guest: "Alice", Start: start, End: start.AddDate(0, 0, 7)}); len(errs) > 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+
+	errs := validator.Schema.Validate(TestBooking{Guest: "banned", Start: start, End: start.AddDate(0, 2, 0)})
+	expected := map[string]string{"guest": "banned", "end": "max_range"}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+	for _, err := range errs {
+		if expected[err.Field] != err.Tag {
+			t.Errorf("unexpected error %s/%s", err.Field, err.Tag)
+		}
+	}
+}
+
+type TestValidatedRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+func (r *TestValidatedRange) Validate() error {
+	if r.To < r.From {
+		return ValidationErrors{{Field: "to", Tag: "gtefield", Message: "to must not be before from"}}
+	}
+	if r.To-r.From > 100 {
+		return errors.New("range must span at most 100 pages")
+	}
+	return nil
+}
+
+type TestPagedQuery struct {
+	Pages  TestValidatedRange   `json:"pages"`
+	Extras []TestValidatedRange `json:"extras"`
+}
+
+func TestWithTyped_StructValidator(t *testing.T) {
+	validator := NewValidator(&TestPagedQuery{})
+
+	router := NewRouter()
+	router.AddRoute(http.MethodPost, "/report", WithTyped(
+		func(ctx *Context, req *TypedRequest[struct{}, TestPagedQuery, struct{}]) (any, int, error) {
+			return req.Body, 200, nil
+		}, nil, validator, nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/report", strings.NewReader(`{"pages":{"from":5,"to":1},"extras":[{"from":1,"to":2},{"from":1,"to":500}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	details := decodeValidationDetails(t, w)
+	expected := map[string]string{"pages.to": "gtefield", "extras[1]": "struct"}
+	if len(details) != len(expected) {
+		t.Fatalf("expected %d errors, got %+v", len(expected), details)
+	}
+	for _, detail := range details {
+		if expected[detail.Field] != detail.Tag {
+			t.Errorf("unexpected error %s/%s: %s", detail.Field, detail.Tag, detail.Message)
+		}
+	}
+}
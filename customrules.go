@@ -0,0 +1,181 @@
+package nimbus
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// RuleFunc validates a field for a named custom rule.
+// field is the field's value (pointers are not dereferenced) and param is the rule's
+// parameter ("5" for divisibleby=5, "" when the tag has no parameter).
+// Custom rules only run on non-empty values; combine them with required to enforce presence.
+type RuleFunc func(field reflect.Value, param string) error
+
+// CustomRule is a named validation rule registered with RegisterRule or Router.RegisterRule.
+type CustomRule struct {
+	name     string
+	validate RuleFunc
+	openAPI  func(schema *OpenAPISchema, param string)
+}
+
+// WithOpenAPI sets a function that documents the rule in generated OpenAPI schemas,
+// e.g., by setting a pattern or multipleOf. Router rules are documented in that router's spec.
+//
+// Example:
+//
+//	nimbus.RegisterRule("sku", validateSKU).WithOpenAPI(func(schema *nimbus.OpenAPISchema, _ string) {
+//	    schema.Pattern = `^[A-Z]{3}-\d{4}$`
+//	})
+func (r *CustomRule) WithOpenAPI(fn func(schema *OpenAPISchema, param string)) *CustomRule {
+	r.openAPI = fn
+	return r
+}
+
+// customRuleRef is a use of a custom rule in a validation tag
+type customRuleRef struct {
+	name  string
+	param string
+}
+
+// ruleRegistry maps rule names to custom rules
+type ruleRegistry struct {
+	mu sync.RWMutex
+	m  map[string]*CustomRule
+}
+
+// globalRules holds rules registered with RegisterRule
+var globalRules ruleRegistry
+
+func (r *ruleRegistry) register(name string, fn RuleFunc) *CustomRule {
+	if fn == nil {
+		panic("nimbus: RegisterRule func is nil")
+	}
+	if _, builtin := builtinRules[name]; builtin {
+		panic(fmt.Sprintf("nimbus: RegisterRule %q conflicts with a built-in rule", name))
+	}
+
+	rule := &CustomRule{name: name, validate: fn}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.m == nil {
+		r.m = make(map[string]*CustomRule)
+	}
+	r.m[name] = rule
+	return rule
+}
+
+func (r *ruleRegistry) lookup(name string) *CustomRule {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.m[name]
+}
+
+// lookupRule finds a custom rule in a router's registry (if any), then the global one
+func lookupRule(rules *ruleRegistry, name string) *CustomRule {
+	if rule := rules.lookup(name); rule != nil {
+		return rule
+	}
+	return globalRules.lookup(name)
+}
+
+// RegisterRule registers a named validation rule usable in any schema's tags,
+// e.g., `validate:"required,sku"` or `validate:"divisibleby=5"`.
+// Rules are looked up when validating, so schemas may be created before their rules are registered.
+// Register rules at startup, before adding the routes using them: adding a typed route (see WithTyped)
+// whose schemas use an unregistered rule panics, and so does validating a field with one.
+// Panics if name is a built-in rule.
+//
+// Example:
+//
+//	nimbus.RegisterRule("divisibleby", func(field reflect.Value, param string) error {
+//	    n, _ := strconv.ParseInt(param, 10, 64)
+//	    if field.Int()%n != 0 {
+//	        return fmt.Errorf("must be divisible by %s", param)
+//	    }
+//	    return nil
+//	})
+func RegisterRule(name string, fn RuleFunc) *CustomRule {
+	return globalRules.register(name, fn)
+}
+
+// RegisterRule registers a named validation rule for requests served by this router.
+// Router rules take precedence over global rules with the same name.
+func (r *Router) RegisterRule(name string, fn RuleFunc) *CustomRule {
+	return r.rules.register(name, fn)
+}
+
+// rules returns the custom rules of the router serving the request (nil outside a router)
+func (c *Context) rules() *ruleRegistry {
+	if c.router == nil {
+		return nil
+	}
+	return &c.router.rules
+}
+
+// validateCustomRules runs the custom rules referenced by a field's tag.
+// Panics if a rule isn't registered, e.g. for a typo like `validate:"slugg"`.
+func validateCustomRules(path string, v reflect.Value, rule fieldRule, rules *ruleRegistry) ValidationErrors {
+	var errors ValidationErrors
+	for _, ref := range rule.customRules {
+		custom := lookupRule(rules, ref.name)
+		if custom == nil {
+			panic(fmt.Sprintf("field %s: unknown validation rule %q", path, ref.name))
+		}
+		if err := custom.validate(v, ref.param); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   path,
+				Value:   v.Interface(),
+				Tag:     ref.name,
+				Param:   ref.param,
+				Message: fmt.Sprintf("%s: %v", path, err),
+			})
+		}
+	}
+	return errors
+}
+
+// applyCustomRuleSchemas lets custom rules document themselves in an OpenAPI schema.
+// rules are the router's custom rules, which take precedence over global rules (nil for global rules only).
+func applyCustomRuleSchemas(propSchema *OpenAPISchema, rule fieldRule, rules *ruleRegistry) {
+	for _, ref := range rule.customRules {
+		if custom := lookupRule(rules, ref.name); custom != nil && custom.openAPI != nil {
+			custom.openAPI(propSchema, ref.param)
+		}
+	}
+}
+
+// checkCustomRules panics if the schemas of a typed handler use a rule that isn't registered
+// with the router or globally, so typos are reported when the route is added
+func (spec *handlerSpec) checkCustomRules(rules *ruleRegistry) {
+	seen := make(map[*Schema]bool)
+	for _, schema := range []*Schema{spec.params, spec.body, spec.query} {
+		checkSchemaRules("", schema, rules, seen)
+	}
+}
+
+func checkSchemaRules(path string, schema *Schema, rules *ruleRegistry, seen map[*Schema]bool) {
+	if schema == nil || seen[schema] {
+		return
+	}
+	seen[schema] = true
+	for _, fieldName := range schema.order {
+		checkFieldRules(path+fieldName, schema.fields[fieldName], rules, seen)
+	}
+}
+
+func checkFieldRules(path string, rule fieldRule, rules *ruleRegistry, seen map[*Schema]bool) {
+	for _, ref := range rule.customRules {
+		if lookupRule(rules, ref.name) == nil {
+			panic(fmt.Sprintf("field %s: unknown validation rule %q", path, ref.name))
+		}
+	}
+	checkSchemaRules(path+".", rule.nested, rules, seen)
+	if rule.elem != nil {
+		checkFieldRules(path+"[]", *rule.elem, rules, seen)
+	}
+}
@@ -0,0 +1,261 @@
+package nimbus
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// registerTestRule registers a global rule for a test and removes it afterwards
+func registerTestRule(t *testing.T, name string, fn RuleFunc) *CustomRule {
+	t.Helper()
+	rule := RegisterRule(name, fn)
+	t.Cleanup(func() {
+		globalRules.mu.Lock()
+		defer globalRules.mu.Unlock()
+		delete(globalRules.m, name)
+	})
+	return rule
+}
+
+var skuRegex = regexp.MustCompile(`^[A-Z]{3}-\d{4}$`)
+
+func validateSKU(field reflect.Value, _ string) error {
+	if !skuRegex.MatchString(field.String()) {
+		return errors.New("must be a SKU like ABC-1234")
+	}
+	return nil
+}
+
+func validateDivisibleBy(field reflect.Value, param string) error {
+	n, err := strconv.ParseInt(param, 10, 64)
+	if err != nil || n == 0 {
+		return fmt.Errorf("invalid divisor %q", param)
+	}
+	if field.Int()%n != 0 {
+		return fmt.Errorf("must be divisible by %d", n)
+	}
+	return nil
+}
+
+type TestCustomRuleProduct struct {
+	SKU      string   `json:"sku" validate:"required,sku"`
+	Quantity int      `json:"quantity" validate:"divisibleby=5"`
+	Related  []string `json:"related" validate:"dive,sku"`
+}
+
+func TestRegisterRule(t *testing.T) {
+	// Schemas may be created before their rules are registered
+	schema := NewSchema(TestCustomRuleProduct{})
+	registerTestRule(t, "sku", validateSKU)
+	registerTestRule(t, "divisibleby", validateDivisibleBy)
+
+	tests := []struct {
+		name     string
+		product  TestCustomRuleProduct
+		expected map[string]string // field -> param
+	}{
+		{
+			name:    "valid",
+			product: TestCustomRuleProduct{SKU: "ABC-1234", Quantity: 10, Related: []string{"XYZ-0001"}},
+		},
+		{
+			name:     "missing sku is only required",
+			product:  TestCustomRuleProduct{Quantity: 5},
+			expected: map[string]string{"sku": ""},
+		},
+		{
+			name:     "invalid values",
+			product:  TestCustomRuleProduct{SKU: "abc", Quantity: 7, Related: []string{"XYZ-0001", "bad"}},
+			expected: map[string]string{"sku": "", "quantity": "5", "related[1]": ""},
+		},
+		{
+			name:    "empty values skip custom rules",
+			product: TestCustomRuleProduct{SKU: "ABC-1234"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := schema.Validate(tt.product)
+			if len(errs) != len(tt.expected) {
+				t.Fatalf("expected %d errors, got %v", len(tt.expected), errs)
+			}
+			for _, err := range errs {
+				param, ok := tt.expected[err.Field]
+				if !ok || err.Param != param {
+					t.Errorf("unexpected error %+v", err)
+				}
+			}
+		})
+	}
+
+	errs := schema.Validate(TestCustomRuleProduct{SKU: "ABC-1234", Quantity: 7})
+	if len(errs) != 1 || errs[0].Tag != "divisibleby" || errs[0].Message != "quantity: must be divisible by 5" {
+		t.Errorf("unexpected error: %v", errs)
+	}
+}
+
+func TestRegisterRule_BuiltinName(t *testing.T) {
+	for _, name := range []string{"required", "email", "dive", "eqfield"} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected RegisterRule(%q) to panic", name)
+				}
+			}()
+			RegisterRule(name, validateSKU)
+		})
+	}
+}
+
+func TestRouter_RegisterRule(t *testing.T) {
+	validator := NewValidator(&TestCustomRuleProduct{})
+
+	router := NewRouter()
+	router.RegisterRule("sku", func(field reflect.Value, _ string) error {
+		if !strings.HasPrefix(field.String(), "SKU") {
+			return errors.New("must start with SKU")
+		}
+		return nil
+	})
+	router.RegisterRule("divisibleby", validateDivisibleBy)
+	router.AddRoute(http.MethodPost, "/products", WithTyped(
+		func(ctx *Context, req *TypedRequest[struct{}, TestCustomRuleProduct, struct{}]) (any, int, error) {
+			return req.Body, 201, nil
+		}, nil, validator, nil))
+
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send(`{"sku":"SKU1"}`); w.Code != http.StatusCreated {
+		t.Errorf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := send(`{"sku":"ABC-1234"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	details := decodeValidationDetails(t, w)
+	if len(details) != 1 || details[0].Field != "sku" || details[0].Tag != "sku" {
+		t.Errorf("expected router rule error for sku, got %+v", details)
+	}
+
+	// Router rules don't apply outside the router
+	defer func() {
+		if recover() == nil {
+			t.Error("expected validating without the router's rules to panic")
+		}
+	}()
+	validator.Schema.Validate(TestCustomRuleProduct{SKU: "ABC-1234"})
+}
+
+func TestCustomRule_Unknown(t *testing.T) {
+	type Product struct {
+		Name    string   `json:"name" validate:"required,slugg"`
+		Related []string `json:"related" validate:"dive,slugg"`
+	}
+	expectPanic := func(t *testing.T, message string, fn func()) {
+		t.Helper()
+		defer func() {
+			if r := recover(); r != message {
+				t.Errorf("expected panic %q, got %v", message, r)
+			}
+		}()
+		fn()
+	}
+	handler := func(ctx *Context, req *TypedRequest[struct{}, Product, struct{}]) (any, int, error) {
+		return req.Body, http.StatusOK, nil
+	}
+
+	t.Run("validate", func(t *testing.T) {
+		expectPanic(t, `field name: unknown validation rule "slugg"`, func() {
+			NewSchema(Product{}).Validate(Product{Name: "shoe"})
+		})
+	})
+
+	t.Run("add route", func(t *testing.T) {
+		expectPanic(t, `field name: unknown validation rule "slugg"`, func() {
+			NewRouter().AddRoute(http.MethodPost, "/products", WithTyped(handler, nil, NewValidator(&Product{}), nil))
+		})
+	})
+
+	t.Run("registered with the router", func(t *testing.T) {
+		router := NewRouter()
+		router.RegisterRule("slugg", func(reflect.Value, string) error { return nil })
+		router.AddRoute(http.MethodPost, "/products", WithTyped(handler, nil, NewValidator(&Product{}), nil))
+	})
+
+	t.Run("registered globally", func(t *testing.T) {
+		registerTestRule(t, "slugg", func(reflect.Value, string) error { return nil })
+		NewRouter().AddRoute(http.MethodPost, "/products", WithTyped(handler, nil, NewValidator(&Product{}), nil))
+	})
+}
+
+func TestCustomRule_OpenAPI(t *testing.T) {
+	registerTestRule(t, "sku", validateSKU).WithOpenAPI(func(schema *OpenAPISchema, _ string) {
+		schema.Pattern = skuRegex.String()
+	})
+	registerTestRule(t, "divisibleby", validateDivisibleBy).WithOpenAPI(func(schema *OpenAPISchema, param string) {
+		if n, err := strconv.ParseFloat(param, 64); err == nil {
+			schema.MultipleOf = &n
+		}
+	})
+
+	openAPISchema := schemaToOpenAPISchema(NewSchema(TestCustomRuleProduct{}), map[string]*OpenAPISchema{}, nil)
+
+	if sku := openAPISchema.Properties["sku"]; sku.Pattern != skuRegex.String() {
+		t.Errorf("expected sku pattern, got %+v", sku)
+	}
+	if quantity := openAPISchema.Properties["quantity"]; quantity.MultipleOf == nil || *quantity.MultipleOf != 5 {
+		t.Errorf("expected multipleOf 5, got %+v", quantity)
+	}
+	if related := openAPISchema.Properties["related"]; related.Items == nil || related.Items.Pattern != skuRegex.String() {
+		t.Errorf("expected sku pattern on related items, got %+v", related.Items)
+	}
+}
+
+func TestRouter_RegisterRule_OpenAPI(t *testing.T) {
+	registerTestRule(t, "sku", validateSKU).WithOpenAPI(func(schema *OpenAPISchema, _ string) {
+		schema.Pattern = skuRegex.String()
+	})
+
+	router := NewRouter()
+	router.RegisterRule("sku", validateSKU).WithOpenAPI(func(schema *OpenAPISchema, _ string) {
+		schema.Pattern = `^SKU`
+	})
+	router.RegisterRule("divisibleby", validateDivisibleBy).WithOpenAPI(func(schema *OpenAPISchema, param string) {
+		if n, err := strconv.ParseFloat(param, 64); err == nil {
+			schema.MultipleOf = &n
+		}
+	})
+	router.AddRoute(http.MethodPost, "/products", WithTyped(
+		func(ctx *Context, req *TypedRequest[struct{}, TestCustomRuleProduct, struct{}]) (any, int, error) {
+			return req.Body, 201, nil
+		}, nil, NewValidator(&TestCustomRuleProduct{}), nil))
+
+	spec := router.GenerateOpenAPI(OpenAPIConfig{Title: "Products", Version: "1.0.0"})
+	product := spec.Components.Schemas["TestCustomRuleProduct"]
+	if product == nil {
+		t.Fatalf("expected product schema, got %v", spec.Components.Schemas)
+	}
+
+	// Router rules take precedence over global rules with the same name
+	if sku := product.Properties["sku"]; sku.Pattern != `^SKU` {
+		t.Errorf("expected the router's sku pattern, got %+v", sku)
+	}
+	if quantity := product.Properties["quantity"]; quantity.MultipleOf == nil || *quantity.MultipleOf != 5 {
+		t.Errorf("expected multipleOf 5, got %+v", quantity)
+	}
+}
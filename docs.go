@@ -0,0 +1,138 @@
+package nimbus
+
+import (
+	"fmt"
+	"html"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+// DocsRenderer selects the UI that renders the OpenAPI spec (see ServeDocsUI)
+type DocsRenderer string
+
+const (
+	RendererSwaggerUI DocsRenderer = "swagger-ui" // Swagger UI, with "Try it out" and the Authorize button
+	RendererReDoc     DocsRenderer = "redoc"      // ReDoc, a read-only three-panel layout
+	RendererScalar    DocsRenderer = "scalar"     // Scalar API reference, with a built-in API client
+)
+
+// docsAssetsCacheControl lets browsers reuse UI assets for a day, then revalidate them with their ETag
+const docsAssetsCacheControl = "public, max-age=86400"
+
+// docsRenderer describes the assets and page of a renderer
+type docsRenderer struct {
+	cdn   string   // CDN base URL of the pinned release
+	files []string // Assets the page loads, relative to the renderer's directory
+	page  string   // Page template: %[1]s is the spec URL, %[2]s the asset base URL
+}
+
+// docsRenderers lists the supported renderers. Assets are pinned to the same releases as the
+// docsui package, and pages use no inline scripts or styles so they work under a strict CSP.
+var docsRenderers = map[DocsRenderer]docsRenderer{
+	RendererSwaggerUI: {
+		cdn:   "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.18.2",
+		files: []string{"swagger-ui.css", "swagger-ui-bundle.js", "swagger-ui-standalone-preset.js"},
+		page: `<link rel="stylesheet" type="text/css" href="%[2]s/swagger-ui.css">
+</head>
+<body>
+    <div id="swagger-ui" data-spec-url="%[1]s"></div>
+    <script src="%[2]s/swagger-ui-bundle.js" charset="UTF-8"></script>
+    <script src="%[2]s/swagger-ui-standalone-preset.js" charset="UTF-8"></script>
+    <script src="%[3]s" charset="UTF-8"></script>`,
+	},
+	RendererReDoc: {
+		cdn:   "https://cdn.jsdelivr.net/npm/redoc@2.1.3/bundles",
+		files: []string{"redoc.standalone.js"},
+		page: `</head>
+<body>
+    <redoc spec-url="%[1]s"></redoc>
+    <script src="%[2]s/redoc.standalone.js" charset="UTF-8"></script>`,
+	},
+	RendererScalar: {
+		cdn:   "https://cdn.jsdelivr.net/npm/@scalar/api-reference@1.25.0/dist/browser",
+		files: []string{"standalone.js"},
+		page: `</head>
+<body>
+    <script id="api-reference" data-url="%[1]s"></script>
+    <script src="%[2]s/standalone.js" charset="UTF-8"></script>`,
+	},
+}
+
+// swaggerInitJS starts Swagger UI with the spec URL from the page, served as a file
+// because a strict CSP blocks inline scripts
+const swaggerInitJS = `window.onload = function() {
+    var root = document.getElementById('swagger-ui');
+    window.ui = SwaggerUIBundle({
+        url: root.dataset.specUrl,
+        dom_id: '#swagger-ui',
+        deepLinking: true,
+        presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+        plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+        layout: 'StandaloneLayout'
+    });
+};
+`
+
+// ServeDocsUI serves a documentation UI for the spec at specURL (RendererSwaggerUI if renderer is empty).
+// Assets load from a CDN unless assets is set, in which case they're served from it under path + "/assets"
+// with caching headers, so the UI works offline and under a CSP without third-party sources.
+// assets must hold each renderer's files in a directory named after it (e.g., "redoc/redoc.standalone.js"),
+// as the docsui package does. Panics on an unknown renderer or missing assets.
+//
+// Example:
+//
+//	import "github.com/DylanHalstead/nimbus/docsui"
+//
+//	router.ServeDocsUI("/docs", "/docs/openapi.json", nimbus.RendererReDoc, docsui.Assets())
+func (r *Router) ServeDocsUI(path, specURL string, renderer DocsRenderer, assets fs.FS) {
+	if renderer == "" {
+		renderer = RendererSwaggerUI
+	}
+	ui, ok := docsRenderers[renderer]
+	if !ok {
+		panic(fmt.Sprintf("nimbus: unknown docs renderer %q", renderer))
+	}
+
+	prefix := strings.TrimSuffix(path, "/")
+	base := ui.cdn
+	if assets != nil {
+		for _, file := range ui.files {
+			if _, err := fs.Stat(assets, string(renderer)+"/"+file); err != nil {
+				panic(fmt.Sprintf("nimbus: docs assets are missing %s/%s: %v", renderer, file, err))
+			}
+		}
+		base = prefix + "/assets/" + string(renderer)
+		r.Static(prefix+"/assets", assets, docsCacheControl)
+	}
+
+	initURL := prefix + "/init.js"
+	if renderer == RendererSwaggerUI {
+		r.AddRoute(http.MethodGet, initURL, func(ctx *Context) (any, int, error) {
+			ctx.Header("Cache-Control", docsAssetsCacheControl)
+			return ctx.Data(http.StatusOK, "text/javascript; charset=utf-8", []byte(swaggerInitJS))
+		})
+	}
+
+	page := `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>API Documentation</title>
+    ` + fmt.Sprintf(ui.page, html.EscapeString(specURL), html.EscapeString(base), html.EscapeString(initURL)) + `
+</body>
+</html>`
+
+	r.AddRoute(http.MethodGet, path, func(ctx *Context) (any, int, error) {
+		return ctx.HTML(http.StatusOK, page)
+	})
+}
+
+// docsCacheControl sets caching headers on UI assets
+func docsCacheControl(next Handler) Handler {
+	return func(ctx *Context) (any, int, error) {
+		ctx.Header("Cache-Control", docsAssetsCacheControl)
+		return next(ctx)
+	}
+}
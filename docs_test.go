@@ -0,0 +1,153 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var testDocsAssets = fstest.MapFS{
+	"swagger-ui/swagger-ui.css":                  {Data: []byte("body{}")},
+	"swagger-ui/swagger-ui-bundle.js":            {Data: []byte("var SwaggerUIBundle;")},
+	"swagger-ui/swagger-ui-standalone-preset.js": {Data: []byte("var SwaggerUIStandalonePreset;")},
+	"redoc/redoc.standalone.js":                  {Data: []byte("var Redoc;")},
+	"scalar/standalone.js":                       {Data: []byte("var Scalar;")},
+}
+
+func serveDocs(router *Router, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestEnableSwagger_Renderers(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer DocsRenderer
+		assets   bool
+		expected []string // Substrings of the page
+	}{
+		{"swagger ui from cdn", "", false, []string{`data-spec-url="/docs/openapi.json"`, "cdn.jsdelivr.net/npm/swagger-ui-dist@5.18.2/swagger-ui-bundle.js", `src="/docs/init.js"`}},
+		{"swagger ui embedded", RendererSwaggerUI, true, []string{`href="/docs/assets/swagger-ui/swagger-ui.css"`, `src="/docs/assets/swagger-ui/swagger-ui-bundle.js"`}},
+		{"redoc", RendererReDoc, true, []string{`<redoc spec-url="/docs/openapi.json">`, `src="/docs/assets/redoc/redoc.standalone.js"`}},
+		{"scalar", RendererScalar, false, []string{`data-url="/docs/openapi.json"`, "@scalar/api-reference@1.25.0/dist/browser/standalone.js"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := OpenAPIConfig{Title: "Docs", Version: "1.0.0", DocsRenderer: tt.renderer}
+			if tt.assets {
+				config.DocsAssets = testDocsAssets
+			}
+			router := NewRouter()
+			router.EnableSwagger("/docs", "/docs/openapi.json", config)
+
+			w := serveDocs(router, "/docs")
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", w.Code)
+			}
+			page := w.Body.String()
+			for _, s := range tt.expected {
+				if !strings.Contains(page, s) {
+					t.Errorf("expected page to contain %s, got:\n%s", s, page)
+				}
+			}
+			if tt.assets && strings.Contains(page, "cdn.jsdelivr.net") {
+				t.Error("expected embedded assets not to load from a CDN")
+			}
+			if strings.Contains(page, "<script>") || strings.Contains(page, "<style>") || strings.Contains(page, "%!") {
+				t.Errorf("expected page without inline scripts or styles, got:\n%s", page)
+			}
+		})
+	}
+}
+
+func TestServeDocsUI_Assets(t *testing.T) {
+	router := NewRouter()
+	router.ServeDocsUI("/docs", "/openapi.json", RendererSwaggerUI, testDocsAssets)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{"asset", "/docs/assets/swagger-ui/swagger-ui-bundle.js", http.StatusOK, "var SwaggerUIBundle;"},
+		{"init script", "/docs/init.js", http.StatusOK, "root.dataset.specUrl"},
+		{"missing asset", "/docs/assets/swagger-ui/missing.js", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveDocs(router, tt.path)
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d", tt.status, w.Code)
+			}
+			if tt.status == http.StatusOK {
+				if !strings.Contains(w.Body.String(), tt.body) {
+					t.Errorf("expected body to contain %q, got %q", tt.body, w.Body.String())
+				}
+				if w.Header().Get("Cache-Control") != docsAssetsCacheControl {
+					t.Errorf("expected Cache-Control %q, got %q", docsAssetsCacheControl, w.Header().Get("Cache-Control"))
+				}
+			}
+		})
+	}
+
+	// Embedded assets revalidate with their ETag
+	etag := serveDocs(router, "/docs/assets/swagger-ui/swagger-ui.css").Header().Get("ETag")
+	req := httptest.NewRequest(http.MethodGet, "/docs/assets/swagger-ui/swagger-ui.css", nil)
+	req.Header.Set("If-None-Match", etag)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if etag == "" || w.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for ETag %q, got %d", etag, w.Code)
+	}
+}
+
+func TestServeDocsUI_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer DocsRenderer
+		assets   fs.FS
+	}{
+		{"unknown renderer", "rapidoc", nil},
+		{"missing assets", RendererReDoc, fstest.MapFS{"swagger-ui/swagger-ui.css": {Data: []byte("body{}")}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected ServeDocsUI to panic")
+				}
+			}()
+			NewRouter().ServeDocsUI("/docs", "/openapi.json", tt.renderer, tt.assets)
+		})
+	}
+}
+
+func TestServeSwaggerJSON(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(http.MethodGet, "/users", func(ctx *Context) (any, int, error) { return nil, http.StatusOK, nil })
+	router.ServeSwaggerJSON("/openapi.json", OpenAPIConfig{Title: "Users", Version: "1.0.0"})
+
+	w := serveDocs(router, "/openapi.json")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected 200 JSON, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	// The spec is wrapped in a SuccessResponse like any other handler result
+	var response struct {
+		Success bool           `json:"success"`
+		Data    map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if !response.Success || response.Data["openapi"] != OpenAPIVersion30 || response.Data["paths"] == nil {
+		t.Errorf("expected spec in a success response, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,37 @@
+// Package docsui embeds the Swagger UI, ReDoc and Scalar assets so the documentation UI works
+// without a CDN (offline, air-gapped or under a strict CSP). It's a separate package so
+// applications that don't serve docs don't pay for the assets in their binary.
+//
+// The assets are pinned to the releases the nimbus renderers are written for, listed in
+// assets/VERSIONS. The Swagger UI assets are committed to the repository; run
+// `go generate ./docsui` (or `make docs-assets`) to download the ReDoc and Scalar assets
+// before selecting those renderers with DocsAssets.
+//
+// Example:
+//
+//	router.EnableSwagger("/docs", "/docs/openapi.json", nimbus.OpenAPIConfig{
+//	    Title:        "My API",
+//	    Version:      "1.0.0",
+//	    DocsRenderer: nimbus.RendererSwaggerUI,
+//	    DocsAssets:   docsui.Assets(),
+//	})
+package docsui
+
+//go:generate go run fetch.go
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// Assets returns the renderer assets, one directory per renderer (e.g., "swagger-ui/swagger-ui.css")
+func Assets() fs.FS {
+	sub, err := fs.Sub(assets, "assets")
+	if err != nil {
+		panic(err) // The embedded directory always exists
+	}
+	return sub
+}
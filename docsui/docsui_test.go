@@ -0,0 +1,70 @@
+package docsui_test
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DylanHalstead/nimbus"
+	"github.com/DylanHalstead/nimbus/docsui"
+)
+
+func TestAssets_ServeDocsUI(t *testing.T) {
+	tests := []struct {
+		renderer nimbus.DocsRenderer
+		files    []string // Files listed for the renderer in assets/VERSIONS
+	}{
+		{nimbus.RendererSwaggerUI, []string{"swagger-ui.css", "swagger-ui-bundle.js", "swagger-ui-standalone-preset.js"}},
+		{nimbus.RendererReDoc, []string{"redoc.standalone.js"}},
+		{nimbus.RendererScalar, []string{"standalone.js"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.renderer), func(t *testing.T) {
+			assets := docsui.Assets()
+			for _, file := range tt.files {
+				if _, err := fs.Stat(assets, string(tt.renderer)+"/"+file); err != nil {
+					// Only Swagger UI is vendored in the repository; the others are added by `go generate ./docsui`
+					if tt.renderer == nimbus.RendererSwaggerUI {
+						t.Fatalf("expected %s/%s to be embedded: %v", tt.renderer, file, err)
+					}
+					t.Skipf("%s assets are not embedded, run `go generate ./docsui` to add them", tt.renderer)
+				}
+			}
+
+			router := nimbus.NewRouter()
+			router.EnableSwagger("/docs", "/docs/openapi.json", nimbus.OpenAPIConfig{
+				Title:        "Docs",
+				Version:      "1.0.0",
+				DocsRenderer: tt.renderer,
+				DocsAssets:   assets,
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected page status 200, got %d", w.Code)
+			}
+			if page := w.Body.String(); strings.Contains(page, "cdn.jsdelivr.net") {
+				t.Errorf("expected embedded assets not to load from a CDN, got:\n%s", page)
+			}
+
+			for _, file := range tt.files {
+				path := "/docs/assets/" + string(tt.renderer) + "/" + file
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+				if w.Code != http.StatusOK {
+					t.Errorf("expected %s status 200, got %d", path, w.Code)
+				}
+				if w.Body.Len() == 0 {
+					t.Errorf("expected %s to have content", path)
+				}
+				if cc := w.Header().Get("Cache-Control"); cc == "" {
+					t.Errorf("expected %s to have a Cache-Control header", path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+//go:build ignore
+
+// fetch downloads the renderer assets listed in assets/VERSIONS into assets/<directory>
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	if err := fetch(filepath.Join("assets", "VERSIONS")); err != nil {
+		fmt.Fprintln(os.Stderr, "fetch:", err)
+		os.Exit(1)
+	}
+}
+
+func fetch(versionsFile string) error {
+	f, err := os.Open(versionsFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) < 3 {
+			return fmt.Errorf("invalid line %q", scanner.Text())
+		}
+
+		dir := filepath.Join("assets", fields[0])
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		for _, file := range fields[2:] {
+			if err := download(fields[1]+"/"+file, filepath.Join(dir, file)); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+func download(url, dest string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	fmt.Println("fetched", dest)
+	return os.WriteFile(dest, data, 0644)
+}
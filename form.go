@@ -0,0 +1,265 @@
+package nimbus
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/DylanHalstead/nimbus/internal/bytesize"
+)
+
+const (
+	// MIMEApplicationForm is the Content-Type for URL-encoded form bodies.
+	MIMEApplicationForm = "application/x-www-form-urlencoded"
+	// MIMEMultipartForm is the Content-Type for multipart form bodies (file uploads).
+	MIMEMultipartForm = "multipart/form-data"
+
+	// DefaultMaxMultipartMemory is the default number of bytes of a multipart body held in memory.
+	// File parts beyond this limit are streamed to temporary files on disk (removed after the request).
+	DefaultMaxMultipartMemory = 32 << 20 // 32 MB
+)
+
+var (
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeaderSliceType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// isFileType reports whether t is a file upload field type.
+func isFileType(t reflect.Type) bool {
+	return t == fileHeaderType || t == fileHeaderSliceType
+}
+
+// SetMaxMultipartMemory sets how many bytes of a multipart body are held in memory when binding forms.
+// Larger uploads are streamed to temporary files on disk. Defaults to DefaultMaxMultipartMemory.
+// Combine with middleware.BodyLimit to cap the total upload size.
+func (r *Router) SetMaxMultipartMemory(bytes int64) {
+	r.maxMultipartMemory.Store(bytes)
+}
+
+// maxMultipartMemory returns the router's multipart memory limit (or the default).
+func (c *Context) maxMultipartMemory() int64 {
+	if c.router != nil {
+		if limit := c.router.maxMultipartMemory.Load(); limit > 0 {
+			return limit
+		}
+	}
+	return DefaultMaxMultipartMemory
+}
+
+// BindAndValidateForm binds and validates a URL-encoded or multipart form body using a schema.
+// Fields are matched by their `form` tag (falling back to the `json` name).
+// File fields must be *multipart.FileHeader or []*multipart.FileHeader.
+func (c *Context) BindAndValidateForm(target any, schema *Schema) error {
+	form, files, err := c.parseForm()
+	if err != nil {
+		return err
+	}
+	return validateTarget(target, schema, formDocument(form, files, schema), bindForm(form, files, target, schema), c.rules())
+}
+
+// parseForm parses the request body as a URL-encoded or multipart form.
+// Only body values are returned (query parameters are bound separately).
+func (c *Context) parseForm() (url.Values, map[string][]*multipart.FileHeader, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType == MIMEMultipartForm {
+		if err := c.Request.ParseMultipartForm(c.maxMultipartMemory()); err != nil {
+			return nil, nil, err
+		}
+		return c.Request.PostForm, c.Request.MultipartForm.File, nil
+	}
+	if err := c.Request.ParseForm(); err != nil {
+		return nil, nil, err
+	}
+	return c.Request.PostForm, nil, nil
+}
+
+// SaveUploadedFile streams an uploaded file to dst on disk.
+// Parent directories must already exist. The file is copied in chunks, never fully buffered.
+func (c *Context) SaveUploadedFile(file *multipart.FileHeader, dst string) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	out, err := os.Create(filepath.Clean(dst))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, src)
+	return err
+}
+
+// ValidateForm binds form values and uploaded files to a struct and validates it against a schema
+func ValidateForm(form url.Values, files map[string][]*multipart.FileHeader, target any, schema *Schema) error {
+	return validateTarget(target, schema, formDocument(form, files, schema), bindForm(form, files, target, schema), nil)
+}
+
+// formDocument records which schema fields were sent in a form, so unsent fields
+// are treated as missing rather than validated as zero values
+func formDocument(form url.Values, files map[string][]*multipart.FileHeader, schema *Schema) map[string]any {
+	doc := make(map[string]any)
+	for fieldName, rule := range schema.fields {
+		values := form[rule.formName]
+		if len(files[rule.formName]) > 0 || len(values) > 1 || (len(values) == 1 && values[0] != "") {
+			doc[fieldName] = true
+		}
+	}
+	return doc
+}
+
+// bindForm binds form values and uploaded files to struct fields.
+// Values that can't be converted to the field type are returned as ValidationErrors.
+func bindForm(form url.Values, files map[string][]*multipart.FileHeader, target any, schema *Schema) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr {
+		return fmt.Errorf("target must be a pointer to struct")
+	}
+
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a pointer to struct")
+	}
+
+	if v.Type() != schema.structType {
+		return fmt.Errorf("target must be a pointer to %s", schema.structType)
+	}
+
+	// Bind form values and files to struct fields
+	var bindErrs ValidationErrors
+	for _, fieldName := range schema.order {
+		rule := schema.fields[fieldName]
+		fieldValue := v.FieldByIndex(rule.index)
+		if !fieldValue.CanSet() {
+			continue
+		}
+
+		switch fieldValue.Type() {
+		case fileHeaderType:
+			if headers := files[rule.formName]; len(headers) > 0 {
+				fieldValue.Set(reflect.ValueOf(headers[0]))
+			}
+			continue
+		case fileHeaderSliceType:
+			if headers := files[rule.formName]; len(headers) > 0 {
+				fieldValue.Set(reflect.ValueOf(headers))
+			}
+			continue
+		}
+
+		values := form[rule.formName]
+		if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+			continue
+		}
+
+		// Repeated keys bind to slice fields (e.g., tags=a&tags=b)
+		if fieldValue.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(fieldValue.Type(), len(values), len(values))
+			for i, value := range values {
+				if err := setFieldValue(slice.Index(i), value); err != nil {
+					bindErrs = append(bindErrs, typeError(fieldName, value, err))
+				}
+			}
+			fieldValue.Set(slice)
+			continue
+		}
+
+		if err := setFieldValue(fieldValue, values[0]); err != nil {
+			bindErrs = append(bindErrs, typeError(fieldName, values[0], err))
+		}
+	}
+
+	if len(bindErrs) > 0 {
+		return bindErrs
+	}
+	return nil
+}
+
+// validateFiles applies maxsize and mimetype rules to file upload fields.
+// Content types are sniffed from the file content rather than trusting the client's header.
+func validateFiles(fieldName string, value any, rule fieldRule) ValidationErrors {
+	if rule.maxSize <= 0 && len(rule.mimeTypes) == 0 {
+		return nil
+	}
+
+	switch files := value.(type) {
+	case *multipart.FileHeader:
+		return validateFile(fieldName, files, rule)
+	case []*multipart.FileHeader:
+		var errors ValidationErrors
+		for i, file := range files {
+			errors = append(errors, validateFile(fmt.Sprintf("%s[%d]", fieldName, i), file, rule)...)
+		}
+		return errors
+	}
+	return nil
+}
+
+// validateFile validates a single uploaded file
+func validateFile(fieldName string, file *multipart.FileHeader, rule fieldRule) ValidationErrors {
+	var errors ValidationErrors
+
+	if rule.maxSize > 0 && file.Size > rule.maxSize {
+		errors = append(errors, ValidationError{
+			Field:   fieldName,
+			Value:   file.Filename,
+			Tag:     "maxsize",
+			Message: fmt.Sprintf("%s must be at most %s", fieldName, bytesize.Format(rule.maxSize)),
+		})
+	}
+
+	if len(rule.mimeTypes) > 0 {
+		contentType, err := sniffContentType(file)
+		if err != nil || !matchesMIMEType(contentType, rule.mimeTypes) {
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Value:   file.Filename,
+				Tag:     "mimetype",
+				Message: fmt.Sprintf("%s must be of type: %s", fieldName, strings.Join(rule.mimeTypes, ", ")),
+			})
+		}
+	}
+
+	return errors
+}
+
+// sniffContentType detects a file's media type from its first 512 bytes
+func sniffContentType(file *multipart.FileHeader) (string, error) {
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(buf[:n]))
+	return mediaType, nil
+}
+
+// matchesMIMEType reports whether mediaType matches one of the allowed types.
+// Supports wildcards such as "image/*".
+func matchesMIMEType(mediaType string, allowed []string) bool {
+	for _, a := range allowed {
+		if a == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(a, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,264 @@
+package nimbus
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type TestSignupForm struct {
+	Name  string   `json:"name" form:"full_name" validate:"required,minlen=2"`
+	Age   int      `json:"age" validate:"min=18"`
+	Tags  []string `json:"tags"`
+	Agree bool     `json:"agree"`
+}
+
+type TestUploadForm struct {
+	Title       string                  `json:"title" validate:"required"`
+	Avatar      *multipart.FileHeader   `form:"avatar" validate:"required,maxsize=1KB,mimetype=image/png|image/jpeg"`
+	Attachments []*multipart.FileHeader `form:"attachments" validate:"maxsize=16B"`
+}
+
+// pngHeader is enough of a PNG signature for content sniffing
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type testFilePart struct {
+	field, filename string
+	content         []byte
+}
+
+func newMultipartRequest(t *testing.T, target string, values map[string]string, files []testFilePart) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for k, v := range values {
+		if err := mw.WriteField(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", `form-data; name="`+f.field+`"; filename="`+f.filename+`"`)
+		header.Set("Content-Type", "application/octet-stream")
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(f.content)
+	}
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestValidateForm_URLEncoded(t *testing.T) {
+	schema := NewSchema(TestSignupForm{})
+	form := url.Values{
+		"full_name": {"Alice"},
+		"age":       {"30"},
+		"tags":      {"go", "api"},
+		"agree":     {"true"},
+	}
+
+	var signup TestSignupForm
+	if err := ValidateForm(form, nil, &signup, schema); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if signup.Name != "Alice" || signup.Age != 30 || !signup.Agree {
+		t.Errorf("unexpected binding result: %+v", signup)
+	}
+	if len(signup.Tags) != 2 || signup.Tags[1] != "api" {
+		t.Errorf("expected repeated keys to bind to slice, got %v", signup.Tags)
+	}
+
+	// Form tag takes precedence over the JSON name
+	err := ValidateForm(url.Values{"name": {"Bob"}, "age": {"30"}}, nil, &TestSignupForm{}, schema)
+	validationErrs, ok := err.(ValidationErrors)
+	if !ok || len(validationErrs) != 1 || validationErrs[0].Field != "name" || validationErrs[0].Tag != "required" {
+		t.Errorf("expected required error for name, got %v", err)
+	}
+
+	if err := ValidateForm(url.Values{"full_name": {"Bob"}, "age": {"abc"}}, nil, &TestSignupForm{}, schema); err == nil {
+		t.Error("expected conversion error for invalid integer")
+	}
+}
+
+func TestContext_BindAndValidateForm(t *testing.T) {
+	schema := NewSchema(TestSignupForm{})
+
+	req := httptest.NewRequest(http.MethodPost, "/signup?age=99", strings.NewReader("full_name=Alice&age=21"))
+	req.Header.Set("Content-Type", MIMEApplicationForm)
+	ctx := NewContext(httptest.NewRecorder(), req)
+	defer ctx.Release()
+
+	var signup TestSignupForm
+	if err := ctx.BindAndValidateForm(&signup, schema); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if signup.Age != 21 {
+		t.Errorf("expected body value to be bound (not query), got %d", signup.Age)
+	}
+}
+
+func TestValidateForm_MultipartFiles(t *testing.T) {
+	schema := NewSchema(TestUploadForm{})
+
+	tests := []struct {
+		name         string
+		files        []testFilePart
+		expectedTags map[string]string // field -> tag
+	}{
+		{
+			name: "valid upload",
+			files: []testFilePart{
+				{"avatar", "me.png", pngHeader},
+				{"attachments", "a.txt", []byte("small")},
+				{"attachments", "b.txt", []byte("also small")},
+			},
+		},
+		{
+			name:         "missing required file",
+			expectedTags: map[string]string{"avatar": "required"},
+		},
+		{
+			name:         "wrong content type",
+			files:        []testFilePart{{"avatar", "me.png", []byte("plain text pretending to be an image")}},
+			expectedTags: map[string]string{"avatar": "mimetype"},
+		},
+		{
+			name:         "file too large",
+			files:        []testFilePart{{"avatar", "me.png", append(pngHeader, make([]byte, 2048)...)}},
+			expectedTags: map[string]string{"avatar": "maxsize"},
+		},
+		{
+			name: "one of many files too large",
+			files: []testFilePart{
+				{"avatar", "me.png", pngHeader},
+				{"attachments", "a.txt", []byte("small")},
+				{"attachments", "b.txt", []byte("this attachment is too large")},
+			},
+			expectedTags: map[string]string{"attachments[1]": "maxsize"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newMultipartRequest(t, "/upload", map[string]string{"title": "Profile"}, tt.files)
+			ctx := NewContext(httptest.NewRecorder(), req)
+			defer ctx.Release()
+
+			var upload TestUploadForm
+			err := ctx.BindAndValidateForm(&upload, schema)
+
+			if len(tt.expectedTags) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if upload.Title != "Profile" || upload.Avatar == nil || upload.Avatar.Filename != "me.png" {
+					t.Errorf("unexpected binding result: %+v", upload)
+				}
+				if len(upload.Attachments) != 2 {
+					t.Errorf("expected 2 attachments, got %d", len(upload.Attachments))
+				}
+				return
+			}
+
+			validationErrs, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("expected ValidationErrors, got %v", err)
+			}
+			if len(validationErrs) != len(tt.expectedTags) {
+				t.Fatalf("expected %d errors, got %v", len(tt.expectedTags), validationErrs)
+			}
+			for _, ve := range validationErrs {
+				if tt.expectedTags[ve.Field] != ve.Tag {
+					t.Errorf("unexpected error %s/%s: %s", ve.Field, ve.Tag, ve.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestWithTyped_MultipartBody(t *testing.T) {
+	uploadValidator := NewValidator(&TestUploadForm{})
+	signupValidator := NewValidator(&TestSignupForm{})
+	dir := t.TempDir()
+
+	router := NewRouter()
+	router.SetMaxMultipartMemory(8) // Force file parts onto disk
+	router.AddRoute(http.MethodPost, "/upload", WithTyped(
+		func(ctx *Context, req *TypedRequest[struct{}, TestUploadForm, struct{}]) (any, int, error) {
+			if err := ctx.SaveUploadedFile(req.Body.Avatar, filepath.Join(dir, "avatar.png")); err != nil {
+				return nil, 500, err
+			}
+			return map[string]string{"title": req.Body.Title}, 201, nil
+		}, nil, uploadValidator, nil))
+	router.AddRoute(http.MethodPost, "/signup", WithTyped(
+		func(ctx *Context, req *TypedRequest[struct{}, TestSignupForm, struct{}]) (any, int, error) {
+			return req.Body, 201, nil
+		}, nil, signupValidator, nil))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newMultipartRequest(t, "/upload", map[string]string{"title": "Profile"},
+		[]testFilePart{{"avatar", "me.png", pngHeader}}))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	saved, err := os.ReadFile(filepath.Join(dir, "avatar.png"))
+	if err != nil || !bytes.Equal(saved, pngHeader) {
+		t.Errorf("expected uploaded file to be saved, got %q (%v)", saved, err)
+	}
+
+	// JSON bodies still work on the same validators
+	req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(`{"name":"Alice","age":30}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status 201 for JSON body, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader("full_name=A&age=30"))
+	req.Header.Set("Content-Type", MIMEApplicationForm)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid form, got %d", w.Code)
+	}
+}
+
+func TestSchemaToFormSchema(t *testing.T) {
+	schema := NewSchema(TestUploadForm{})
+
+	jsonSchema := schemaToOpenAPISchema(schema, map[string]*OpenAPISchema{}, nil)
+	if _, ok := jsonSchema.Properties["avatar"]; ok {
+		t.Error("expected file fields to be excluded from the JSON schema")
+	}
+
+	formSchema := schemaToFormSchema(schema, map[string]*OpenAPISchema{}, nil)
+	avatar := formSchema.Properties["avatar"]
+	if avatar == nil || avatar.Type != "string" || avatar.Format != "binary" {
+		t.Errorf("expected avatar to be a binary string, got %+v", avatar)
+	}
+	attachments := formSchema.Properties["attachments"]
+	if attachments == nil || attachments.Type != "array" || attachments.Items.Format != "binary" {
+		t.Errorf("expected attachments to be an array of binary strings, got %+v", attachments)
+	}
+
+	signupSchema := schemaToFormSchema(NewSchema(TestSignupForm{}), map[string]*OpenAPISchema{}, nil)
+	if _, ok := signupSchema.Properties["full_name"]; !ok {
+		t.Error("expected form schema to use form tag names")
+	}
+}
@@ -0,0 +1,291 @@
+package nimbus
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// RequestHandlerFunc is a typed handler that receives a single bound and validated request struct
+// (see Handle). A nil response is sent as 204 No Content.
+type RequestHandlerFunc[Req any, Resp any] func(*Context, *Req) (*Resp, error)
+
+// StatusCoder is implemented by Handle response types that choose their success status
+// (e.g., 201 Created). A zero status means 200 OK.
+//
+// Example:
+//
+//	func (*CreatedUser) StatusCode() int { return http.StatusCreated }
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// Handle wraps a handler that takes one request struct whose tags declare where each field comes from:
+//
+//   - `path:"id"`: path parameter
+//   - `query:"limit"`: query parameter
+//   - `header:"X-Tenant-ID"`: request header
+//   - `cookie:"session"`: cookie
+//   - `json:"name"`: field of the request body
+//   - `body:""`: the whole request body (a struct or pointer to struct; a nil pointer when no body is sent)
+//
+// The body is decoded based on Content-Type like BindAndValidateBody. Schemas are built once when
+// Handle is called, and validation errors from every source are reported together.
+// Responses are sent with 200 OK, or the status reported by a response type implementing StatusCoder,
+// and a nil response is sent as 204 No Content. The spec documents both, using the status reported by
+// the response type's zero value. Return an *APIError with a status (see APIError.WithStatus) or
+// ValidationErrors to fail the request.
+//
+// Example:
+//
+//	type UpdateUserRequest struct {
+//	    ID       int    `path:"id" validate:"min=1"`
+//	    DryRun   bool   `query:"dry_run"`
+//	    TenantID string `header:"X-Tenant-ID" validate:"required"`
+//	    Name     string `json:"name" validate:"required,minlen=2"`
+//	}
+//
+//	func updateUser(ctx *nimbus.Context, req *UpdateUserRequest) (*User, error) {
+//	    user, ok := users[req.ID]
+//	    if !ok {
+//	        return nil, nimbus.NewAPIError("not_found", "user not found").WithStatus(404)
+//	    }
+//	    user.Name = req.Name
+//	    return user, nil
+//	}
+//
+//	router.AddRoute(http.MethodPut, "/users/:id", nimbus.Handle(updateUser))
+func Handle[Req any, Resp any](handler RequestHandlerFunc[Req, Resp]) Handler {
+	binding := newRequestBinding(reflect.TypeFor[Req]())
+	respSchema := responseSchema(reflect.TypeFor[Resp]())
+
+	return describeHandler(func(ctx *Context) (any, int, error) {
+		req := new(Req)
+		if err := binding.bind(ctx, req); err != nil {
+			return ctx.sendBindError(err, "invalid_request")
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			var validationErrs ValidationErrors
+			if errors.As(err, &validationErrs) {
+				return ctx.SendValidationError(validationErrs)
+			}
+			return nil, 0, err
+		}
+		if resp == nil {
+			return nil, http.StatusNoContent, nil
+		}
+		if err := ctx.checkResponse(resp, respSchema); err != nil {
+			return nil, 0, err
+		}
+		return resp, responseStatus(resp), nil
+	}, binding.spec(reflect.TypeFor[Resp](), responseStatus(new(Resp))))
+}
+
+// responseStatus returns the success status of a Handle response (see StatusCoder)
+func responseStatus(resp any) int {
+	if coder, ok := resp.(StatusCoder); ok {
+		if status := coder.StatusCode(); status != 0 {
+			return status
+		}
+	}
+	return http.StatusOK
+}
+
+// SetResponseValidation enables validating the responses of typed handlers (Handle and
+// WithTypedResponse) against the `validate` tags of their response types. Meant for development
+// and tests: a response that drifts from its declared type fails with 500 invalid_response.
+func (r *Router) SetResponseValidation(enabled bool) {
+	r.validateResponses.Store(enabled)
+}
+
+// responseSchema returns the schema used to validate responses of type t (nil if t isn't a struct)
+func responseSchema(t reflect.Type) *Schema {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return NewSchema(reflect.New(t).Interface())
+}
+
+// checkResponse validates a typed handler's response when the router has response validation enabled
+func (c *Context) checkResponse(resp any, schema *Schema) error {
+	if schema == nil || c.router == nil || !c.router.validateResponses.Load() {
+		return nil
+	}
+
+	errs := schema.validate(resp, nil, c.rules())
+	if len(errs) == 0 {
+		return nil
+	}
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Message
+	}
+	message := fmt.Sprintf("response doesn't match %s: %s", schema.structType, strings.Join(messages, "; "))
+	return NewAPIError("invalid_response", message).WithStatus(http.StatusInternalServerError)
+}
+
+// requestBinding binds a request struct declared with path, query, header, cookie, json and body tags
+type requestBinding struct {
+	schema    *Schema // Schema of the request struct
+	body      *Schema // Schema of the `body` field (nil if there is none)
+	bodyIndex []int   // Index of the `body` field
+	hasBody   bool    // The request struct has fields bound from the body
+}
+
+// newRequestBinding builds the schemas for a request struct.
+// Panics if t isn't a struct or its body fields are declared inconsistently.
+func newRequestBinding(t reflect.Type) *requestBinding {
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("nimbus: Handle request type %s must be a struct", t))
+	}
+
+	b := &requestBinding{schema: NewSchema(reflect.New(t).Interface())}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, ok := field.Tag.Lookup("body"); !ok {
+			continue
+		}
+		bodyType := field.Type
+		if bodyType.Kind() == reflect.Ptr {
+			bodyType = bodyType.Elem()
+		}
+		if b.body != nil || bodyType.Kind() != reflect.Struct || !field.IsExported() {
+			panic(fmt.Sprintf("nimbus: Handle request type %s must have at most one exported struct body field", t))
+		}
+		b.body = NewSchema(reflect.New(bodyType).Interface())
+		b.bodyIndex = field.Index
+		b.hasBody = true
+	}
+
+	for _, fieldName := range b.schema.order {
+		rule := b.schema.fields[fieldName]
+		if _, isBody := t.FieldByIndex(rule.index).Tag.Lookup("body"); isBody || rule.in != "" || !rule.inJSON {
+			continue
+		}
+		if b.body != nil {
+			panic(fmt.Sprintf("nimbus: Handle request type %s: json field %s can't be combined with a body field", t, fieldName))
+		}
+		b.hasBody = true
+	}
+	return b
+}
+
+// spec describes the request struct's path parameters, query, header and cookie parameters,
+// and body for OpenAPI generation
+func (b *requestBinding) spec(responseType reflect.Type, status int) *handlerSpec {
+	spec := &handlerSpec{
+		status:    status,
+		noContent: true,
+		params:    b.schema,
+		query: b.schema.subset(func(rule fieldRule) bool {
+			return rule.in == "query" || rule.in == "header" || rule.in == "cookie"
+		}),
+		body:         b.body,
+		responseType: responseType,
+	}
+	if b.body == nil && b.hasBody {
+		spec.body = b.schema.subset(func(rule fieldRule) bool { return rule.in == "" && rule.inJSON })
+	}
+	return spec
+}
+
+// bind decodes the body and binds path, query, header and cookie fields of target, then validates it.
+// Conversion and validation errors from every source are returned together as ValidationErrors.
+func (b *requestBinding) bind(ctx *Context, target any) error {
+	v := reflect.ValueOf(target).Elem()
+	sentBody := ctx.Request.Body != nil && ctx.Request.Body != http.NoBody
+
+	// The request document records which fields were sent
+	var doc any = make(map[string]any)
+	var bindErrs, bodyErrs ValidationErrors
+	switch {
+	case b.body != nil:
+		field := v.FieldByIndex(b.bodyIndex)
+		if field.Kind() == reflect.Ptr {
+			if !sentBody {
+				break
+			}
+			field.Set(reflect.New(field.Type().Elem()))
+			field = field.Elem()
+		}
+
+		// An empty body is validated so its required fields are reported
+		var err error
+		if sentBody {
+			err = ctx.BindAndValidateBody(field.Addr().Interface(), b.body)
+		} else {
+			err = validateTarget(field.Addr().Interface(), b.body, map[string]any{}, nil, ctx.rules())
+		}
+		if !errors.As(err, &bodyErrs) && err != nil {
+			return err
+		}
+	case b.hasBody && sentBody:
+		bodyDoc, err := ctx.decodeBody(target, b.schema)
+		if !errors.As(err, &bindErrs) && err != nil {
+			return err
+		}
+		doc = bodyDoc
+
+		// Fields bound from other sources are never taken from the body
+		for _, fieldName := range b.schema.order {
+			if rule := b.schema.fields[fieldName]; rule.in != "" {
+				v.FieldByIndex(rule.index).SetZero()
+				if obj, ok := doc.(map[string]any); ok {
+					delete(obj, fieldName)
+				}
+			}
+		}
+	}
+
+	for _, fieldName := range b.schema.order {
+		rule := b.schema.fields[fieldName]
+		fieldValue := v.FieldByIndex(rule.index)
+		if rule.in == "" || !fieldValue.CanSet() {
+			continue
+		}
+
+		var sent bool
+		var errs ValidationErrors
+		switch rule.in {
+		case "path":
+			if paramValue, ok := ctx.PathParams[rule.pathName]; ok {
+				sent = true
+				if err := setFieldValue(fieldValue, paramValue); err != nil {
+					errs = ValidationErrors{typeError(fieldName, paramValue, err)}
+				}
+			}
+		case "query":
+			if ctx.queryCache == nil {
+				ctx.queryCache = ctx.Request.URL.Query()
+			}
+			sent, errs = bindQueryField(fieldValue, fieldName, rule, ctx.queryCache)
+		default:
+			sent, errs = bindHeaderField(fieldValue, fieldName, rule, ctx.Request)
+		}
+
+		if obj, ok := doc.(map[string]any); ok && sent {
+			obj[fieldName] = true
+		}
+		bindErrs = append(bindErrs, errs...)
+	}
+
+	var bindErr error
+	if len(bindErrs) > 0 {
+		bindErr = bindErrs
+	}
+	err := validateTarget(target, b.schema, doc, bindErr, ctx.rules())
+	errs, ok := err.(ValidationErrors)
+	if err != nil && !ok {
+		return err
+	}
+
+	errs = append(errs, bodyErrs...)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type TestUpdateItemRequest struct {
+	ID       int    `path:"id" validate:"min=1"`
+	DryRun   bool   `query:"dry_run"`
+	TenantID string `header:"X-Tenant-ID" validate:"required"`
+	Session  string `cookie:"session"`
+	Name     string `json:"name" validate:"required,minlen=2"`
+	Quantity int    `json:"quantity" default:"1" validate:"min=1"`
+}
+
+type TestItem struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	DryRun   bool   `json:"dry_run"`
+	Tenant   string `json:"tenant"`
+}
+
+type TestCreateNoteRequest struct {
+	ItemID int              `path:"id" validate:"min=1"`
+	Note   TestOrderBody    `body:""`
+	Sort   Optional[string] `query:"sort"`
+}
+
+func newTestHandleRouter() *Router {
+	router := NewRouter()
+	router.AddRoute(http.MethodPut, "/items/:id", Handle(func(ctx *Context, req *TestUpdateItemRequest) (*TestItem, error) {
+		if req.ID == 404 {
+			return nil, NewAPIError("not_found", "item not found").WithStatus(http.StatusNotFound)
+		}
+		return &TestItem{ID: req.ID, Name: req.Name, Quantity: req.Quantity, DryRun: req.DryRun, Tenant: req.TenantID}, nil
+	}))
+	return router
+}
+
+func TestHandle_MixedSources(t *testing.T) {
+	router := newTestHandleRouter()
+
+	req := httptest.NewRequest(http.MethodPut, "/items/7?dry_run=true", strings.NewReader(`{"name":"Widget","id":99}`))
+	req.Header.Set("X-Tenant-ID", "acme")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data TestItem `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	expected := TestItem{ID: 7, Name: "Widget", Quantity: 1, DryRun: true, Tenant: "acme"}
+	if resp.Data != expected {
+		t.Errorf("expected %+v, got %+v", expected, resp.Data)
+	}
+}
+
+func TestHandle_AggregatesErrors(t *testing.T) {
+	router := newTestHandleRouter()
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		expected map[string]string // field -> tag
+	}{
+		{
+			name:     "every source",
+			path:     "/items/0?dry_run=maybe",
+			body:     `{"name":"W","quantity":0}`,
+			expected: map[string]string{"id": "min", "dry_run": "type", "X-Tenant-ID": "required", "name": "minlen", "quantity": "min"},
+		},
+		{
+			name:     "missing body",
+			path:     "/items/abc",
+			expected: map[string]string{"id": "type", "X-Tenant-ID": "required", "name": "required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, tt.path, nil)
+			if tt.body != "" {
+				req = httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(tt.body))
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			details := decodeValidationDetails(t, w)
+			if len(details) != len(tt.expected) {
+				t.Fatalf("expected %d errors, got %v", len(tt.expected), details)
+			}
+			for _, err := range details {
+				if tag, ok := tt.expected[err.Field]; !ok || tag != err.Tag {
+					t.Errorf("unexpected error %s/%s: %s", err.Field, err.Tag, err.Message)
+				}
+			}
+		})
+	}
+}
+
+// TestCreatedItem is sent with 201 Created
+type TestCreatedItem struct {
+	ID int `json:"id"`
+}
+
+func (*TestCreatedItem) StatusCode() int { return http.StatusCreated }
+
+func TestHandle_Responses(t *testing.T) {
+	router := newTestHandleRouter()
+	router.AddRoute(http.MethodDelete, "/items/:id", Handle(func(ctx *Context, req *struct {
+		ID int `path:"id"`
+	}) (*TestItem, error) {
+		return nil, nil
+	}))
+	router.AddRoute(http.MethodPost, "/items", Handle(func(ctx *Context, req *struct {
+		Name string `json:"name"`
+	}) (*TestCreatedItem, error) {
+		return &TestCreatedItem{ID: 1}, nil
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"api error status", http.MethodPut, "/items/404", http.StatusNotFound},
+		{"nil response", http.MethodDelete, "/items/1", http.StatusNoContent},
+		{"status coder", http.MethodPost, "/items", http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{"name":"Widget"}`))
+			req.Header.Set("X-Tenant-ID", "acme")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// Both success statuses are documented
+	spec := router.GenerateOpenAPI(OpenAPIConfig{})
+	for _, tt := range []struct {
+		op    *OpenAPIOperation
+		codes []string
+	}{
+		{spec.Paths["/items/{id}"].PUT, []string{"200", "204"}},
+		{spec.Paths["/items"].POST, []string{"201", "204"}},
+	} {
+		for _, code := range tt.codes {
+			if _, ok := tt.op.Responses[code]; !ok {
+				t.Errorf("expected %s response for %s, got %v", code, tt.op.OperationID, tt.op.Responses)
+			}
+		}
+	}
+	if _, ok := spec.Paths["/items"].POST.Responses["200"]; ok {
+		t.Error("expected no 200 response for a 201 response type")
+	}
+}
+
+func TestHandle_BodyField(t *testing.T) {
+	router := NewRouter()
+	var got TestCreateNoteRequest
+	router.AddRoute(http.MethodPost, "/items/:id/notes", Handle(func(ctx *Context, req *TestCreateNoteRequest) (*TestCreateNoteRequest, error) {
+		got = *req
+		return req, nil
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/items/3/notes?sort=asc", strings.NewReader(`name=Widget&quantity=2`))
+	req.Header.Set("Content-Type", MIMEApplicationForm)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got.ItemID != 3 || got.Note.Name != "Widget" || got.Note.Quantity != 2 || got.Sort != Some("asc") {
+		t.Errorf("unexpected request %+v", got)
+	}
+
+	// Body errors are reported at the root alongside parameter errors
+	req = httptest.NewRequest(http.MethodPost, "/items/0/notes", strings.NewReader(`{"quantity":0}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	details := decodeValidationDetails(t, w)
+	fields := make(map[string]bool)
+	for _, err := range details {
+		fields[err.Field] = true
+	}
+	if len(details) != 3 || !fields["id"] || !fields["name"] || !fields["quantity"] {
+		t.Errorf("expected id, name and quantity errors, got %v", details)
+	}
+}
+
+func TestHandle_InvalidRequestType(t *testing.T) {
+	tests := []struct {
+		name   string
+		handle func()
+	}{
+		{"not a struct", func() {
+			Handle(func(ctx *Context, req *string) (*string, error) { return nil, nil })
+		}},
+		{"two body fields", func() {
+			Handle(func(ctx *Context, req *struct {
+				A TestOrderBody `body:""`
+				B TestOrderBody `body:""`
+			}) (*string, error) {
+				return nil, nil
+			})
+		}},
+		{"body field with json fields", func() {
+			Handle(func(ctx *Context, req *struct {
+				Body TestOrderBody `body:""`
+				Name string        `json:"name"`
+			}) (*string, error) {
+				return nil, nil
+			})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected Handle to panic")
+				}
+			}()
+			tt.handle()
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package nimbus
+
+import (
+	"reflect"
+	"sync"
+)
+
+// handlerSpec describes the request and response types of a typed handler (see WithTyped and
+// Handle), so routes using it are documented without RouteMetadata
+type handlerSpec struct {
+	params       *Schema      // Path parameters, matched to the route's by `path` tag (nil if none)
+	body         *Schema      // Request body (nil if none)
+	query        *Schema      // Query, header and cookie parameters (nil if none)
+	responseType reflect.Type // Type of the response data (nil if undeclared)
+	status       int          // Success status of the response (0 for 200 OK)
+	noContent    bool         // The handler responds with 204 No Content when it has no data
+}
+
+// describedCode holds the code pointers of the closures returned by describeHandler and
+// DescribeMiddleware. Func values can't be compared, but a closure's code pointer tells whether
+// one of them created it, and so whether it can be asked for its spec or doc.
+var describedCode sync.Map // uintptr -> struct{}
+
+// specProbe is the context passed to a described handler to ask for its spec
+var specProbe = new(Context)
+
+// describeHandler wraps a typed handler so the spec can be found from the handler alone when
+// the route is registered (see lookupHandlerSpec)
+func describeHandler(h Handler, spec *handlerSpec) Handler {
+	described := func(ctx *Context) (any, int, error) {
+		if ctx == specProbe {
+			return spec, 0, nil
+		}
+		return h(ctx)
+	}
+	describedCode.Store(reflect.ValueOf(described).Pointer(), struct{}{})
+	return described
+}
+
+// lookupHandlerSpec returns the spec recorded for a handler (nil for plain handlers).
+// Only handlers created by describeHandler are asked for their spec; others are never called.
+func lookupHandlerSpec(h Handler) *handlerSpec {
+	if h == nil {
+		return nil
+	}
+	if _, ok := describedCode.Load(reflect.ValueOf(h).Pointer()); !ok {
+		return nil
+	}
+	spec, _, _ := h(specProbe)
+	return spec.(*handlerSpec)
+}
+
+// MiddlewareDoc describes what a middleware adds to the routes it's applied to, so GenerateOpenAPI
+// can document it (see DescribeMiddleware)
+type MiddlewareDoc struct {
+	SecurityName   string          // Key of the scheme in components.securitySchemes (e.g., "bearerAuth")
+	SecurityScheme *SecurityScheme // Security scheme the middleware enforces (nil if none)
+	Scopes         []string        // OAuth2 scopes the middleware requires
+
+	Responses map[int]MiddlewareResponse // Error responses the middleware can send, by status code
+	Headers   map[string]OpenAPIHeader   // Headers the middleware sets on every response
+}
+
+// MiddlewareResponse describes an error response sent by a middleware (as an ErrorResponse)
+type MiddlewareResponse struct {
+	Description string
+	Headers     map[string]OpenAPIHeader // Headers sent with the response (e.g., Retry-After)
+}
+
+// DescribeMiddleware records what a middleware enforces, returning the described middleware.
+// Routes using it (directly, through a Group or with Router.Use) are documented accordingly,
+// e.g. with the middleware's security scheme. Docs are lost when the result is combined with Chain.
+//
+// Example:
+//
+//	func SessionAuth() nimbus.Middleware {
+//	    mw := func(next nimbus.Handler) nimbus.Handler { ... }
+//	    return nimbus.DescribeMiddleware(mw, nimbus.MiddlewareDoc{
+//	        SecurityName:   "sessionCookie",
+//	        SecurityScheme: &nimbus.SecurityScheme{Type: "apiKey", In: "cookie", Name: "session"},
+//	    })
+//	}
+func DescribeMiddleware(m Middleware, doc MiddlewareDoc) Middleware {
+	described := func(next Handler) Handler {
+		// A nil next handler asks for the doc (see lookupMiddlewareDoc)
+		if next == nil {
+			return func(*Context) (any, int, error) { return &doc, 0, nil }
+		}
+		return m(next)
+	}
+	describedCode.Store(reflect.ValueOf(described).Pointer(), struct{}{})
+	return described
+}
+
+// lookupMiddlewareDoc returns the doc recorded for a middleware (nil if it has none).
+// Only middleware created by DescribeMiddleware is asked for its doc; others are never called.
+func lookupMiddlewareDoc(m Middleware) *MiddlewareDoc {
+	if m == nil {
+		return nil
+	}
+	if _, ok := describedCode.Load(reflect.ValueOf(m).Pointer()); !ok {
+		return nil
+	}
+	doc, _, _ := m(nil)(nil)
+	return doc.(*MiddlewareDoc)
+}
+
+// validatorSchema returns a validator's schema (nil for a nil validator)
+func validatorSchema[T any](v *Validator[T]) *Schema {
+	if v == nil {
+		return nil
+	}
+	return v.Schema
+}
@@ -0,0 +1,88 @@
+package nimbus
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// bindHeaders binds request headers and cookies to fields tagged `header` or `cookie`, recording
+// the ones that were sent in doc. Values are converted like query parameters: slice fields accept
+// repeated headers and comma-separated values (Accept-Language: en,fr).
+func bindHeaders(r *http.Request, target any, schema *Schema, doc map[string]any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a pointer to struct")
+	}
+	v = v.Elem()
+	if v.Type() != schema.structType {
+		return fmt.Errorf("target must be a pointer to %s", schema.structType)
+	}
+
+	var bindErrs ValidationErrors
+	for _, fieldName := range schema.order {
+		rule := schema.fields[fieldName]
+		if rule.headerName == "" && rule.cookieName == "" {
+			continue
+		}
+		fieldValue := v.FieldByIndex(rule.index)
+		if !fieldValue.CanSet() {
+			continue
+		}
+
+		sent, errs := bindHeaderField(fieldValue, fieldName, rule, r)
+		if sent {
+			doc[fieldName] = true
+		}
+		bindErrs = append(bindErrs, errs...)
+	}
+
+	if len(bindErrs) > 0 {
+		return bindErrs
+	}
+	return nil
+}
+
+// bindHeaderField binds the header or cookie for a field, reporting whether it was sent
+func bindHeaderField(fieldValue reflect.Value, fieldName string, rule fieldRule, r *http.Request) (bool, ValidationErrors) {
+	values := headerValues(r, rule)
+	if len(values) == 0 {
+		return false, nil
+	}
+
+	// Optional[T] fields bind to their value
+	value := fieldValue
+	if rule.optional {
+		value = fieldValue.Field(0)
+	}
+
+	var sent bool
+	var errs ValidationErrors
+	if queryStyle(value.Type()) == "form" {
+		sent, errs = bindQuerySlice(value, fieldName, values)
+	} else if values[0] != "" {
+		// Empty headers are treated as missing
+		sent = true
+		if err := setFieldValue(value, values[0]); err != nil {
+			errs = ValidationErrors{typeError(fieldName, values[0], err)}
+		}
+	}
+
+	if sent && rule.optional {
+		fieldValue.FieldByName("Set").SetBool(true)
+	}
+	return sent, errs
+}
+
+// headerValues returns the values of the header or cookie a field is bound to
+func headerValues(r *http.Request, rule fieldRule) []string {
+	switch {
+	case rule.headerName != "":
+		return r.Header.Values(rule.headerName)
+	case rule.cookieName != "":
+		if cookie, err := r.Cookie(rule.cookieName); err == nil {
+			return []string{cookie.Value}
+		}
+	}
+	return nil
+}
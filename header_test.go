@@ -0,0 +1,144 @@
+package nimbus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type TestTenantQuery struct {
+	Limit          int              `json:"limit" default:"20"`
+	TenantID       string           `header:"X-Tenant-ID" sanitize:"lower" validate:"required,minlen=3"`
+	IdempotencyKey Optional[string] `header:"Idempotency-Key"`
+	Languages      []string         `header:"Accept-Language" validate:"dive,len=2"`
+	Version        int              `header:"X-API-Version" validate:"min=1"`
+	Session        string           `cookie:"session" validate:"required"`
+}
+
+func TestBindAndValidateQuery_HeadersAndCookies(t *testing.T) {
+	validator := NewValidator(&TestTenantQuery{})
+	router := NewRouter()
+	var got TestTenantQuery
+	router.AddRoute(http.MethodGet, "/orders", WithTyped(
+		func(ctx *Context, req *TypedRequest[struct{}, struct{}, TestTenantQuery]) (any, int, error) {
+			got = *req.Query
+			return nil, 204, nil
+		}, nil, nil, validator))
+
+	tests := []struct {
+		name     string
+		query    string
+		headers  map[string][]string
+		cookie   string
+		expected TestTenantQuery
+		errors   map[string]string // field -> tag
+	}{
+		{
+			name: "bound",
+			headers: map[string][]string{
+				"X-Tenant-ID":     {"ACME"},
+				"Idempotency-Key": {"abc123"},
+				"Accept-Language": {"en,fr", "de"},
+				"X-API-Version":   {"2"},
+			},
+			cookie: "s3cr3t",
+			expected: TestTenantQuery{
+				Limit:          20,
+				TenantID:       "acme",
+				IdempotencyKey: Some("abc123"),
+				Languages:      []string{"en", "fr", "de"},
+				Version:        2,
+				Session:        "s3cr3t",
+			},
+		},
+		{
+			name:   "missing",
+			errors: map[string]string{"X-Tenant-ID": "required", "session": "required"},
+		},
+		{
+			name: "invalid",
+			headers: map[string][]string{
+				"X-Tenant-ID":     {"ab"},
+				"Accept-Language": {"english"},
+				"X-API-Version":   {"latest"},
+			},
+			cookie: "s3cr3t",
+			errors: map[string]string{"X-Tenant-ID": "minlen", "Accept-Language[0]": "len", "X-API-Version": "type"},
+		},
+		{
+			name:    "invalid query and headers",
+			query:   "?limit=ten",
+			headers: map[string][]string{"X-Tenant-ID": {"ACME"}, "X-API-Version": {"latest"}},
+			cookie:  "s3cr3t",
+			errors:  map[string]string{"limit": "type", "X-API-Version": "type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = TestTenantQuery{}
+			req := httptest.NewRequest(http.MethodGet, "/orders"+tt.query, nil)
+			for name, values := range tt.headers {
+				for _, value := range values {
+					req.Header.Add(name, value)
+				}
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "session", Value: tt.cookie})
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if tt.errors == nil {
+				if w.Code != 204 {
+					t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+				}
+				if !reflect.DeepEqual(got, tt.expected) {
+					t.Errorf("expected %+v, got %+v", tt.expected, got)
+				}
+				return
+			}
+
+			details := decodeValidationDetails(t, w)
+			if len(details) != len(tt.errors) {
+				t.Fatalf("expected %d errors, got %v", len(tt.errors), details)
+			}
+			for _, err := range details {
+				if tag, ok := tt.errors[err.Field]; !ok || tag != err.Tag {
+					t.Errorf("unexpected error %s/%s: %s", err.Field, err.Tag, err.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaToQueryParameters_HeadersAndCookies(t *testing.T) {
+	params := make(map[string]OpenAPIParameter)
+	for _, param := range schemaToQueryParameters(NewSchema(TestTenantQuery{}), nil) {
+		params[param.Name] = param
+	}
+
+	expected := map[string]string{
+		"limit":           "query",
+		"X-Tenant-ID":     "header",
+		"Idempotency-Key": "header",
+		"Accept-Language": "header",
+		"X-API-Version":   "header",
+		"session":         "cookie",
+	}
+	if len(params) != len(expected) {
+		t.Fatalf("expected %d parameters, got %+v", len(expected), params)
+	}
+	for name, in := range expected {
+		if params[name].In != in {
+			t.Errorf("expected %s to be in %s, got %+v", name, in, params[name])
+		}
+	}
+	if p := params["X-Tenant-ID"]; !p.Required || p.Schema.MinLength == nil {
+		t.Errorf("expected required header with minLength, got %+v", p)
+	}
+	if p := params["Accept-Language"]; p.Style != "" || p.Schema.Type != "array" {
+		t.Errorf("expected header array without query style, got %+v", p)
+	}
+}
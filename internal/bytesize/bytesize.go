@@ -0,0 +1,68 @@
+// Package bytesize parses and formats human-readable byte sizes ("512", "1.5MB", "2GB"),
+// shared by the maxsize validation rule and the body limit middleware.
+package bytesize
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Size units
+const (
+	B  = 1
+	KB = 1024 * B
+	MB = 1024 * KB
+	GB = 1024 * MB
+)
+
+// Parse converts a human-readable size string to bytes.
+// Supports: "1B", "1KB", "1MB", "1GB" and the short forms "1K", "1M", "1G" (case-insensitive).
+// Also supports decimals ("1.5MB", "0.5GB"); a number without a unit is in bytes.
+func Parse(size string) (int64, error) {
+	size = strings.TrimSpace(strings.ToUpper(size))
+
+	// Extract number and unit
+	numStr, unit := size, ""
+	if i := strings.IndexFunc(size, func(c rune) bool { return (c < '0' || c > '9') && c != '.' }); i >= 0 {
+		numStr, unit = size[:i], strings.TrimSpace(size[i:])
+	}
+	if numStr == "" {
+		return 0, fmt.Errorf("invalid size format: %s", size)
+	}
+
+	value, err := strconv.ParseFloat(numStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %s", numStr)
+	}
+
+	var multiplier int64
+	switch unit {
+	case "B", "":
+		multiplier = B
+	case "KB", "K":
+		multiplier = KB
+	case "MB", "M":
+		multiplier = MB
+	case "GB", "G":
+		multiplier = GB
+	default:
+		return 0, fmt.Errorf("unknown unit: %s (use B, KB, MB, or GB)", unit)
+	}
+
+	return int64(value * float64(multiplier)), nil
+}
+
+// Format converts bytes to a human-readable size ("512B", "1.50KB")
+func Format(bytes int64) string {
+	switch {
+	case bytes >= GB:
+		return fmt.Sprintf("%.2fGB", float64(bytes)/float64(GB))
+	case bytes >= MB:
+		return fmt.Sprintf("%.2fMB", float64(bytes)/float64(MB))
+	case bytes >= KB:
+		return fmt.Sprintf("%.2fKB", float64(bytes)/float64(KB))
+	default:
+		return fmt.Sprintf("%dB", bytes)
+	}
+}
@@ -0,0 +1,81 @@
+package bytesize
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		hasError bool
+	}{
+		// Valid formats
+		{"1B", 1, false},
+		{"1KB", 1024, false},
+		{"1MB", 1024 * 1024, false},
+		{"1GB", 1024 * 1024 * 1024, false},
+
+		// With spaces
+		{" 1MB ", 1024 * 1024, false},
+		{"5 MB", 5 * 1024 * 1024, false},
+
+		// Case insensitive
+		{"1kb", 1024, false},
+		{"5mb", 5 * 1024 * 1024, false},
+		{"1gb", 1024 * 1024 * 1024, false},
+
+		// Decimals
+		{"1.5MB", int64(1.5 * 1024 * 1024), false},
+		{"0.5GB", int64(0.5 * 1024 * 1024 * 1024), false},
+		{"1.5KB", 1536, false},
+
+		// Short forms
+		{"1K", 1024, false},
+		{"1M", 1024 * 1024, false},
+		{"1G", 1024 * 1024 * 1024, false},
+
+		// Just number (assumes bytes)
+		{"512", 512, false},
+
+		// Invalid formats
+		{"", 0, true},
+		{"invalid", 0, true},
+		{"1XB", 0, true},
+		{"MB", 0, true},
+		{"-1MB", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := Parse(tt.input)
+			if (err != nil) != tt.hasError {
+				t.Fatalf("Parse(%q) error = %v, expected error: %v", tt.input, err, tt.hasError)
+			}
+			if result != tt.expected {
+				t.Errorf("Parse(%q) = %d, expected %d", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		bytes    int64
+		expected string
+	}{
+		{100, "100B"},
+		{1024, "1.00KB"},
+		{1536, "1.50KB"},
+		{1048576, "1.00MB"},
+		{2097152, "2.00MB"},
+		{1073741824, "1.00GB"},
+		{2147483648, "2.00GB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if result := Format(tt.bytes); result != tt.expected {
+				t.Errorf("Format(%d) = %q, expected %q", tt.bytes, result, tt.expected)
+			}
+		})
+	}
+}
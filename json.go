@@ -0,0 +1,823 @@
+package nimbus
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultJSONMaxDepth matches the nesting limit of encoding/json
+const defaultJSONMaxDepth = 10000
+
+// JSONOptions configures how JSON bodies are decoded for a schema.
+type JSONOptions struct {
+	DisallowUnknownFields bool // Reject keys that don't match a field of the target struct
+	DisallowDuplicateKeys bool // Reject objects that repeat a key
+	MaxDepth              int  // Maximum nesting of objects and arrays (0 = 10000, the encoding/json limit)
+}
+
+// StrictJSON rejects unknown fields and duplicate keys and limits nesting to 32 levels.
+var StrictJSON = JSONOptions{
+	DisallowUnknownFields: true,
+	DisallowDuplicateKeys: true,
+	MaxDepth:              32,
+}
+
+// WithJSONOptions sets the options used when decoding JSON bodies for this schema.
+//
+// Example:
+//
+//	schema := nimbus.NewSchema(CreateUserRequest{}).WithJSONOptions(nimbus.StrictJSON)
+func (s *Schema) WithJSONOptions(opts JSONOptions) *Schema {
+	s.jsonOptions = opts
+	return s
+}
+
+// WithJSONOptions sets the options used when decoding JSON bodies for this validator.
+//
+// Example:
+//
+//	var createUserValidator = nimbus.NewValidator(&CreateUserRequest{}).WithJSONOptions(nimbus.StrictJSON)
+func (v *Validator[T]) WithJSONOptions(opts JSONOptions) *Validator[T] {
+	v.Schema.WithJSONOptions(opts)
+	return v
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonNumberType      = reflect.TypeOf(json.Number(""))
+)
+
+// decodeJSONDocument decodes a JSON value from r into target in a single streaming pass.
+// A token scanner walks the input alongside target's type: it enforces the schema's JSON options,
+// reports type mismatches with their field path and byte offset, stores each value into target
+// following encoding/json's rules, and builds the generic document used for presence checks.
+// Types with their own json.Unmarshaler are the one exception: UnmarshalJSON takes raw bytes,
+// so their values are read as a json.RawMessage and handed to it.
+//
+// Field-level problems (type mismatches, unknown fields, duplicate keys) are returned as
+// ValidationErrors alongside the document. Syntax and depth errors are returned wrapped as
+// "invalid JSON" errors that still unwrap to ValidationErrors.
+func decodeJSONDocument(r io.Reader, target any, schema *Schema) (any, error) {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, fmt.Errorf("invalid JSON: %w", &json.InvalidUnmarshalError{Type: reflect.TypeOf(target)})
+	}
+
+	s := &jsonScanner{
+		dec:      json.NewDecoder(r),
+		maxDepth: defaultJSONMaxDepth,
+	}
+	s.dec.UseNumber()
+	if schema != nil {
+		s.opts = schema.jsonOptions
+		if s.opts.MaxDepth > 0 {
+			s.maxDepth = s.opts.MaxDepth
+		}
+	}
+
+	doc, err := s.scan(v, "", 0)
+	if err == nil {
+		if _, tokErr := s.dec.Token(); tokErr != io.EOF {
+			err = s.syntaxError("", errors.New("unexpected data after JSON value"))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.errs) > 0 {
+		return doc, s.errs
+	}
+	return doc, nil
+}
+
+// jsonScanner walks a JSON token stream alongside the Go value it decodes into
+type jsonScanner struct {
+	dec      *json.Decoder
+	opts     JSONOptions
+	maxDepth int
+	base     int64 // Offset of the decoder's input in the request body
+	errs     ValidationErrors
+}
+
+// scan reads one value into v (an invalid Value accepts and discards any value)
+// and returns it as a generic document
+func (s *jsonScanner) scan(v reflect.Value, path string, depth int) (any, error) {
+	if v.IsValid() && decodesWithUnmarshaler(v.Type()) {
+		return s.scanRaw(v, path, depth)
+	}
+
+	tok, err := s.dec.Token()
+	if err != nil {
+		return nil, s.readError(path, err)
+	}
+	if tok == nil {
+		storeJSONNull(v)
+		return nil, nil
+	}
+	v = indirectJSON(v)
+
+	switch {
+	case !v.IsValid():
+	case v.Kind() == reflect.Interface:
+		if v.NumMethod() > 0 {
+			s.typeError(path, nil, fmt.Sprintf("%s must be of type %s", fieldLabel(path), v.Type()))
+			return s.scanToken(tok, reflect.Value{}, path, depth)
+		}
+		// Stored like encoding/json does: objects as map[string]any, arrays as []any, numbers as float64
+		doc, err := s.scanToken(tok, reflect.Value{}, path, depth)
+		if err == nil {
+			v.Set(reflect.ValueOf(jsonInterfaceValue(doc)))
+		}
+		return doc, err
+	case reflect.PointerTo(v.Type()).Implements(textUnmarshalerType):
+		str, ok := tok.(string)
+		if !ok {
+			s.typeError(path, nil, fmt.Sprintf("%s must be a string", fieldLabel(path)))
+			return s.scanToken(tok, reflect.Value{}, path, depth)
+		}
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str)); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return str, nil
+	}
+	return s.scanToken(tok, v, path, depth)
+}
+
+// scanToken reads the value starting with tok into v
+func (s *jsonScanner) scanToken(tok json.Token, v reflect.Value, path string, depth int) (any, error) {
+	switch tok := tok.(type) {
+	case json.Delim:
+		if depth >= s.maxDepth {
+			return nil, fmt.Errorf("invalid JSON: %w", ValidationErrors{{
+				Field:   path,
+				Tag:     "depth",
+				Message: fmt.Sprintf("JSON nesting exceeds the maximum depth of %d", s.maxDepth),
+				Offset:  s.offset(),
+			}})
+		}
+		if tok == '{' {
+			return s.scanObject(v, path, depth+1)
+		}
+		return s.scanArray(v, path, depth+1)
+	case string:
+		return tok, s.storeString(v, path, tok)
+	case json.Number:
+		s.storeNumber(v, path, tok)
+	case bool:
+		if v.IsValid() && v.Kind() == reflect.Bool {
+			v.SetBool(tok)
+		} else {
+			s.checkType(v, path, "boolean", tok)
+		}
+	}
+	return tok, nil
+}
+
+func (s *jsonScanner) scanObject(v reflect.Value, path string, depth int) (any, error) {
+	var fields map[string]jsonField
+	switch {
+	case !v.IsValid():
+	case v.Kind() == reflect.Struct:
+		fields = jsonFields(v.Type())
+	case v.Kind() == reflect.Map:
+		if !isJSONMapKey(v.Type().Key()) {
+			s.typeError(path, nil, fmt.Sprintf("%s must be of type %s", fieldLabel(path), v.Type()))
+			v = reflect.Value{}
+		} else if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+	default:
+		s.checkType(v, path, "object", nil)
+		v = reflect.Value{}
+	}
+
+	obj := make(map[string]any)
+	seen := make(map[string]struct{})
+	for s.dec.More() {
+		tok, err := s.dec.Token()
+		if err != nil {
+			return nil, s.readError(path, err)
+		}
+		key, _ := tok.(string)
+		keyPath := joinJSONPath(path, key)
+
+		// Keys that differ only in case decode into the same struct field
+		var field jsonField
+		seenKey := key
+		if fields != nil {
+			name, f, known := lookupJSONField(fields, key)
+			if !known && s.opts.DisallowUnknownFields {
+				s.errs = append(s.errs, ValidationError{
+					Field:   keyPath,
+					Tag:     "unknown",
+					Message: fmt.Sprintf("%s is not a known field", keyPath),
+					Offset:  s.offset(),
+				})
+			}
+			if known {
+				seenKey, field = name, f
+			}
+		}
+
+		if _, dup := seen[seenKey]; dup && s.opts.DisallowDuplicateKeys {
+			s.errs = append(s.errs, ValidationError{
+				Field:   joinJSONPath(path, seenKey),
+				Tag:     "duplicate",
+				Message: fmt.Sprintf("%s appears more than once", joinJSONPath(path, seenKey)),
+				Offset:  s.offset(),
+			})
+		}
+		seen[seenKey] = struct{}{}
+
+		var value any
+		switch {
+		case v.IsValid() && v.Kind() == reflect.Map:
+			// Like encoding/json, each value is decoded into a new element before it's stored
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if value, err = s.scan(elem, keyPath, depth); err == nil {
+				if mapKey, ok := s.mapKey(v.Type().Key(), key, path); ok {
+					v.SetMapIndex(mapKey, elem)
+				}
+			}
+		case field.quoted:
+			value, err = s.scanQuoted(field.value(v), keyPath)
+		default:
+			value, err = s.scan(field.value(v), keyPath, depth)
+		}
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = value
+	}
+
+	// Closing delimiter
+	if _, err := s.dec.Token(); err != nil {
+		return nil, s.readError(path, err)
+	}
+	return obj, nil
+}
+
+func (s *jsonScanner) scanArray(v reflect.Value, path string, depth int) (any, error) {
+	if v.IsValid() && !((v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8) || v.Kind() == reflect.Array) {
+		s.checkType(v, path, "array", nil)
+		v = reflect.Value{}
+	}
+
+	items := []any{}
+	for i := 0; s.dec.More(); i++ {
+		var elem reflect.Value
+		if v.IsValid() {
+			if v.Kind() == reflect.Slice && i >= v.Len() {
+				if i >= v.Cap() {
+					v.Grow(1)
+				}
+				v.SetLen(i + 1)
+			}
+			// Elements past the end of an array are discarded
+			if i < v.Len() {
+				elem = v.Index(i)
+				elem.SetZero()
+			}
+		}
+
+		value, err := s.scan(elem, fmt.Sprintf("%s[%d]", path, i), depth)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+	}
+
+	// Closing delimiter
+	if _, err := s.dec.Token(); err != nil {
+		return nil, s.readError(path, err)
+	}
+
+	switch {
+	case !v.IsValid():
+	case v.Kind() == reflect.Slice && len(items) == 0:
+		v.Set(reflect.MakeSlice(v.Type(), 0, 0)) // [] decodes to an empty slice, not nil
+	case v.Kind() == reflect.Slice:
+		v.SetLen(len(items))
+	default:
+		for i := len(items); i < v.Len(); i++ {
+			v.Index(i).SetZero()
+		}
+	}
+	return items, nil
+}
+
+// scanRaw decodes a value whose type has its own json.Unmarshaler, which takes the value's raw bytes
+func (s *jsonScanner) scanRaw(v reflect.Value, path string, depth int) (any, error) {
+	var raw json.RawMessage
+	if err := s.dec.Decode(&raw); err != nil {
+		return nil, s.readError(path, err)
+	}
+	start := s.offset() - int64(len(raw))
+
+	// The raw value is scanned for the document, so the schema's JSON options apply inside it too
+	sub := &jsonScanner{dec: json.NewDecoder(bytes.NewReader(raw)), opts: s.opts, maxDepth: s.maxDepth, base: start}
+	sub.dec.UseNumber()
+	doc, err := sub.scan(reflect.Value{}, path, depth)
+	if err != nil {
+		return nil, err
+	}
+	s.errs = append(s.errs, sub.errs...)
+
+	// Null sets a pointer to nil and marks an Optional null; other values see the null themselves
+	null := doc == nil
+	for {
+		switch {
+		case v.Kind() == reflect.Ptr:
+			if null && v.CanSet() {
+				v.SetZero()
+				return nil, nil
+			}
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+			continue
+		case isOptionalType(v.Type()):
+			markOptional(v, null)
+			if null {
+				return nil, nil
+			}
+			v = v.Field(0)
+			continue
+		}
+		break
+	}
+
+	if err := v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(raw); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		field := path
+		if typeErr.Field != "" {
+			field = joinJSONPath(path, typeErr.Field)
+		}
+		s.errs = append(s.errs, ValidationError{
+			Field:   field,
+			Value:   typeErr.Value,
+			Tag:     "type",
+			Message: fmt.Sprintf("%s must be of type %s", fieldLabel(field), typeErr.Type),
+			Offset:  start + typeErr.Offset,
+		})
+	}
+	return doc, nil
+}
+
+// scanQuoted reads a field with the ",string" option, whose value is encoded inside a JSON string
+func (s *jsonScanner) scanQuoted(v reflect.Value, path string) (any, error) {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return nil, s.readError(path, err)
+	}
+	if tok == nil {
+		storeJSONNull(v)
+		return nil, nil
+	}
+	if _, ok := tok.(json.Delim); ok {
+		s.typeError(path, nil, fmt.Sprintf("%s must be a string", fieldLabel(path)))
+		return s.scanToken(tok, reflect.Value{}, path, 0)
+	}
+
+	str, ok := tok.(string)
+	var inner json.Token
+	if ok {
+		dec := json.NewDecoder(strings.NewReader(str))
+		dec.UseNumber()
+		inner, err = dec.Token()
+	}
+	if _, delim := inner.(json.Delim); !ok || err != nil || delim {
+		s.typeError(path, tok, fmt.Sprintf("%s must be a string", fieldLabel(path)))
+		return tok, nil
+	}
+
+	if inner == nil {
+		storeJSONNull(v)
+	} else if _, err := s.scanToken(inner, indirectJSON(v), path, 0); err != nil {
+		return nil, err
+	}
+	return tok, nil
+}
+
+// storeString stores a JSON string into v: string kinds, json.Number and base64-encoded []byte accept it
+func (s *jsonScanner) storeString(v reflect.Value, path, str string) error {
+	switch {
+	case !v.IsValid():
+	case v.Type() == jsonNumberType:
+		if !isJSONNumber(str) {
+			s.typeError(path, str, fmt.Sprintf("%s must be a number", fieldLabel(path)))
+			return nil
+		}
+		v.SetString(str)
+	case v.Kind() == reflect.String:
+		v.SetString(str)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		b, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		v.SetBytes(b)
+	default:
+		s.checkType(v, path, "string", str)
+	}
+	return nil
+}
+
+// storeNumber stores a JSON number into v, recording a type error if it doesn't fit
+func (s *jsonScanner) storeNumber(v reflect.Value, path string, num json.Number) {
+	if !v.IsValid() {
+		return
+	}
+
+	var err error
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(num.String(), 10, v.Type().Bits()); err == nil {
+			v.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		if n, err = strconv.ParseUint(num.String(), 10, v.Type().Bits()); err == nil {
+			v.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		var n float64
+		if n, err = strconv.ParseFloat(num.String(), v.Type().Bits()); err == nil {
+			v.SetFloat(n)
+		}
+	default:
+		if v.Type() == jsonNumberType {
+			v.SetString(num.String())
+			return
+		}
+		s.checkType(v, path, "number", num)
+		return
+	}
+
+	if err != nil {
+		s.typeError(path, num, fmt.Sprintf("%s must be %s", fieldLabel(path), jsonKindDescriptions[jsonKindOf(v.Type())]))
+	}
+}
+
+// mapKey converts an object key to a key of type t, recording a type error if it doesn't fit
+func (s *jsonScanner) mapKey(t reflect.Type, key, path string) (reflect.Value, bool) {
+	k := reflect.New(t).Elem()
+
+	var err error
+	switch {
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		err = k.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key))
+	case t.Kind() == reflect.String:
+		k.SetString(key)
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(key, 10, t.Bits()); err == nil {
+			k.SetInt(n)
+		}
+	default:
+		var n uint64
+		if n, err = strconv.ParseUint(key, 10, t.Bits()); err == nil {
+			k.SetUint(n)
+		}
+	}
+
+	if err != nil {
+		keyPath := joinJSONPath(path, key)
+		s.typeError(keyPath, key, fmt.Sprintf("%s is not a valid key of %s", key, fieldLabel(path)))
+		return k, false
+	}
+	return k, true
+}
+
+// checkType records a type error if a JSON value of kind got can't be decoded into v
+func (s *jsonScanner) checkType(v reflect.Value, path, got string, value any) {
+	if !v.IsValid() {
+		return
+	}
+	expected := jsonKindOf(v.Type())
+	if expected == "" || expected == got {
+		return
+	}
+	s.typeError(path, value, fmt.Sprintf("%s must be %s", fieldLabel(path), jsonKindDescriptions[expected]))
+}
+
+func (s *jsonScanner) typeError(path string, value any, message string) {
+	s.errs = append(s.errs, ValidationError{
+		Field:   path,
+		Value:   value,
+		Tag:     "type",
+		Message: message,
+		Offset:  s.offset(),
+	})
+}
+
+// offset returns the position of the decoder in the request body
+func (s *jsonScanner) offset() int64 {
+	return s.base + s.dec.InputOffset()
+}
+
+// readError translates token errors: malformed or truncated input becomes a syntax
+// ValidationError with its byte offset, while read errors (e.g., body too large) are returned as is.
+func (s *jsonScanner) readError(path string, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return s.syntaxError(path, err)
+	}
+	return err
+}
+
+func (s *jsonScanner) syntaxError(path string, err error) error {
+	offset := s.offset()
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = s.base + syntaxErr.Offset
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = errors.New("unexpected end of JSON input")
+	}
+
+	return fmt.Errorf("invalid JSON: %w", ValidationErrors{{
+		Field:   path,
+		Tag:     "syntax",
+		Message: fmt.Sprintf("invalid JSON at offset %d: %s", offset, strings.TrimPrefix(err.Error(), "json: ")),
+		Offset:  offset,
+	}})
+}
+
+var jsonKindDescriptions = map[string]string{
+	"string":   "a string",
+	"integer":  "an integer",
+	"unsigned": "a non-negative integer",
+	"number":   "a number",
+	"boolean":  "a boolean",
+	"object":   "an object",
+	"array":    "an array",
+}
+
+// jsonKindOf returns the kind of JSON value t decodes from ("" if any value may fit)
+func jsonKindOf(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "unsigned"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // []byte is base64-encoded
+		}
+		return "array"
+	case reflect.Array:
+		return "array"
+	}
+	return ""
+}
+
+// decodesWithUnmarshaler reports whether values of type t (or what its pointers and Optional
+// wrappers hold) are decoded by their own json.Unmarshaler
+func decodesWithUnmarshaler(t reflect.Type) bool {
+	for {
+		switch {
+		case t.Kind() == reflect.Ptr:
+			t = t.Elem()
+		case isOptionalType(t):
+			t = t.Field(0).Type
+		default:
+			return t.Kind() != reflect.Interface && reflect.PointerTo(t).Implements(jsonUnmarshalerType)
+		}
+	}
+}
+
+// indirectJSON walks v through pointers (allocating nil ones) and Optional wrappers (marking them set)
+// to the value a non-null JSON value is stored in
+func indirectJSON(v reflect.Value) reflect.Value {
+	for v.IsValid() {
+		switch {
+		case v.Kind() == reflect.Ptr:
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		case isOptionalType(v.Type()):
+			markOptional(v, false)
+			v = v.Field(0)
+		default:
+			return v
+		}
+	}
+	return v
+}
+
+// storeJSONNull stores a JSON null into v like encoding/json: pointers, interfaces, maps and slices
+// become nil, Optional values are marked null, and other values are left as they are
+func storeJSONNull(v reflect.Value) {
+	for v.IsValid() {
+		switch {
+		case v.Kind() == reflect.Ptr && !v.CanSet():
+			v = v.Elem() // The decode target itself
+		case v.Kind() == reflect.Ptr, v.Kind() == reflect.Interface, v.Kind() == reflect.Map, v.Kind() == reflect.Slice:
+			v.SetZero()
+			return
+		case isOptionalType(v.Type()):
+			markOptional(v, true)
+			return
+		default:
+			return
+		}
+	}
+}
+
+// markOptional records an Optional as sent, clearing its value if it's null
+func markOptional(v reflect.Value, null bool) {
+	// Optional's fields are Value, Set and Null
+	v.Field(1).SetBool(true)
+	v.Field(2).SetBool(null)
+	if null {
+		v.Field(0).SetZero()
+	}
+}
+
+// jsonInterfaceValue converts a document value to what encoding/json stores in an interface:
+// the same maps, slices, strings and booleans, with numbers as float64
+func jsonInterfaceValue(doc any) any {
+	switch doc := doc.(type) {
+	case map[string]any:
+		obj := make(map[string]any, len(doc))
+		for key, value := range doc {
+			obj[key] = jsonInterfaceValue(value)
+		}
+		return obj
+	case []any:
+		items := make([]any, len(doc))
+		for i, value := range doc {
+			items[i] = jsonInterfaceValue(value)
+		}
+		return items
+	case json.Number:
+		f, _ := doc.Float64()
+		return f
+	}
+	return doc
+}
+
+// isJSONMapKey reports whether encoding/json can decode object keys into type t
+func isJSONMapKey(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// isJSONNumber reports whether s is a valid JSON number literal
+func isJSONNumber(s string) bool {
+	return s != "" && (s[0] == '-' || (s[0] >= '0' && s[0] <= '9')) && json.Valid([]byte(s))
+}
+
+// jsonField is a JSON key of a struct type and the field it decodes into
+type jsonField struct {
+	index  []int // Field index, through embedded structs
+	quoted bool  // Field uses the ",string" option, so its value is encoded in a JSON string
+}
+
+// value returns the field of struct v, allocating embedded struct pointers on the way.
+// Returns an invalid Value (discarding the JSON value) if the field is unset or can't be set.
+func (f jsonField) value(v reflect.Value) reflect.Value {
+	if f.index == nil || !v.IsValid() {
+		return reflect.Value{}
+	}
+	for i, x := range f.index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{} // Embedded pointer to an unexported struct
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	if !v.CanSet() {
+		return reflect.Value{}
+	}
+	return v
+}
+
+// jsonFieldCache caches the JSON fields of struct types
+var jsonFieldCache sync.Map // map[reflect.Type]map[string]jsonField
+
+// jsonFields returns the JSON keys encoding/json decodes into a struct, mapped to their fields.
+// Fields of embedded structs are promoted.
+func jsonFields(t reflect.Type) map[string]jsonField {
+	if cached, ok := jsonFieldCache.Load(t); ok {
+		return cached.(map[string]jsonField)
+	}
+
+	fields := make(map[string]jsonField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, promoted := range jsonFields(embedded) {
+					if _, exists := fields[key]; !exists {
+						promoted.index = append([]int{i}, promoted.index...)
+						fields[key] = promoted
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = jsonField{
+			index:  []int{i},
+			quoted: strings.Contains(","+opts+",", ",string,") && isQuotableKind(field.Type),
+		}
+	}
+
+	jsonFieldCache.Store(t, fields)
+	return fields
+}
+
+// isQuotableKind reports whether the ",string" option applies to fields of type t
+func isQuotableKind(t reflect.Type) bool {
+	if t.Name() == "" && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// lookupJSONField matches a key to a field name and field, preferring an exact match like encoding/json
+func lookupJSONField(fields map[string]jsonField, key string) (string, jsonField, bool) {
+	if field, ok := fields[key]; ok {
+		return key, field, true
+	}
+	for name, field := range fields {
+		if strings.EqualFold(name, key) {
+			return name, field, true
+		}
+	}
+	return "", jsonField{}, false
+}
+
+func joinJSONPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// fieldLabel names a value in messages ("value" for the document root)
+func fieldLabel(path string) string {
+	if path == "" {
+		return "value"
+	}
+	return path
+}
@@ -0,0 +1,241 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+type TestStrictItem struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1"`
+}
+
+type TestStrictOrder struct {
+	Customer string            `json:"customer" validate:"required"`
+	Items    []TestStrictItem  `json:"items"`
+	Labels   map[string]string `json:"labels"`
+	Express  bool              `json:"express"`
+	Count    uint8             `json:"count"`
+}
+
+func TestValidateJSON_DecodeErrors(t *testing.T) {
+	lenient := NewSchema(TestStrictOrder{})
+	strict := NewSchema(TestStrictOrder{}).WithJSONOptions(StrictJSON)
+	shallow := NewSchema(TestStrictOrder{}).WithJSONOptions(JSONOptions{MaxDepth: 2})
+
+	tests := []struct {
+		name         string
+		schema       *Schema
+		body         string
+		expectedTags map[string]string // field -> tag
+	}{
+		{
+			name:   "valid",
+			schema: strict,
+			body:   `{"customer":"Acme","items":[{"sku":"A1","quantity":2}],"labels":{"env":"prod"}}`,
+		},
+		{
+			name:         "type mismatch in nested element",
+			schema:       lenient,
+			body:         `{"customer":"Acme","items":[{"sku":"A1","quantity":1},{"sku":"B2","quantity":"2"}]}`,
+			expectedTags: map[string]string{"items[1].quantity": "type"},
+		},
+		{
+			name:         "type errors replace rule errors",
+			schema:       lenient,
+			body:         `{"customer":42,"express":"yes","count":-1,"items":{}}`,
+			expectedTags: map[string]string{"customer": "type", "express": "type", "count": "type", "items": "type"},
+		},
+		{
+			name:         "fractional integer",
+			schema:       lenient,
+			body:         `{"customer":"Acme","items":[{"sku":"A1","quantity":1.5}]}`,
+			expectedTags: map[string]string{"items[0].quantity": "type"},
+		},
+		{
+			name:   "unknown fields and duplicates allowed by default",
+			schema: lenient,
+			body:   `{"customer":"Acme","customer":"Acme Inc","coupon":"FREE"}`,
+		},
+		{
+			name:         "unknown fields",
+			schema:       strict,
+			body:         `{"customer":"Acme","coupon":"FREE","items":[{"sku":"A1","qty":1}]}`,
+			expectedTags: map[string]string{"coupon": "unknown", "items[0].qty": "unknown"},
+		},
+		{
+			name:         "duplicate keys",
+			schema:       strict,
+			body:         `{"customer":"Acme","labels":{"env":"prod","env":"dev"}}`,
+			expectedTags: map[string]string{"labels.env": "duplicate"},
+		},
+		{
+			name:         "map keys are never unknown",
+			schema:       strict,
+			body:         `{"customer":"Acme","labels":{"anything":"goes"},"Customer":"Acme"}`,
+			expectedTags: map[string]string{"customer": "duplicate"},
+		},
+		{
+			name:         "max depth",
+			schema:       shallow,
+			body:         `{"customer":"Acme","items":[{"sku":"A1"}]}`,
+			expectedTags: map[string]string{"items[0]": "depth"},
+		},
+		{
+			name:         "syntax error",
+			schema:       lenient,
+			body:         `{"customer":"Acme",}`,
+			expectedTags: map[string]string{"": "syntax"},
+		},
+		{
+			name:         "trailing data",
+			schema:       lenient,
+			body:         `{"customer":"Acme"} {}`,
+			expectedTags: map[string]string{"": "syntax"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var order TestStrictOrder
+			err := ValidateJSON([]byte(tt.body), &order, tt.schema)
+
+			if len(tt.expectedTags) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationErrs ValidationErrors
+			if !errors.As(err, &validationErrs) {
+				t.Fatalf("expected ValidationErrors, got %v", err)
+			}
+			if len(validationErrs) != len(tt.expectedTags) {
+				t.Fatalf("expected %d errors, got %v", len(tt.expectedTags), validationErrs)
+			}
+			for _, ve := range validationErrs {
+				if tag, ok := tt.expectedTags[ve.Field]; !ok || tag != ve.Tag {
+					t.Errorf("unexpected error %s/%s: %s", ve.Field, ve.Tag, ve.Message)
+				}
+				if ve.Offset <= 0 {
+					t.Errorf("expected a byte offset for %s, got %d", ve.Field, ve.Offset)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateJSON_TypeErrorMessage(t *testing.T) {
+	body := `{"customer":"Acme","items":[{"sku":"A1","quantity":"2"}]}`
+
+	var order TestStrictOrder
+	err := ValidateJSON([]byte(body), &order, NewSchema(TestStrictOrder{}))
+
+	validationErrs, ok := err.(ValidationErrors)
+	if !ok || len(validationErrs) != 1 {
+		t.Fatalf("expected 1 validation error, got %v", err)
+	}
+	if ve := validationErrs[0]; ve.Message != "items[0].quantity must be an integer" || ve.Offset != int64(strings.Index(body, `"2"`)+3) {
+		t.Errorf("unexpected error: %+v", ve)
+	}
+	if order.Customer != "Acme" || order.Items[0].SKU != "A1" {
+		t.Errorf("expected other fields to be bound, got %+v", order)
+	}
+}
+
+func TestWithTyped_StrictJSON(t *testing.T) {
+	validator := NewValidator(&TestStrictOrder{}).WithJSONOptions(StrictJSON)
+
+	router := NewRouter()
+	router.AddRoute(http.MethodPost, "/orders", WithTyped(
+		func(ctx *Context, req *TypedRequest[struct{}, TestStrictOrder, struct{}]) (any, int, error) {
+			return req.Body, 201, nil
+		}, nil, validator, nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"customer":"Acme","admin":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	details := decodeValidationDetails(t, w)
+	if len(details) != 1 || details[0].Field != "admin" || details[0].Tag != "unknown" {
+		t.Errorf("expected unknown field error for admin, got %+v", details)
+	}
+}
+
+type TestDecodeBase struct {
+	ID      int    `json:"id"`
+	Created string `json:"created"`
+}
+
+type TestDecodeTarget struct {
+	TestDecodeBase
+	*TestStrictItem
+	Name     string            `json:"name"`
+	Nick     *string           `json:"nick"`
+	Scores   map[int]float64   `json:"scores"`
+	Extra    any               `json:"extra"`
+	Amount   json.Number       `json:"amount"`
+	Blob     []byte            `json:"blob"`
+	Port     int               `json:"port,string"`
+	When     time.Time         `json:"when"`
+	Addr     net.IP            `json:"addr"`
+	Pair     [2]string         `json:"pair"`
+	Tags     []string          `json:"tags"`
+	Optional Optional[int]     `json:"optional"`
+	Raw      json.RawMessage   `json:"raw"`
+	Labels   map[string]string `json:"labels"`
+	Skipped  string            `json:"-"`
+}
+
+func TestDecodeJSONDocument_MatchesEncodingJSON(t *testing.T) {
+	bodies := []string{
+		`{"id":7,"created":"today","sku":"A1","quantity":2,"name":"Ann","nick":"an"}`,
+		`{"scores":{"1":1.5,"2":-3},"extra":{"a":[1,"b",true,null]},"amount":12.50}`,
+		`{"blob":"aGVsbG8=","port":"8080","when":"2024-05-01T10:00:00Z","addr":"10.0.0.1"}`,
+		`{"pair":["a","b","c"],"tags":[],"optional":null,"raw":{"keep": [1, 2]},"labels":null}`,
+		`{"NAME":"case","nick":null,"optional":5,"Skipped":"no","unknown":{"x":1}}`,
+		`{"pair":["a"],"tags":["x","y"],"extra":3}`,
+	}
+
+	for _, body := range bodies {
+		t.Run(body, func(t *testing.T) {
+			var expected, got TestDecodeTarget
+			if err := json.Unmarshal([]byte(body), &expected); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := decodeJSONDocument(strings.NewReader(body), &got, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("expected %+v, got %+v", expected, got)
+			}
+		})
+	}
+}
+
+func TestBindAndValidateJSON_StreamsBody(t *testing.T) {
+	body := `{"customer":"Acme","items":[{"sku":"A1","quantity":2}]}`
+	req := httptest.NewRequest(http.MethodPost, "/orders", iotest.OneByteReader(strings.NewReader(body)))
+	ctx := &Context{Request: req}
+
+	var order TestStrictOrder
+	if err := ctx.BindAndValidateJSON(&order, NewSchema(TestStrictOrder{})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.Customer != "Acme" || len(order.Items) != 1 || order.Items[0].Quantity != 2 {
+		t.Errorf("unexpected order %+v", order)
+	}
+}
@@ -0,0 +1,161 @@
+package nimbus
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// SpecIssue is a problem found in an OpenAPI spec (see OpenAPISpec.Lint)
+type SpecIssue struct {
+	Location string // Where the problem is, e.g. "paths./users/{id}.get"
+	Message  string
+}
+
+// String formats the issue as "location: message"
+func (i SpecIssue) String() string {
+	return i.Location + ": " + i.Message
+}
+
+var (
+	templateParamRegex = regexp.MustCompile(`\{([^}]+)\}`)       // Parameters of a path template ("/users/{id}")
+	componentNameRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`) // Names allowed for components
+)
+
+// Lint checks the spec for problems that make it invalid or mislead clients and code generators:
+//
+//   - component names not matching ^[a-zA-Z0-9._-]+$
+//   - $refs that don't resolve to a schema (refs are JSON pointers, with "~1" for "/" and "~0" for "~")
+//   - operationIds shared by several operations (generated IDs can collide, e.g. for
+//     "/users/:id" and "/users/by/id"; set RouteMetadata.OperationID to disambiguate)
+//   - path template parameters without a path parameter, and path parameters missing from the template
+//   - security requirements naming schemes missing from components
+//
+// Issues are returned in a fixed order, so the result can be compared in tests.
+//
+// Example:
+//
+//	func TestOpenAPISpec(t *testing.T) {
+//	    spec := newRouter().GenerateOpenAPI(apiConfig)
+//	    for _, issue := range spec.Lint() {
+//	        t.Error(issue)
+//	    }
+//	}
+func (spec *OpenAPISpec) Lint() []SpecIssue {
+	var issues []SpecIssue
+	report := func(location, format string, args ...any) {
+		issues = append(issues, SpecIssue{Location: location, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(spec.Components.Schemas)) {
+		if !componentNameRegex.MatchString(name) {
+			report("components.schemas", "component name %q doesn't match %s", name, componentNameRegex)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(spec.Components.SecuritySchemes)) {
+		if !componentNameRegex.MatchString(name) {
+			report("components.securitySchemes", "component name %q doesn't match %s", name, componentNameRegex)
+		}
+	}
+
+	spec.walkSchemas(func(location string, schema *OpenAPISchema) {
+		if schema.Ref != "" && spec.resolveSchemaRef(schema.Ref) == nil {
+			report(location, "$ref %s doesn't resolve to a component schema", schema.Ref)
+		}
+	})
+
+	operationIDs := make(map[string]string) // operationId -> location of its first use
+	lintPath := func(location, path string, item OpenAPIPath, checkParams bool) {
+		templateParams := make(map[string]bool)
+		for _, match := range templateParamRegex.FindAllStringSubmatch(path, -1) {
+			templateParams[match[1]] = true
+		}
+
+		for _, method := range openAPIMethods {
+			op := *item.operation(method)
+			if op == nil {
+				continue
+			}
+			opLocation := location + "." + strings.ToLower(method)
+
+			if op.OperationID != "" {
+				if first, exists := operationIDs[op.OperationID]; exists {
+					report(opLocation, "operationId %q is already used by %s", op.OperationID, first)
+				} else {
+					operationIDs[op.OperationID] = opLocation
+				}
+			}
+
+			for _, requirement := range op.Security {
+				for _, name := range slices.Sorted(maps.Keys(requirement)) {
+					if spec.Components.SecuritySchemes[name] == nil {
+						report(opLocation, "security scheme %s isn't defined in components", name)
+					}
+				}
+			}
+
+			if !checkParams {
+				continue
+			}
+			declared := make(map[string]bool)
+			for _, param := range append(slices.Clone(item.Parameters), op.Parameters...) {
+				if param.In != "path" {
+					continue
+				}
+				declared[param.Name] = true
+				if !templateParams[param.Name] {
+					report(opLocation, "path parameter %s isn't in the path template", param.Name)
+				}
+				if !param.Required {
+					report(opLocation, "path parameter %s must be required", param.Name)
+				}
+			}
+			for _, name := range slices.Sorted(maps.Keys(templateParams)) {
+				if !declared[name] {
+					report(opLocation, "path template parameter {%s} isn't declared", name)
+				}
+			}
+		}
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(spec.Paths)) {
+		lintPath("paths."+path, path, spec.Paths[path], true)
+	}
+	// Webhook names aren't path templates
+	for _, name := range slices.Sorted(maps.Keys(spec.Webhooks)) {
+		lintPath("webhooks."+name, name, spec.Webhooks[name], false)
+	}
+
+	return issues
+}
+
+// resolveSchemaRef returns the schema a local $ref points to (nil if it doesn't resolve).
+// The ref is a JSON pointer into the spec, e.g. "#/components/schemas/User/properties/name".
+func (spec *OpenAPISpec) resolveSchemaRef(ref string) *OpenAPISchema {
+	pointer, ok := strings.CutPrefix(ref, "#/components/schemas/")
+	if !ok {
+		return nil
+	}
+	tokens := strings.Split(pointer, "/")
+	for i, token := range tokens {
+		// "~1" must be decoded before "~0", so "~01" becomes "~1" rather than "/"
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	schema := spec.Components.Schemas[tokens[0]]
+	for rest := tokens[1:]; schema != nil && len(rest) > 0; {
+		switch {
+		case rest[0] == "properties" && len(rest) > 1:
+			schema, rest = schema.Properties[rest[1]], rest[2:]
+		case rest[0] == "items":
+			schema, rest = schema.Items, rest[1:]
+		case rest[0] == "additionalProperties":
+			schema, rest = schema.AdditionalProperties, rest[1:]
+		default:
+			return nil
+		}
+	}
+	return schema
+}
@@ -0,0 +1,152 @@
+package nimbus
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestOpenAPISpec_Lint(t *testing.T) {
+	ok := func() map[string]OpenAPIResponse {
+		return map[string]OpenAPIResponse{"200": {Description: "OK"}}
+	}
+	tests := []struct {
+		name     string
+		spec     *OpenAPISpec
+		expected []string
+	}{
+		{
+			name: "valid",
+			spec: &OpenAPISpec{
+				Paths: map[string]OpenAPIPath{
+					"/users/{id}": {
+						Parameters: []OpenAPIParameter{{Name: "id", In: "path", Required: true}},
+						GET:        &OpenAPIOperation{OperationID: "getUser", Responses: ok()},
+					},
+				},
+			},
+		},
+		{
+			name: "dangling ref",
+			spec: &OpenAPISpec{
+				Paths: map[string]OpenAPIPath{
+					"/users": {POST: &OpenAPIOperation{
+						RequestBody: &OpenAPIRequestBody{Content: jsonContent(&OpenAPISchema{Ref: "#/components/schemas/User"}, nil)},
+						Responses:   ok(),
+					}},
+				},
+			},
+			expected: []string{"paths./users.post.requestBody.content.application/json: $ref #/components/schemas/User doesn't resolve to a component schema"},
+		},
+		{
+			name: "escaped refs",
+			spec: &OpenAPISpec{
+				Components: OpenAPIComponents{Schemas: map[string]*OpenAPISchema{
+					"User": {Type: "object", Properties: map[string]*OpenAPISchema{
+						"a/b": {Type: "string"},
+						"a~b": {Type: "string"},
+					}},
+				}},
+				Paths: map[string]OpenAPIPath{
+					"/users": {GET: &OpenAPIOperation{
+						Parameters: []OpenAPIParameter{
+							{Name: "ab", In: "query", Schema: &OpenAPISchema{Ref: "#/components/schemas/User/properties/a~1b"}},
+							{Name: "a~b", In: "query", Schema: &OpenAPISchema{Ref: "#/components/schemas/User/properties/a~0b"}},
+							{Name: "name", In: "query", Schema: &OpenAPISchema{Ref: "#/components/schemas/User/properties/name"}},
+							{Name: "other", In: "query", Schema: &OpenAPISchema{Ref: "#/definitions/User"}},
+						},
+						Responses: ok(),
+					}},
+				},
+			},
+			expected: []string{
+				"paths./users.get.parameters.name: $ref #/components/schemas/User/properties/name doesn't resolve to a component schema",
+				"paths./users.get.parameters.other: $ref #/definitions/User doesn't resolve to a component schema",
+			},
+		},
+		{
+			name: "invalid component names",
+			spec: &OpenAPISpec{
+				Components: OpenAPIComponents{
+					Schemas: map[string]*OpenAPISchema{
+						"Page[main.Item]": {Type: "object"},
+						"Page_Item":       {Type: "object"},
+					},
+					SecuritySchemes: map[string]*SecurityScheme{"bearer auth": {Type: "http", Scheme: "bearer"}},
+				},
+			},
+			expected: []string{
+				`components.schemas: component name "Page[main.Item]" doesn't match ^[a-zA-Z0-9._-]+$`,
+				`components.securitySchemes: component name "bearer auth" doesn't match ^[a-zA-Z0-9._-]+$`,
+			},
+		},
+		{
+			name: "duplicate operationId",
+			spec: &OpenAPISpec{
+				Paths: map[string]OpenAPIPath{
+					"/users":  {GET: &OpenAPIOperation{OperationID: "listUsers", Responses: ok()}},
+					"/people": {GET: &OpenAPIOperation{OperationID: "listUsers", Responses: ok()}},
+				},
+			},
+			expected: []string{`paths./users.get: operationId "listUsers" is already used by paths./people.get`},
+		},
+		{
+			name: "path parameters",
+			spec: &OpenAPISpec{
+				Paths: map[string]OpenAPIPath{
+					"/users/{id}": {GET: &OpenAPIOperation{
+						Parameters: []OpenAPIParameter{{Name: "userId", In: "path"}},
+						Responses:  ok(),
+					}},
+				},
+			},
+			expected: []string{
+				"paths./users/{id}.get: path parameter userId isn't in the path template",
+				"paths./users/{id}.get: path parameter userId must be required",
+				"paths./users/{id}.get: path template parameter {id} isn't declared",
+			},
+		},
+		{
+			name: "undefined security scheme",
+			spec: &OpenAPISpec{
+				Paths: map[string]OpenAPIPath{
+					"/users": {GET: &OpenAPIOperation{Security: []map[string][]string{{"bearerAuth": {}}}, Responses: ok()}},
+				},
+			},
+			expected: []string{"paths./users.get: security scheme bearerAuth isn't defined in components"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := tt.spec.Lint()
+			if len(issues) != len(tt.expected) {
+				t.Fatalf("expected %d issues, got %v", len(tt.expected), issues)
+			}
+			for i, issue := range issues {
+				if issue.String() != tt.expected[i] {
+					t.Errorf("expected %q, got %q", tt.expected[i], issue)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAPISpec_LintGenerated(t *testing.T) {
+	handler := func(ctx *Context) (any, int, error) { return nil, http.StatusOK, nil }
+
+	// Specs generated from well-formed routes are clean
+	for _, router := range []*Router{newTestHandleRouter(), newTestOpenAPI31Router()} {
+		if issues := router.GenerateOpenAPI(OpenAPIConfig{OpenAPIVersion: OpenAPIVersion31}).Lint(); len(issues) > 0 {
+			t.Errorf("expected no issues, got %v", issues)
+		}
+	}
+
+	// Generated operation IDs collide for these patterns
+	router := NewRouter()
+	router.AddRoute(http.MethodGet, "/users/:id", handler)
+	router.AddRoute(http.MethodGet, "/users/by/id", handler)
+	issues := router.GenerateOpenAPI(OpenAPIConfig{}).Lint()
+	if len(issues) != 1 || issues[0].Message != `operationId "getUsersById" is already used by paths./users/by/id.get` {
+		t.Errorf("expected duplicate operationId, got %v", issues)
+	}
+}
@@ -0,0 +1,166 @@
+package nimbus
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// messageCatalog holds message templates per locale, keyed by rule tag (or msg tag key)
+var messageCatalog = struct {
+	sync.RWMutex
+	m map[string]map[string]string
+}{
+	m: make(map[string]map[string]string),
+}
+
+// RegisterMessages adds message templates for a locale (e.g., "de", "pt-br").
+// Templates are keyed by rule tag ("required", "minlen", ...) or by the value of a field's
+// `msg` tag, and may use the {field}, {param} and {value} placeholders.
+// The "validation_failed" key translates the summary message of validation error responses.
+// Errors without a template keep their default English message.
+//
+// Example:
+//
+//	nimbus.RegisterMessages("de", map[string]string{
+//	    "required": "{field} ist erforderlich",
+//	    "minlen":   "{field} muss mindestens {param} Zeichen lang sein",
+//	})
+func RegisterMessages(locale string, messages map[string]string) {
+	locale = normalizeLocale(locale)
+
+	messageCatalog.Lock()
+	defer messageCatalog.Unlock()
+
+	catalog := messageCatalog.m[locale]
+	if catalog == nil {
+		catalog = make(map[string]string, len(messages))
+		messageCatalog.m[locale] = catalog
+	}
+	for key, template := range messages {
+		catalog[key] = template
+	}
+}
+
+// lookupMessage returns the template for key in a locale
+func lookupMessage(locale, key string) (string, bool) {
+	messageCatalog.RLock()
+	defer messageCatalog.RUnlock()
+	template, ok := messageCatalog.m[locale][key]
+	return template, ok
+}
+
+// Localize returns a copy of the errors with messages translated into locale
+// ("de-AT" falls back to "de" if only "de" is registered).
+// A field's `msg` tag takes precedence over the rule tag's template; Tag is never changed.
+func (ve ValidationErrors) Localize(locale string) ValidationErrors {
+	locale = resolveLocale(normalizeLocale(locale))
+	localized := make(ValidationErrors, len(ve))
+	for i, err := range ve {
+		if err.messageKey != "" {
+			if template, ok := lookupMessage(locale, err.messageKey); ok {
+				err.Message = renderMessage(template, err)
+			}
+		} else if template, ok := lookupMessage(locale, err.Tag); ok {
+			err.Message = renderMessage(template, err)
+		}
+		localized[i] = err
+	}
+	return localized
+}
+
+// renderMessage fills in the {field}, {param} and {value} placeholders of a template
+func renderMessage(template string, err ValidationError) string {
+	value := ""
+	if err.Value != nil {
+		value = fmt.Sprint(err.Value)
+	}
+	return strings.NewReplacer(
+		"{field}", err.Field,
+		"{param}", err.Param,
+		"{value}", value,
+	).Replace(template)
+}
+
+// SetLocale overrides the locale negotiated from the Accept-Language header,
+// e.g., with a language stored in the user's profile.
+func (c *Context) SetLocale(locale string) {
+	c.locale = normalizeLocale(locale)
+}
+
+// Locale returns the locale used for validation messages: the one set with SetLocale,
+// or the best match between the Accept-Language header and the registered message locales.
+// Returns "" when nothing matches (default messages are used).
+func (c *Context) Locale() string {
+	if c.locale != "" {
+		return c.locale
+	}
+	if c.Request == nil {
+		return ""
+	}
+	return matchLocale(c.Request.Header.Get("Accept-Language"))
+}
+
+// matchLocale picks the registered locale that best matches an Accept-Language header.
+// Languages are tried in order of preference (q-value); "pt-BR" falls back to "pt".
+func matchLocale(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return ""
+	}
+
+	type preference struct {
+		locale string
+		q      float64
+	}
+	var prefs []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if tag != "" && tag != "*" && q > 0 {
+			prefs = append(prefs, preference{normalizeLocale(tag), q})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+
+	messageCatalog.RLock()
+	defer messageCatalog.RUnlock()
+
+	for _, pref := range prefs {
+		if locale := resolveLocaleLocked(pref.locale); locale != "" {
+			return locale
+		}
+	}
+	return ""
+}
+
+// resolveLocale returns the registered locale for a normalized locale, falling back to its
+// base language, or "" if neither is registered
+func resolveLocale(locale string) string {
+	messageCatalog.RLock()
+	defer messageCatalog.RUnlock()
+	return resolveLocaleLocked(locale)
+}
+
+func resolveLocaleLocked(locale string) string {
+	if _, ok := messageCatalog.m[locale]; ok {
+		return locale
+	}
+	if base, _, found := strings.Cut(locale, "-"); found {
+		if _, ok := messageCatalog.m[base]; ok {
+			return base
+		}
+	}
+	return ""
+}
+
+// normalizeLocale lowercases a locale and uses "-" as the separator ("pt_BR" -> "pt-br")
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
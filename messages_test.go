@@ -0,0 +1,169 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// registerTestMessages registers catalogs for a test and removes them afterwards
+func registerTestMessages(t *testing.T, catalogs map[string]map[string]string) {
+	t.Helper()
+	for locale, messages := range catalogs {
+		RegisterMessages(locale, messages)
+	}
+	t.Cleanup(func() {
+		messageCatalog.Lock()
+		defer messageCatalog.Unlock()
+		for locale := range catalogs {
+			delete(messageCatalog.m, normalizeLocale(locale))
+		}
+	})
+}
+
+func TestMatchLocale(t *testing.T) {
+	registerTestMessages(t, map[string]map[string]string{
+		"de":    {"required": "{field} ist erforderlich"},
+		"pt":    {"required": "{field} é obrigatório"},
+		"fr-CA": {"required": "{field} est requis"},
+	})
+
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", ""},
+		{"de", "de"},
+		{"de-AT,en;q=0.8", "de"},
+		{"pt_BR", "pt"},
+		{"fr-ca", "fr-ca"},
+		{"fr", ""},
+		{"en-US,en;q=0.9,pt;q=0.8", "pt"},
+		{"de;q=0.5,pt;q=0.9", "pt"},
+		{"de;q=0,ja", ""},
+		{"*", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := matchLocale(tt.header); got != tt.expected {
+				t.Errorf("matchLocale(%q) = %q, expected %q", tt.header, got, tt.expected)
+			}
+		})
+	}
+}
+
+type TestLocalizedSignup struct {
+	Name     string `json:"name" validate:"required,minlen=3"`
+	Password string `json:"password" validate:"minlen=8" msg:"password.weak"`
+	Website  string `json:"website" validate:"url" msg:"Enter a full URL like https://example.com"`
+	Age      int    `json:"age" validate:"min=18"`
+}
+
+func TestValidationErrors_Localize(t *testing.T) {
+	registerTestMessages(t, map[string]map[string]string{
+		"de": {
+			"minlen":        "{field} muss mindestens {param} Zeichen lang sein",
+			"min":           "{field} muss mindestens {param} sein, nicht {value}",
+			"password.weak": "Das Passwort ist zu schwach",
+		},
+	})
+
+	schema := NewSchema(TestLocalizedSignup{})
+	errs := schema.Validate(TestLocalizedSignup{Name: "Al", Password: "short", Website: "example", Age: 16})
+
+	english := make(map[string]ValidationError)
+	for _, err := range errs {
+		english[err.Field] = err
+	}
+	if e := english["name"]; e.Param != "3" || e.Message != "name must be at least 3 characters" {
+		t.Errorf("unexpected default error: %+v", e)
+	}
+	if e := english["password"]; e.Tag != "minlen" || e.Message != "password.weak" {
+		t.Errorf("expected msg key as default message with stable tag, got %+v", e)
+	}
+	if e := english["website"]; e.Tag != "url" || e.Message != "Enter a full URL like https://example.com" {
+		t.Errorf("expected msg override, got %+v", e)
+	}
+
+	expected := map[string]string{
+		"name":     "name muss mindestens 3 Zeichen lang sein",
+		"password": "Das Passwort ist zu schwach",
+		"website":  "Enter a full URL like https://example.com",
+		"age":      "age muss mindestens 18 sein, nicht 16",
+	}
+	for _, locale := range []string{"de", "DE", "de_de"} {
+		for _, err := range errs.Localize(locale) {
+			if expected[err.Field] != err.Message {
+				t.Errorf("%s: unexpected message for %s: %q", locale, err.Field, err.Message)
+			}
+		}
+	}
+	if english["name"].Message != "name must be at least 3 characters" {
+		t.Error("expected Localize not to modify the original errors")
+	}
+
+	// Unknown locales keep the default messages
+	for _, err := range errs.Localize("ja") {
+		if err.Message != english[err.Field].Message {
+			t.Errorf("expected default message for %s, got %q", err.Field, err.Message)
+		}
+	}
+}
+
+func TestSendValidationError_Locale(t *testing.T) {
+	registerTestMessages(t, map[string]map[string]string{
+		"es": {
+			"validation_failed": "La validación de la solicitud falló",
+			"required":          "{field} es obligatorio",
+		},
+		"fr": {
+			"required": "{field} est requis",
+		},
+	})
+
+	validator := NewValidator(&TestLocalizedSignup{})
+	router := NewRouter()
+	router.AddRoute(http.MethodPost, "/signup", WithTyped(
+		func(ctx *Context, req *TypedRequest[struct{}, TestLocalizedSignup, struct{}]) (any, int, error) {
+			return req.Body, 201, nil
+		}, nil, validator, nil))
+	router.AddRoute(http.MethodPost, "/profile", func(ctx *Context) (any, int, error) {
+		ctx.SetLocale("fr")
+		var body TestLocalizedSignup
+		if err := ctx.BindAndValidateBody(&body, validator.Schema); err != nil {
+			return ctx.sendBindError(err, "invalid_request")
+		}
+		return body, 200, nil
+	})
+
+	send := func(path, acceptLanguage string) map[string]any {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"age":20}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", acceptLanguage)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp map[string]any
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+
+	resp := send("/signup", "es-MX,es;q=0.9")
+	details := resp["details"].([]any)
+	detail := details[0].(map[string]any)
+	if resp["message"] != "La validación de la solicitud falló" || detail["message"] != "name es obligatorio" || detail["tag"] != "required" {
+		t.Errorf("unexpected localized response: %v", resp)
+	}
+
+	// SetLocale takes precedence over Accept-Language
+	resp = send("/profile", "es")
+	detail = resp["details"].([]any)[0].(map[string]any)
+	if resp["message"] != "Request validation failed" || detail["message"] != "name est requis" {
+		t.Errorf("unexpected localized response: %v", resp)
+	}
+}
@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/DylanHalstead/nimbus"
@@ -9,8 +11,9 @@ import (
 
 // Auth middleware validates authentication token
 // This is a simple example - in production, use proper JWT validation
+// Routes using it are documented with the "bearerAuth" HTTP bearer security scheme.
 func Auth(validateToken func(string) (any, error)) nimbus.Middleware {
-	return func(next nimbus.Handler) nimbus.Handler {
+	mw := func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (any, int, error) {
 			authHeader := ctx.GetHeader("Authorization")
 
@@ -39,4 +42,169 @@ func Auth(validateToken func(string) (any, error)) nimbus.Middleware {
 			return next(ctx)
 		}
 	}
+	return nimbus.DescribeMiddleware(mw, nimbus.MiddlewareDoc{
+		SecurityName:   "bearerAuth",
+		SecurityScheme: &nimbus.SecurityScheme{Type: "http", Scheme: "bearer"},
+		Responses:      map[int]nimbus.MiddlewareResponse{http.StatusUnauthorized: {Description: "Unauthorized"}},
+	})
+}
+
+// APIKeyConfig defines the configuration for API key authentication
+type APIKeyConfig struct {
+	Name       string                        // Header or query parameter carrying the key (default: X-API-Key)
+	In         string                        // "header" (default) or "query"
+	Validate   func(key string) (any, error) // Returns the authenticated user (required)
+	SchemeName string                        // Name of the security scheme (default: apiKeyAuth)
+}
+
+// APIKeyAuth middleware authenticates requests with an API key sent in a header or query parameter.
+// Panics if config.Validate is nil or config.In is invalid.
+//
+// Example:
+//
+//	router.Use(middleware.APIKeyAuth(middleware.APIKeyConfig{
+//	    Name:     "X-API-Key",
+//	    Validate: lookupKey,
+//	}))
+func APIKeyAuth(config APIKeyConfig) nimbus.Middleware {
+	if config.Validate == nil {
+		panic("middleware: APIKeyAuth requires a Validate function")
+	}
+	if config.Name == "" {
+		config.Name = "X-API-Key"
+	}
+	if config.In == "" {
+		config.In = "header"
+	}
+	if config.In != "header" && config.In != "query" {
+		panic(fmt.Sprintf("middleware: APIKeyAuth key must be in header or query, got %q", config.In))
+	}
+	if config.SchemeName == "" {
+		config.SchemeName = "apiKeyAuth"
+	}
+
+	mw := func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			var key string
+			if config.In == "query" {
+				key = ctx.Query(config.Name)
+			} else {
+				key = ctx.GetHeader(config.Name)
+			}
+			if key == "" {
+				return nil, http.StatusUnauthorized, nimbus.NewAPIError("unauthorized", "Missing API key")
+			}
+
+			user, err := config.Validate(key)
+			if err != nil {
+				return nil, http.StatusUnauthorized, nimbus.NewAPIError("unauthorized", err.Error())
+			}
+
+			ctx.Set("user", user)
+			return next(ctx)
+		}
+	}
+	return nimbus.DescribeMiddleware(mw, nimbus.MiddlewareDoc{
+		SecurityName:   config.SchemeName,
+		SecurityScheme: &nimbus.SecurityScheme{Type: "apiKey", Name: config.Name, In: config.In},
+		Responses:      map[int]nimbus.MiddlewareResponse{http.StatusUnauthorized: {Description: "Unauthorized"}},
+	})
+}
+
+// BasicAuth middleware authenticates requests with HTTP basic credentials.
+// Failed requests are answered with a WWW-Authenticate challenge so browsers prompt for credentials.
+func BasicAuth(validate func(username, password string) (any, error)) nimbus.Middleware {
+	mw := func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			username, password, ok := ctx.Request.BasicAuth()
+			if !ok {
+				ctx.Header("WWW-Authenticate", `Basic realm="restricted"`)
+				return nil, http.StatusUnauthorized, nimbus.NewAPIError("unauthorized", "Missing basic credentials")
+			}
+
+			user, err := validate(username, password)
+			if err != nil {
+				ctx.Header("WWW-Authenticate", `Basic realm="restricted"`)
+				return nil, http.StatusUnauthorized, nimbus.NewAPIError("unauthorized", err.Error())
+			}
+
+			ctx.Set("user", user)
+			return next(ctx)
+		}
+	}
+	return nimbus.DescribeMiddleware(mw, nimbus.MiddlewareDoc{
+		SecurityName:   "basicAuth",
+		SecurityScheme: &nimbus.SecurityScheme{Type: "http", Scheme: "basic"},
+		Responses: map[int]nimbus.MiddlewareResponse{
+			http.StatusUnauthorized: {
+				Description: "Unauthorized",
+				Headers: map[string]nimbus.OpenAPIHeader{
+					"WWW-Authenticate": {Description: "Basic authentication challenge", Schema: &nimbus.OpenAPISchema{Type: "string"}},
+				},
+			},
+		},
+	})
+}
+
+// OAuth2Config defines the configuration for OAuth2 bearer token authentication
+type OAuth2Config struct {
+	Flows         nimbus.OAuthFlows                         // Flows documented in the security scheme
+	ValidateToken func(token string) (any, []string, error) // Returns the user and the token's granted scopes (required)
+	SchemeName    string                                    // Name of the security scheme (default: oauth2)
+}
+
+// OAuth2 middleware authenticates requests with an OAuth2 bearer token and requires it to have
+// been granted every scope listed. Missing scopes are rejected with 403 insufficient_scope.
+// Panics if config.ValidateToken is nil.
+//
+// Example:
+//
+//	oauth := middleware.OAuth2Config{
+//	    Flows: nimbus.OAuthFlows{AuthorizationCode: &nimbus.OAuthFlow{
+//	        AuthorizationURL: "https://auth.example.com/authorize",
+//	        TokenURL:         "https://auth.example.com/token",
+//	        Scopes:           map[string]string{"orders:write": "Create orders"},
+//	    }},
+//	    ValidateToken: introspect,
+//	}
+//	router.AddRoute(http.MethodPost, "/orders", createOrder, middleware.OAuth2(oauth, "orders:write"))
+func OAuth2(config OAuth2Config, scopes ...string) nimbus.Middleware {
+	if config.ValidateToken == nil {
+		panic("middleware: OAuth2 requires a ValidateToken function")
+	}
+	if config.SchemeName == "" {
+		config.SchemeName = "oauth2"
+	}
+
+	mw := func(next nimbus.Handler) nimbus.Handler {
+		return func(ctx *nimbus.Context) (any, int, error) {
+			token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				return nil, http.StatusUnauthorized, nimbus.NewAPIError("unauthorized", "Missing bearer token")
+			}
+
+			user, granted, err := config.ValidateToken(token)
+			if err != nil {
+				return nil, http.StatusUnauthorized, nimbus.NewAPIError("unauthorized", err.Error())
+			}
+			for _, scope := range scopes {
+				if !slices.Contains(granted, scope) {
+					return nil, http.StatusForbidden, nimbus.NewAPIError("insufficient_scope", fmt.Sprintf("Token is missing scope %s", scope))
+				}
+			}
+
+			ctx.Set("user", user)
+			return next(ctx)
+		}
+	}
+	flows := config.Flows
+	return nimbus.DescribeMiddleware(mw, nimbus.MiddlewareDoc{
+		SecurityName:   config.SchemeName,
+		SecurityScheme: &nimbus.SecurityScheme{Type: "oauth2", Flows: &flows},
+		Scopes:         scopes,
+		Responses: map[int]nimbus.MiddlewareResponse{
+			http.StatusUnauthorized: {Description: "Unauthorized"},
+			http.StatusForbidden:    {Description: "Token is missing a required scope"},
+		},
+	})
 }
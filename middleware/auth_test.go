@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/DylanHalstead/nimbus"
@@ -228,3 +229,181 @@ func TestAuth_DifferentTokens(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIKeyAuth(t *testing.T) {
+	validate := func(key string) (any, error) {
+		if key != "secret" {
+			return nil, errors.New("invalid API key")
+		}
+		return "alice", nil
+	}
+
+	testCases := []struct {
+		name     string
+		config   APIKeyConfig
+		target   string
+		header   string
+		expected int
+	}{
+		{"header key", APIKeyConfig{Validate: validate}, "/test", "secret", http.StatusOK},
+		{"missing header key", APIKeyConfig{Validate: validate}, "/test", "", http.StatusUnauthorized},
+		{"invalid key", APIKeyConfig{Validate: validate}, "/test", "wrong", http.StatusUnauthorized},
+		{"query key", APIKeyConfig{Name: "api_key", In: "query", Validate: validate}, "/test?api_key=secret", "", http.StatusOK},
+		{"query key ignores header", APIKeyConfig{Name: "api_key", In: "query", Validate: validate}, "/test", "secret", http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := APIKeyAuth(tc.config)(func(ctx *nimbus.Context) (any, int, error) {
+				if user, _ := ctx.Get("user"); user != "alice" {
+					t.Errorf("expected user alice, got %v", user)
+				}
+				return nil, http.StatusOK, nil
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.header != "" {
+				req.Header.Set("X-API-Key", tc.header)
+			}
+			_, statusCode, _ := handler(nimbus.NewContext(httptest.NewRecorder(), req))
+			if statusCode != tc.expected {
+				t.Errorf("expected status %d, got %d", tc.expected, statusCode)
+			}
+		})
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	validate := func(username, password string) (any, error) {
+		if username != "alice" || password != "s3cr3t" {
+			return nil, errors.New("invalid credentials")
+		}
+		return username, nil
+	}
+
+	testCases := []struct {
+		name     string
+		username string
+		password string
+		expected int
+	}{
+		{"valid credentials", "alice", "s3cr3t", http.StatusOK},
+		{"invalid credentials", "alice", "wrong", http.StatusUnauthorized},
+		{"missing credentials", "", "", http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := BasicAuth(validate)(func(ctx *nimbus.Context) (any, int, error) {
+				return nil, http.StatusOK, nil
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tc.username != "" {
+				req.SetBasicAuth(tc.username, tc.password)
+			}
+			w := httptest.NewRecorder()
+			_, statusCode, _ := handler(nimbus.NewContext(w, req))
+			if statusCode != tc.expected {
+				t.Errorf("expected status %d, got %d", tc.expected, statusCode)
+			}
+			if challenged := w.Header().Get("WWW-Authenticate") != ""; challenged != (tc.expected == http.StatusUnauthorized) {
+				t.Errorf("unexpected WWW-Authenticate header %q", w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestOAuth2(t *testing.T) {
+	config := OAuth2Config{
+		ValidateToken: func(token string) (any, []string, error) {
+			if token != "valid" {
+				return nil, nil, errors.New("invalid token")
+			}
+			return "alice", []string{"orders:read"}, nil
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		scopes   []string
+		header   string
+		expected int
+		code     string
+	}{
+		{"granted scope", []string{"orders:read"}, "Bearer valid", http.StatusOK, ""},
+		{"missing scope", []string{"orders:read", "orders:write"}, "Bearer valid", http.StatusForbidden, "insufficient_scope"},
+		{"invalid token", nil, "Bearer expired", http.StatusUnauthorized, "unauthorized"},
+		{"missing token", nil, "", http.StatusUnauthorized, "unauthorized"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := OAuth2(config, tc.scopes...)(func(ctx *nimbus.Context) (any, int, error) {
+				return nil, http.StatusOK, nil
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			_, statusCode, err := handler(nimbus.NewContext(httptest.NewRecorder(), req))
+			if statusCode != tc.expected {
+				t.Errorf("expected status %d, got %d", tc.expected, statusCode)
+			}
+			if apiErr, ok := err.(*nimbus.APIError); tc.code != "" && (!ok || apiErr.Code != tc.code) {
+				t.Errorf("expected error code %s, got %v", tc.code, err)
+			}
+		})
+	}
+}
+
+func TestAuth_OpenAPISecurity(t *testing.T) {
+	validateToken := func(token string) (any, error) { return token, nil }
+	oauth := OAuth2Config{
+		Flows: nimbus.OAuthFlows{ClientCredentials: &nimbus.OAuthFlow{
+			TokenURL: "https://auth.example.com/token",
+			Scopes:   map[string]string{"orders:write": "Create orders"},
+		}},
+		ValidateToken: func(token string) (any, []string, error) { return token, nil, nil },
+	}
+
+	router := nimbus.NewRouter()
+	handler := func(ctx *nimbus.Context) (any, int, error) { return nil, http.StatusOK, nil }
+	router.AddRoute(http.MethodGet, "/bearer", handler, Auth(validateToken))
+	router.AddRoute(http.MethodGet, "/key", handler, APIKeyAuth(APIKeyConfig{Name: "api_key", In: "query", Validate: validateToken}))
+	router.AddRoute(http.MethodGet, "/basic", handler, BasicAuth(func(u, p string) (any, error) { return u, nil }))
+	router.AddRoute(http.MethodPost, "/orders", handler, OAuth2(oauth, "orders:write"))
+	router.AddRoute(http.MethodGet, "/public", handler, RequestID())
+
+	spec := router.GenerateOpenAPI(nimbus.OpenAPIConfig{Title: "Auth", Version: "1.0.0"})
+
+	testCases := []struct {
+		path   string
+		op     *nimbus.OpenAPIOperation
+		scheme string
+		scopes []string
+	}{
+		{"/bearer", spec.Paths["/bearer"].GET, "bearerAuth", []string{}},
+		{"/key", spec.Paths["/key"].GET, "apiKeyAuth", []string{}},
+		{"/basic", spec.Paths["/basic"].GET, "basicAuth", []string{}},
+		{"/orders", spec.Paths["/orders"].POST, "oauth2", []string{"orders:write"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			if len(tc.op.Security) != 1 || !reflect.DeepEqual(tc.op.Security[0][tc.scheme], tc.scopes) {
+				t.Errorf("expected %s security with scopes %v, got %v", tc.scheme, tc.scopes, tc.op.Security)
+			}
+			if spec.Components.SecuritySchemes[tc.scheme] == nil {
+				t.Errorf("expected %s in components.securitySchemes", tc.scheme)
+			}
+		})
+	}
+
+	if key := spec.Components.SecuritySchemes["apiKeyAuth"]; key.In != "query" || key.Name != "api_key" {
+		t.Errorf("unexpected API key scheme %+v", key)
+	}
+	if spec.Paths["/public"].GET.Security != nil {
+		t.Errorf("expected no security on /public, got %v", spec.Paths["/public"].GET.Security)
+	}
+}
@@ -3,10 +3,10 @@ package middleware
 import (
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/DylanHalstead/nimbus"
+	"github.com/DylanHalstead/nimbus/internal/bytesize"
 )
 
 // Common size constants for convenience
@@ -79,10 +79,10 @@ func BodyLimitWithConfig(config BodyLimitConfig) nimbus.Middleware {
 	// Set default error message
 	if config.ErrorMessage == "" {
 		config.ErrorMessage = fmt.Sprintf("Request body too large. Maximum size is %s", 
-			formatBytes(config.MaxBytes))
+			bytesize.Format(config.MaxBytes))
 	}
 
-	return func(next nimbus.Handler) nimbus.Handler {
+	mw := func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (any, int, error) {
 			path := ctx.Request.URL.Path
 
@@ -121,6 +121,11 @@ func BodyLimitWithConfig(config BodyLimitConfig) nimbus.Middleware {
 			return data, status, err
 		}
 	}
+	return nimbus.DescribeMiddleware(mw, nimbus.MiddlewareDoc{
+		Responses: map[int]nimbus.MiddlewareResponse{
+			http.StatusRequestEntityTooLarge: {Description: config.ErrorMessage},
+		},
+	})
 }
 
 // BodyLimitFromString parses a human-readable size string and returns middleware
@@ -143,61 +148,7 @@ func BodyLimitFromString(size string) nimbus.Middleware {
 // Supports: "1B", "1KB", "1MB", "1GB" (case-insensitive)
 // Also supports decimals: "1.5MB", "0.5GB"
 func ParseSize(size string) (int64, error) {
-	size = strings.TrimSpace(strings.ToUpper(size))
-	
-	// Extract number and unit
-	var numStr string
-	var unit string
-	
-	for i, c := range size {
-		if (c >= '0' && c <= '9') || c == '.' {
-			numStr += string(c)
-		} else {
-			unit = size[i:]
-			break
-		}
-	}
-	
-	if numStr == "" {
-		return 0, fmt.Errorf("invalid size format: %s", size)
-	}
-	
-	// Parse the numeric value
-	value, err := strconv.ParseFloat(numStr, 64)
-	if err != nil {
-		return 0, fmt.Errorf("invalid number: %s", numStr)
-	}
-	
-	// Convert based on unit
-	var multiplier int64
-	switch unit {
-	case "B", "":
-		multiplier = B
-	case "KB", "K":
-		multiplier = KB
-	case "MB", "M":
-		multiplier = MB
-	case "GB", "G":
-		multiplier = GB
-	default:
-		return 0, fmt.Errorf("unknown unit: %s (use B, KB, MB, or GB)", unit)
-	}
-	
-	return int64(value * float64(multiplier)), nil
-}
-
-// formatBytes converts bytes to human-readable format
-func formatBytes(bytes int64) string {
-	switch {
-	case bytes >= GB:
-		return fmt.Sprintf("%.2fGB", float64(bytes)/float64(GB))
-	case bytes >= MB:
-		return fmt.Sprintf("%.2fMB", float64(bytes)/float64(MB))
-	case bytes >= KB:
-		return fmt.Sprintf("%.2fKB", float64(bytes)/float64(KB))
-	default:
-		return fmt.Sprintf("%dB", bytes)
-	}
+	return bytesize.Parse(size)
 }
 
 // isMaxBytesError checks if an error is caused by exceeding body size limit
@@ -343,30 +343,6 @@ func TestBodyLimitPresets(t *testing.T) {
 	}
 }
 
-func TestFormatBytes(t *testing.T) {
-	tests := []struct {
-		bytes    int64
-		expected string
-	}{
-		{100, "100B"},
-		{1024, "1.00KB"},
-		{1536, "1.50KB"},
-		{1048576, "1.00MB"},
-		{2097152, "2.00MB"},
-		{1073741824, "1.00GB"},
-		{2147483648, "2.00GB"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.expected, func(t *testing.T) {
-			result := formatBytes(tt.bytes)
-			if result != tt.expected {
-				t.Errorf("formatBytes(%d) = %q, expected %q", tt.bytes, result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestBodyLimitPanicOnInvalidConfig(t *testing.T) {
 	t.Run("panic on zero MaxBytes", func(t *testing.T) {
 		defer func() {
@@ -105,13 +105,20 @@ func Logger(config LoggerConfig) nimbus.Middleware {
 			// Call next handler
 			data, statusCode, err := next(ctx)
 
+			// Handlers using ctx.JSON/HTML/String return status 0 after writing;
+			// read the committed status from the response writer instead
+			loggedStatus := statusCode
+			if loggedStatus == 0 && ctx.Writer.Written() {
+				loggedStatus = ctx.Writer.Status()
+			}
+
 			// Build log event
 			duration := time.Since(start)
 			event := config.Logger.Info().
 				Str("method", method).
 				Str("path", path).
 				Dur("duration", duration).
-				Int("status", statusCode)
+				Int("status", loggedStatus)
 
 			// Add request ID if available (automatically added by RequestID middleware)
 			if requestID := ctx.GetString("request_id"); requestID != "" {
@@ -209,18 +209,18 @@ func RateLimitWithRouter(router interface{ RegisterCleanup(func()) }, requestsPe
 	limiter := NewRateLimiter(requestsPerSecond, burst)
 	router.RegisterCleanup(limiter.Close)
 
-	return func(next nimbus.Handler) nimbus.Handler {
+	return describeRateLimit(func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (any, int, error) {
 			// Use IP address as key
 			key := ctx.Request.RemoteAddr
 
 			if !limiter.allow(key) {
-				return nil, http.StatusTooManyRequests, nimbus.NewAPIError("rate_limit_exceeded", "Too many requests, please try again later")
+				return rateLimitExceeded(ctx)
 			}
 
 			return next(ctx)
 		}
-	}
+	})
 }
 
 // RateLimit returns a rate limiting middleware
@@ -232,18 +232,18 @@ func RateLimit(requestsPerSecond, burst int) nimbus.Middleware {
 	limiter := NewRateLimiter(requestsPerSecond, burst)
 	registerLimiter(limiter)
 
-	return func(next nimbus.Handler) nimbus.Handler {
+	return describeRateLimit(func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (any, int, error) {
 			// Use IP address as key
 			key := ctx.Request.RemoteAddr
 
 			if !limiter.allow(key) {
-				return nil, http.StatusTooManyRequests, nimbus.NewAPIError("rate_limit_exceeded", "Too many requests, please try again later")
+				return rateLimitExceeded(ctx)
 			}
 
 			return next(ctx)
 		}
-	}
+	})
 }
 
 // RateLimitByHeaderWithRouter returns a rate limiting middleware based on a header value
@@ -255,7 +255,7 @@ func RateLimitByHeaderWithRouter(router interface{ RegisterCleanup(func()) }, he
 	limiter := NewRateLimiter(requestsPerSecond, burst)
 	router.RegisterCleanup(limiter.Close)
 
-	return func(next nimbus.Handler) nimbus.Handler {
+	return describeRateLimit(func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (any, int, error) {
 			key := ctx.GetHeader(header)
 			if key == "" {
@@ -263,12 +263,12 @@ func RateLimitByHeaderWithRouter(router interface{ RegisterCleanup(func()) }, he
 			}
 
 			if !limiter.allow(key) {
-				return nil, http.StatusTooManyRequests, nimbus.NewAPIError("rate_limit_exceeded", "Too many requests, please try again later")
+				return rateLimitExceeded(ctx)
 			}
 
 			return next(ctx)
 		}
-	}
+	})
 }
 
 // RateLimitByHeader returns a rate limiting middleware based on a header value
@@ -280,7 +280,7 @@ func RateLimitByHeader(header string, requestsPerSecond, burst int) nimbus.Middl
 	limiter := NewRateLimiter(requestsPerSecond, burst)
 	registerLimiter(limiter)
 
-	return func(next nimbus.Handler) nimbus.Handler {
+	return describeRateLimit(func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (any, int, error) {
 			key := ctx.GetHeader(header)
 			if key == "" {
@@ -288,10 +288,34 @@ func RateLimitByHeader(header string, requestsPerSecond, burst int) nimbus.Middl
 			}
 
 			if !limiter.allow(key) {
-				return nil, http.StatusTooManyRequests, nimbus.NewAPIError("rate_limit_exceeded", "Too many requests, please try again later")
+				return rateLimitExceeded(ctx)
 			}
 
 			return next(ctx)
 		}
-	}
+	})
+}
+
+// rateLimitExceeded rejects a request over the limit. Buckets refill at least one token
+// per second, so clients are told to retry after a second.
+func rateLimitExceeded(ctx *nimbus.Context) (any, int, error) {
+	ctx.Header("Retry-After", "1")
+	return nil, http.StatusTooManyRequests, nimbus.NewAPIError("rate_limit_exceeded", "Too many requests, please try again later")
+}
+
+// describeRateLimit documents the 429 response of a rate limiting middleware
+func describeRateLimit(mw nimbus.Middleware) nimbus.Middleware {
+	return nimbus.DescribeMiddleware(mw, nimbus.MiddlewareDoc{
+		Responses: map[int]nimbus.MiddlewareResponse{
+			http.StatusTooManyRequests: {
+				Description: "Too many requests",
+				Headers: map[string]nimbus.OpenAPIHeader{
+					"Retry-After": {
+						Description: "Seconds to wait before retrying",
+						Schema:      &nimbus.OpenAPISchema{Type: "integer"},
+					},
+				},
+			},
+		},
+	})
 }
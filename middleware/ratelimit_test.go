@@ -242,6 +242,10 @@ func TestRateLimit_ExceedsLimit(t *testing.T) {
 	if apiErr.Code != "rate_limit_exceeded" {
 		t.Errorf("expected error code 'rate_limit_exceeded', got '%s'", apiErr.Code)
 	}
+
+	if retryAfter := w.Header().Get("Retry-After"); retryAfter != "1" {
+		t.Errorf("expected Retry-After 1, got %q", retryAfter)
+	}
 }
 
 func TestRateLimit_DifferentIPs(t *testing.T) {
@@ -62,7 +62,7 @@ func RequestID(configs ...RequestIDConfig) nimbus.Middleware {
 		config.ContextKey = RequestIDKey
 	}
 
-	return func(next nimbus.Handler) nimbus.Handler {
+	mw := func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (any, int, error) {
 			// Check if request ID exists in incoming request
 			requestID := ctx.GetHeader(config.HeaderName)
@@ -82,6 +82,14 @@ func RequestID(configs ...RequestIDConfig) nimbus.Middleware {
 			return next(ctx)
 		}
 	}
+	return nimbus.DescribeMiddleware(mw, nimbus.MiddlewareDoc{
+		Headers: map[string]nimbus.OpenAPIHeader{
+			config.HeaderName: {
+				Description: "Request ID, propagated from the request or generated",
+				Schema:      &nimbus.OpenAPISchema{Type: "string"},
+			},
+		},
+	})
 }
 
 // generateRequestID generates a UUID v4 (random UUID)
@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"net/http"
 	"regexp"
 	"testing"
+	"time"
+
+	"github.com/DylanHalstead/nimbus"
 )
 
 func TestGenerateRequestID_UUIDFormat(t *testing.T) {
@@ -89,3 +93,47 @@ func TestGenerateULID_Uniqueness(t *testing.T) {
 		t.Errorf("Expected %d unique ULIDs, got %d", iterations, len(seen))
 	}
 }
+
+func TestMiddleware_OpenAPIResponses(t *testing.T) {
+	router := nimbus.NewRouter()
+	router.Use(RequestID(), Timeout(time.Second))
+	handler := func(ctx *nimbus.Context) (any, int, error) { return nil, http.StatusOK, nil }
+	router.AddRoute(http.MethodPost, "/uploads", handler, BodyLimit(MB), RateLimitWithRouter(router, 10, 10), Auth(func(token string) (any, error) { return token, nil }))
+	router.AddRoute(http.MethodGet, "/health", handler)
+	defer router.Shutdown()
+
+	spec := router.GenerateOpenAPI(nimbus.OpenAPIConfig{Title: "Uploads", Version: "1.0.0"})
+
+	testCases := []struct {
+		name      string
+		responses map[string]nimbus.OpenAPIResponse
+		expected  []string
+	}{
+		{"route middleware", spec.Paths["/uploads"].POST.Responses, []string{"200", "400", "401", "413", "429", "500", "504"}},
+		{"router middleware", spec.Paths["/health"].GET.Responses, []string{"200", "400", "500", "504"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if len(tc.responses) != len(tc.expected) {
+				t.Errorf("expected responses %v, got %v", tc.expected, tc.responses)
+			}
+			for _, code := range tc.expected {
+				resp, ok := tc.responses[code]
+				if !ok {
+					t.Errorf("expected %s response", code)
+					continue
+				}
+				if _, ok := resp.Headers[RequestIDHeader]; !ok {
+					t.Errorf("expected %s response to document %s", code, RequestIDHeader)
+				}
+			}
+		})
+	}
+
+	if _, ok := spec.Paths["/uploads"].POST.Responses["429"].Headers["Retry-After"]; !ok {
+		t.Error("expected 429 response to document Retry-After")
+	}
+	if schema := spec.Paths["/uploads"].POST.Responses["413"].Content["application/json"].Schema; schema.Ref != "#/components/schemas/ErrorResponse" {
+		t.Errorf("expected 413 to reference ErrorResponse, got %+v", schema)
+	}
+}
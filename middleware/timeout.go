@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/DylanHalstead/nimbus"
@@ -16,7 +17,7 @@ import (
 //
 // This is useful for preventing slow handlers from tying up resources.
 func Timeout(timeout time.Duration) nimbus.Middleware {
-	return func(next nimbus.Handler) nimbus.Handler {
+	return describeTimeout(func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (any, int, error) {
 			// Create timeout context from request's context
 			timeoutCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
@@ -48,7 +49,7 @@ func Timeout(timeout time.Duration) nimbus.Middleware {
 				return nil, 504, nimbus.NewAPIError("timeout", "request timeout exceeded")
 			}
 		}
-	}
+	})
 }
 
 // TimeoutWithSkip is like Timeout but skips certain paths.
@@ -64,7 +65,7 @@ func TimeoutWithSkip(timeout time.Duration, skipPaths ...string) nimbus.Middlewa
 		skipMap[path] = true
 	}
 
-	return func(next nimbus.Handler) nimbus.Handler {
+	return describeTimeout(func(next nimbus.Handler) nimbus.Handler {
 		return func(ctx *nimbus.Context) (any, int, error) {
 			// Skip timeout for certain paths
 			if skipMap[ctx.Request.URL.Path] {
@@ -96,6 +97,14 @@ func TimeoutWithSkip(timeout time.Duration, skipPaths ...string) nimbus.Middlewa
 				return nil, 504, nimbus.NewAPIError("timeout", "request timeout exceeded")
 			}
 		}
-	}
+	})
 }
 
+// describeTimeout documents the 504 response of a timeout middleware
+func describeTimeout(mw nimbus.Middleware) nimbus.Middleware {
+	return nimbus.DescribeMiddleware(mw, nimbus.MiddlewareDoc{
+		Responses: map[int]nimbus.MiddlewareResponse{
+			http.StatusGatewayTimeout: {Description: "Request timeout exceeded"},
+		},
+	})
+}
@@ -0,0 +1,236 @@
+package nimbus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// maxMsgPackDepth limits nesting of msgpack arrays/maps to guard against stack exhaustion.
+const maxMsgPackDepth = 64
+
+var errMsgPackTruncated = errors.New("unexpected end of msgpack data")
+
+// decodeMsgPack decodes a MessagePack request body, returning the generic value as the request document.
+// The payload is decoded into generic values and then bound like a JSON body (see decodeJSONDocument),
+// so struct fields are matched by their `json` tags and type mismatches are reported as ValidationErrors.
+// Extension types are not supported.
+func decodeMsgPack(ctx *Context, target any, schema *Schema) (any, error) {
+	data, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &msgPackDecoder{data: data}
+	value, err := d.decode(0)
+	if err == nil && d.pos != len(d.data) {
+		err = errors.New("trailing data after msgpack value")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid msgpack: %w", err)
+	}
+
+	// Re-encode as JSON so json tags (and json.Unmarshaler implementations) apply
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid msgpack: %w", err)
+	}
+	if _, err := decodeJSONDocument(bytes.NewReader(jsonData), target, schema); err != nil {
+		var validationErrs ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			return nil, fmt.Errorf("msgpack unmarshal error: %w", err)
+		}
+		for i := range validationErrs {
+			validationErrs[i].Offset = 0 // Offsets in the re-encoded JSON don't point into the body
+		}
+		return value, validationErrs
+	}
+	return value, nil
+}
+
+// msgPackDecoder decodes the MessagePack format into nil, bool, int64, uint64, float64,
+// string, []byte, []any and map[string]any values.
+type msgPackDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *msgPackDecoder) read(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, errMsgPackTruncated
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// readUint reads a big-endian unsigned integer of size bytes (1, 2, 4 or 8)
+func (d *msgPackDecoder) readUint(size int) (uint64, error) {
+	b, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+// readLength reads a length prefix of size bytes
+func (d *msgPackDecoder) readLength(size int) (int, error) {
+	n, err := d.readUint(size)
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.data)) {
+		return 0, errMsgPackTruncated
+	}
+	return int(n), nil
+}
+
+func (d *msgPackDecoder) decode(depth int) (any, error) {
+	if depth > maxMsgPackDepth {
+		return nil, errors.New("msgpack data nested too deeply")
+	}
+
+	b, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+
+	switch {
+	case c <= 0x7f: // positive fixint
+		return int64(c), nil
+	case c >= 0xe0: // negative fixint
+		return int64(int8(c)), nil
+	case c >= 0x80 && c <= 0x8f: // fixmap
+		return d.decodeMap(int(c&0x0f), depth)
+	case c >= 0x90 && c <= 0x9f: // fixarray
+		return d.decodeArray(int(c&0x0f), depth)
+	case c >= 0xa0 && c <= 0xbf: // fixstr
+		return d.decodeString(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6: // bin 8/16/32
+		n, err := d.readLength(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		raw, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), raw...), nil
+	case 0xca: // float 32
+		bits, err := d.readUint(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(uint32(bits))), nil
+	case 0xcb: // float 64
+		bits, err := d.readUint(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(bits), nil
+	case 0xcc, 0xcd, 0xce, 0xcf: // uint 8/16/32/64
+		return d.readUint(1 << (c - 0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3: // int 8/16/32/64
+		size := 1 << (c - 0xd0)
+		n, err := d.readUint(size)
+		if err != nil {
+			return nil, err
+		}
+		shift := 64 - 8*size
+		return int64(n<<shift) >> shift, nil // sign-extend
+	case 0xd9, 0xda, 0xdb: // str 8/16/32
+		n, err := d.readLength(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(n)
+	case 0xdc, 0xdd: // array 16/32
+		n, err := d.readLength(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(n, depth)
+	case 0xde, 0xdf: // map 16/32
+		n, err := d.readLength(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(n, depth)
+	}
+
+	return nil, fmt.Errorf("unsupported msgpack type 0x%02x", c)
+}
+
+func (d *msgPackDecoder) decodeString(n int) (string, error) {
+	b, err := d.read(n)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (d *msgPackDecoder) decodeArray(n int, depth int) ([]any, error) {
+	// Every element takes at least one byte, so larger lengths are truncated input
+	if n > len(d.data)-d.pos {
+		return nil, errMsgPackTruncated
+	}
+	values := make([]any, n)
+	for i := range values {
+		value, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func (d *msgPackDecoder) decodeMap(n int, depth int) (map[string]any, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errMsgPackTruncated
+	}
+	values := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		key, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		// JSON objects only have string keys
+		switch k := key.(type) {
+		case string:
+			values[k] = value
+		case []byte:
+			values[string(k)] = value
+		default:
+			values[fmt.Sprint(k)] = value
+		}
+	}
+	return values, nil
+}
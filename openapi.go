@@ -3,20 +3,41 @@ package nimbus
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
+	"maps"
 	"net/http"
 	"os"
 	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
-// OpenAPISpec represents an OpenAPI 3.0 specification
+// OpenAPI versions GenerateOpenAPI can produce
+const (
+	OpenAPIVersion30 = "3.0.3"
+	OpenAPIVersion31 = "3.1.0" // JSON Schema 2020-12 schemas, webhooks and path-level parameters
+)
+
+// OpenAPISpec represents an OpenAPI 3.0 or 3.1 specification
 type OpenAPISpec struct {
-	OpenAPI    string                 `json:"openapi"`
-	Info       OpenAPIInfo            `json:"info"`
-	Servers    []OpenAPIServer        `json:"servers,omitempty"`
-	Paths      map[string]OpenAPIPath `json:"paths"`
-	Components OpenAPIComponents      `json:"components,omitempty"`
+	OpenAPI      string                 `json:"openapi"`
+	Info         OpenAPIInfo            `json:"info"`
+	Servers      []OpenAPIServer        `json:"servers,omitempty"`
+	Paths        map[string]OpenAPIPath `json:"paths"`
+	Webhooks     map[string]OpenAPIPath `json:"webhooks,omitempty"` // OpenAPI 3.1 only
+	Components   OpenAPIComponents      `json:"components,omitempty"`
+	ExternalDocs *ExternalDocs          `json:"externalDocs,omitempty"`
+}
+
+// ExternalDocs links to documentation outside the spec
+type ExternalDocs struct {
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
 }
 
 // OpenAPIInfo contains API metadata
@@ -49,11 +70,55 @@ type OpenAPIServer struct {
 
 // OpenAPIPath represents operations for a path
 type OpenAPIPath struct {
-	GET    *OpenAPIOperation `json:"get,omitempty"`
-	POST   *OpenAPIOperation `json:"post,omitempty"`
-	PUT    *OpenAPIOperation `json:"put,omitempty"`
-	DELETE *OpenAPIOperation `json:"delete,omitempty"`
-	PATCH  *OpenAPIOperation `json:"patch,omitempty"`
+	GET        *OpenAPIOperation  `json:"get,omitempty"`
+	POST       *OpenAPIOperation  `json:"post,omitempty"`
+	PUT        *OpenAPIOperation  `json:"put,omitempty"`
+	DELETE     *OpenAPIOperation  `json:"delete,omitempty"`
+	PATCH      *OpenAPIOperation  `json:"patch,omitempty"`
+	HEAD       *OpenAPIOperation  `json:"head,omitempty"`
+	OPTIONS    *OpenAPIOperation  `json:"options,omitempty"`
+	TRACE      *OpenAPIOperation  `json:"trace,omitempty"`
+	Parameters []OpenAPIParameter `json:"parameters,omitempty"` // Shared by every operation (OpenAPI 3.1 output)
+}
+
+// operation returns the operation slot for an HTTP method (nil for methods OpenAPI can't describe)
+func (p *OpenAPIPath) operation(method string) **OpenAPIOperation {
+	switch method {
+	case http.MethodGet:
+		return &p.GET
+	case http.MethodPost:
+		return &p.POST
+	case http.MethodPut:
+		return &p.PUT
+	case http.MethodDelete:
+		return &p.DELETE
+	case http.MethodPatch:
+		return &p.PATCH
+	case http.MethodHead:
+		return &p.HEAD
+	case http.MethodOptions:
+		return &p.OPTIONS
+	case http.MethodTrace:
+		return &p.TRACE
+	}
+	return nil
+}
+
+// openAPIMethods lists the HTTP methods OpenAPI can describe, in spec order
+var openAPIMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete,
+	http.MethodPatch, http.MethodHead, http.MethodOptions, http.MethodTrace,
+}
+
+// operations returns the path's operations in a fixed order
+func (p *OpenAPIPath) operations() []*OpenAPIOperation {
+	var ops []*OpenAPIOperation
+	for _, method := range openAPIMethods {
+		if op := *p.operation(method); op != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
 }
 
 // OpenAPIOperation represents an API operation
@@ -66,6 +131,9 @@ type OpenAPIOperation struct {
 	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
 	Responses   map[string]OpenAPIResponse `json:"responses"`
 	Security    []map[string][]string      `json:"security,omitempty"`
+
+	Deprecated   bool          `json:"deprecated,omitempty"`
+	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
 }
 
 // OpenAPIParameter represents a parameter
@@ -76,6 +144,8 @@ type OpenAPIParameter struct {
 	Required    bool           `json:"required,omitempty"`
 	Schema      *OpenAPISchema `json:"schema,omitempty"`
 	Example     any            `json:"example,omitempty"`
+	Style       string         `json:"style,omitempty"`   // Serialization: form (arrays), deepObject (maps)
+	Explode     *bool          `json:"explode,omitempty"` // Arrays as repeated keys (tag=a&tag=b)
 }
 
 // OpenAPIRequestBody represents a request body
@@ -94,12 +164,48 @@ type OpenAPIMediaType struct {
 // OpenAPIResponse represents a response
 type OpenAPIResponse struct {
 	Description string                      `json:"description"`
+	Headers     map[string]OpenAPIHeader    `json:"headers,omitempty"`
 	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
 }
 
-// OpenAPIComponents contains reusable schemas
+// OpenAPIHeader represents a response header
+type OpenAPIHeader struct {
+	Description string         `json:"description,omitempty"`
+	Schema      *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPIComponents contains reusable schemas and security schemes
 type OpenAPIComponents struct {
-	Schemas map[string]*OpenAPISchema `json:"schemas,omitempty"`
+	Schemas         map[string]*OpenAPISchema  `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme represents a security scheme (see DescribeMiddleware)
+type SecurityScheme struct {
+	Type             string      `json:"type"` // apiKey, http, oauth2, openIdConnect
+	Description      string      `json:"description,omitempty"`
+	Name             string      `json:"name,omitempty"`         // apiKey: header, query or cookie name
+	In               string      `json:"in,omitempty"`           // apiKey: header, query, cookie
+	Scheme           string      `json:"scheme,omitempty"`       // http: bearer, basic
+	BearerFormat     string      `json:"bearerFormat,omitempty"` // http bearer: e.g., JWT
+	Flows            *OAuthFlows `json:"flows,omitempty"`        // oauth2
+	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty"`
+}
+
+// OAuthFlows represents the OAuth2 flows a security scheme supports
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+// OAuthFlow represents an OAuth2 flow and its available scopes
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	RefreshURL       string            `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"` // Scope name -> description
 }
 
 // OpenAPISchema represents a JSON schema
@@ -110,6 +216,9 @@ type OpenAPISchema struct {
 	Properties  map[string]*OpenAPISchema `json:"properties,omitempty"`
 	Required    []string                  `json:"required,omitempty"`
 	Items       *OpenAPISchema            `json:"items,omitempty"`
+	MinItems    *int                      `json:"minItems,omitempty"`
+	MaxItems    *int                      `json:"maxItems,omitempty"`
+	UniqueItems bool                      `json:"uniqueItems,omitempty"`
 	Enum        []any                     `json:"enum,omitempty"`
 	Minimum     *float64                  `json:"minimum,omitempty"`
 	Maximum     *float64                  `json:"maximum,omitempty"`
@@ -118,6 +227,58 @@ type OpenAPISchema struct {
 	Pattern     string                    `json:"pattern,omitempty"`
 	Example     any                       `json:"example,omitempty"`
 	Ref         string                    `json:"$ref,omitempty"`
+
+	AdditionalProperties *OpenAPISchema `json:"additionalProperties,omitempty"` // Map value schema
+	ExclusiveMinimum     bool           `json:"exclusiveMinimum,omitempty"`     // Minimum is exclusive (gt)
+	ExclusiveMaximum     bool           `json:"exclusiveMaximum,omitempty"`     // Maximum is exclusive (lt)
+	Nullable             bool           `json:"nullable,omitempty"`             // Value may be null (Optional fields)
+	MultipleOf           *float64       `json:"multipleOf,omitempty"`           // Value must be a multiple of (custom rules)
+	Default              any            `json:"default,omitempty"`              // Value used when the field is missing (`default` tag)
+
+	jsonSchema bool         // Marshal as JSON Schema 2020-12 (OpenAPI 3.1)
+	goType     reflect.Type // Go type of a component schema, so same-named types get distinct names
+}
+
+// MarshalJSON encodes the schema for OpenAPI 3.0, or as JSON Schema 2020-12 for OpenAPI 3.1:
+// nullable types become type arrays, exclusive bounds become numbers and examples become arrays.
+func (s *OpenAPISchema) MarshalJSON() ([]byte, error) {
+	type plain OpenAPISchema // Without the MarshalJSON method
+	if !s.jsonSchema {
+		return json.Marshal((*plain)(s))
+	}
+
+	// Outer fields shadow the embedded fields with the same JSON names
+	out := struct {
+		Type             any      `json:"type,omitempty"`
+		Minimum          *float64 `json:"minimum,omitempty"`
+		Maximum          *float64 `json:"maximum,omitempty"`
+		ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+		Nullable         bool     `json:"nullable,omitempty"`
+		Example          any      `json:"example,omitempty"`
+		Examples         []any    `json:"examples,omitempty"`
+		*plain
+	}{
+		Minimum: s.Minimum,
+		Maximum: s.Maximum,
+		plain:   (*plain)(s),
+	}
+	switch {
+	case s.Nullable && s.Type != "":
+		out.Type = []string{s.Type, "null"}
+	case s.Type != "":
+		out.Type = s.Type
+	}
+	if s.ExclusiveMinimum {
+		out.Minimum, out.ExclusiveMinimum = nil, s.Minimum
+	}
+	if s.ExclusiveMaximum {
+		out.Maximum, out.ExclusiveMaximum = nil, s.Maximum
+	}
+	if s.Example != nil {
+		out.Examples = []any{s.Example}
+	}
+	return json.Marshal(out)
 }
 
 // RouteMetadata contains metadata for generating OpenAPI docs
@@ -128,8 +289,18 @@ type RouteMetadata struct {
 	RequestSchema  *Schema
 	RequestBody    any // Example request body
 	QuerySchema    *Schema
-	ResponseSchema map[int]any // Status code -> example response
+	ResponseSchema map[int]any // Status code -> example response (typed handlers document their response type for 2xx codes)
 	OperationID    string
+	Deprecated     bool
+	ExternalDocs   *ExternalDocs
+}
+
+// Webhook documents a request the API sends to its clients (OpenAPI 3.1 only).
+// The request body and responses are described with RouteMetadata like routes.
+type Webhook struct {
+	Name     string // Webhook name, e.g. "orderCreated"
+	Method   string
+	Metadata RouteMetadata
 }
 
 // OpenAPIConfig configures OpenAPI generation
@@ -140,12 +311,29 @@ type OpenAPIConfig struct {
 	Servers     []OpenAPIServer
 	Contact     *Contact
 	License     *License
+
+	OpenAPIVersion string // OpenAPIVersion30 (default) or OpenAPIVersion31
+	ExternalDocs   *ExternalDocs
+	Webhooks       []Webhook // Documented with OpenAPIVersion31
+
+	DocsRenderer DocsRenderer // UI served by EnableSwagger (default: RendererSwaggerUI)
+	DocsAssets   fs.FS        // Renderer assets served under the UI path instead of a CDN (see the docsui package)
 }
 
-// GenerateOpenAPI generates an OpenAPI 3.0 specification from the router
+// GenerateOpenAPI generates an OpenAPI specification from the router (3.0 unless config.OpenAPIVersion
+// is OpenAPIVersion31). Routes are processed in path and method order, so the same routes always
+// produce the same spec. Panics on an unsupported OpenAPIVersion.
 func (r *Router) GenerateOpenAPI(config OpenAPIConfig) *OpenAPISpec {
+	version := config.OpenAPIVersion
+	if version == "" {
+		version = OpenAPIVersion30
+	}
+	if version != OpenAPIVersion30 && version != OpenAPIVersion31 {
+		panic(fmt.Sprintf("nimbus: unsupported OpenAPI version %q", version))
+	}
+
 	spec := &OpenAPISpec{
-		OpenAPI: "3.0.3",
+		OpenAPI: version,
 		Info: OpenAPIInfo{
 			Title:       config.Title,
 			Description: config.Description,
@@ -158,14 +346,147 @@ func (r *Router) GenerateOpenAPI(config OpenAPIConfig) *OpenAPISpec {
 		Components: OpenAPIComponents{
 			Schemas: make(map[string]*OpenAPISchema),
 		},
+		ExternalDocs: config.ExternalDocs,
 	}
 
 	// Process all routes
 	r.generatePathsFromRoutes(spec)
 
+	if version == OpenAPIVersion31 {
+		r.generateWebhooks(spec, config.Webhooks)
+		for path, item := range spec.Paths {
+			hoistPathParameters(&item)
+			spec.Paths[path] = item
+		}
+		spec.walkSchemas(func(_ string, schema *OpenAPISchema) {
+			schema.jsonSchema = true
+		})
+	}
+
 	return spec
 }
 
+// generateWebhooks documents webhooks as operations keyed by webhook name.
+// Webhooks are requests sent by the API, so the router's middleware isn't documented on them.
+func (r *Router) generateWebhooks(spec *OpenAPISpec, webhooks []Webhook) {
+	for _, webhook := range webhooks {
+		if spec.Webhooks == nil {
+			spec.Webhooks = make(map[string]OpenAPIPath)
+		}
+		item := spec.Webhooks[webhook.Name]
+		slot := item.operation(webhook.Method)
+		if slot == nil {
+			panic(fmt.Sprintf("nimbus: webhook %s: unsupported method %q", webhook.Name, webhook.Method))
+		}
+
+		metadata := webhook.Metadata
+		if metadata.OperationID == "" {
+			metadata.OperationID = webhook.Name
+		}
+		*slot = r.createOperation(&Route{method: webhook.Method, pattern: webhook.Name}, &metadata, spec)
+		spec.Webhooks[webhook.Name] = item
+	}
+}
+
+// hoistPathParameters moves path parameters shared by every operation of a path to the path item
+func hoistPathParameters(item *OpenAPIPath) {
+	ops := item.operations()
+	if len(ops) == 0 {
+		return
+	}
+
+	var shared []OpenAPIParameter
+	for _, param := range ops[0].Parameters {
+		if param.In == "path" {
+			shared = append(shared, param)
+		}
+	}
+	for _, op := range ops[1:] {
+		var params []OpenAPIParameter
+		for _, param := range op.Parameters {
+			if param.In == "path" {
+				params = append(params, param)
+			}
+		}
+		if !reflect.DeepEqual(params, shared) {
+			return
+		}
+	}
+	if len(shared) == 0 {
+		return
+	}
+
+	item.Parameters = shared
+	for _, op := range ops {
+		params := op.Parameters[:0:0]
+		for _, param := range op.Parameters {
+			if param.In != "path" {
+				params = append(params, param)
+			}
+		}
+		op.Parameters = params
+	}
+}
+
+// walkSchemas calls fn for every schema in the spec, including nested ones, with its location
+// (e.g., "paths./users/{id}.get.parameters.id"). Schemas shared by several locations are visited once.
+func (spec *OpenAPISpec) walkSchemas(fn func(location string, schema *OpenAPISchema)) {
+	seen := make(map[*OpenAPISchema]bool)
+	var walk func(location string, schema *OpenAPISchema)
+	walk = func(location string, schema *OpenAPISchema) {
+		if schema == nil || seen[schema] {
+			return
+		}
+		seen[schema] = true
+		fn(location, schema)
+		for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+			walk(location+".properties."+name, schema.Properties[name])
+		}
+		walk(location+".items", schema.Items)
+		walk(location+".additionalProperties", schema.AdditionalProperties)
+	}
+	walkContent := func(location string, content map[string]OpenAPIMediaType) {
+		for _, mediaType := range slices.Sorted(maps.Keys(content)) {
+			walk(location+".content."+mediaType, content[mediaType].Schema)
+		}
+	}
+	walkPath := func(location string, item OpenAPIPath) {
+		for _, param := range item.Parameters {
+			walk(location+".parameters."+param.Name, param.Schema)
+		}
+		for _, method := range openAPIMethods {
+			op := *item.operation(method)
+			if op == nil {
+				continue
+			}
+			opLocation := location + "." + strings.ToLower(method)
+			for _, param := range op.Parameters {
+				walk(opLocation+".parameters."+param.Name, param.Schema)
+			}
+			if op.RequestBody != nil {
+				walkContent(opLocation+".requestBody", op.RequestBody.Content)
+			}
+			for _, code := range slices.Sorted(maps.Keys(op.Responses)) {
+				resp := op.Responses[code]
+				walkContent(opLocation+".responses."+code, resp.Content)
+				for _, name := range slices.Sorted(maps.Keys(resp.Headers)) {
+					walk(opLocation+".responses."+code+".headers."+name, resp.Headers[name].Schema)
+				}
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(spec.Components.Schemas)) {
+		walk("components.schemas."+name, spec.Components.Schemas[name])
+	}
+	for _, path := range slices.Sorted(maps.Keys(spec.Paths)) {
+		walkPath("paths."+path, spec.Paths[path])
+	}
+	for _, name := range slices.Sorted(maps.Keys(spec.Webhooks)) {
+		walkPath("webhooks."+name, spec.Webhooks[name])
+	}
+}
+
 // generatePathsFromRoutes processes routes and generates OpenAPI paths
 func (r *Router) generatePathsFromRoutes(spec *OpenAPISpec) {
 	table := r.table.Load()
@@ -201,40 +522,41 @@ func (r *Router) generatePathsFromRoutes(spec *OpenAPISpec) {
 		}
 	}
 
-	// Process all collected routes
-	for method, pathMap := range allRoutes {
+	// Process routes in path and method order so component names and operation IDs are deterministic
+	var routes []*Route
+	for _, pathMap := range allRoutes {
 		for _, route := range pathMap {
-			// Convert path parameters from :param to {param}
-			openAPIPath := convertPathParams(route.pattern)
+			routes = append(routes, route)
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].pattern != routes[j].pattern {
+			return routes[i].pattern < routes[j].pattern
+		}
+		return routes[i].method < routes[j].method
+	})
 
-			// Get or create path item
-			pathItem, exists := spec.Paths[openAPIPath]
-			if !exists {
-				pathItem = OpenAPIPath{}
-			}
+	for _, route := range routes {
+		// Convert path parameters from :param to {param}
+		openAPIPath := convertPathParams(route.pattern)
 
-			// Get route metadata
-			metadata := r.getRouteMetadata(route)
-
-			// Create operation
-			operation := r.createOperation(route, metadata, spec)
-
-			// Add operation to path based on method
-			switch method {
-			case "GET":
-				pathItem.GET = operation
-			case "POST":
-				pathItem.POST = operation
-			case "PUT":
-				pathItem.PUT = operation
-			case "DELETE":
-				pathItem.DELETE = operation
-			case "PATCH":
-				pathItem.PATCH = operation
-			}
+		// Get or create path item
+		pathItem := spec.Paths[openAPIPath]
 
-			spec.Paths[openAPIPath] = pathItem
+		// Methods OpenAPI has no operation for (e.g., CONNECT) aren't documented
+		slot := pathItem.operation(route.method)
+		if slot == nil {
+			continue
 		}
+
+		// Get route metadata
+		metadata := r.getRouteMetadata(route)
+
+		// Create operation, documenting what the route's middleware adds
+		*slot = r.createOperation(route, metadata, spec)
+		r.applyMiddlewareDocs(*slot, route, spec)
+
+		spec.Paths[openAPIPath] = pathItem
 	}
 }
 
@@ -247,6 +569,9 @@ func (r *Router) createOperation(route *Route, metadata *RouteMetadata, spec *Op
 		OperationID: metadata.OperationID,
 		Parameters:  []OpenAPIParameter{},
 		Responses:   make(map[string]OpenAPIResponse),
+
+		Deprecated:   metadata.Deprecated,
+		ExternalDocs: metadata.ExternalDocs,
 	}
 
 	// Generate operation ID if not provided
@@ -254,6 +579,21 @@ func (r *Router) createOperation(route *Route, metadata *RouteMetadata, spec *Op
 		operation.OperationID = generateOperationID(route.method, route.pattern)
 	}
 
+	// Schemas captured from typed handlers document the request; metadata schemas take precedence.
+	// Custom rules registered on the router document themselves like global ones.
+	rules := &r.rules
+	requestSchema, querySchema := metadata.RequestSchema, metadata.QuerySchema
+	var paramsSchema *Schema
+	if route.spec != nil {
+		paramsSchema = route.spec.params
+		if requestSchema == nil {
+			requestSchema = route.spec.body
+		}
+		if querySchema == nil {
+			querySchema = route.spec.query
+		}
+	}
+
 	// Extract path parameters
 	pathParams := extractPathParams(route.pattern)
 	for _, param := range pathParams {
@@ -262,219 +602,577 @@ func (r *Router) createOperation(route *Route, metadata *RouteMetadata, spec *Op
 			In:          "path",
 			Description: fmt.Sprintf("Path parameter: %s", param),
 			Required:    true,
-			Schema: &OpenAPISchema{
-				Type: "string",
-			},
+			Schema:      pathParamSchema(paramsSchema, param, rules),
 		})
 	}
 
 	// Add query parameters from schema
-	if metadata.QuerySchema != nil {
-		queryParams := schemaToQueryParameters(metadata.QuerySchema)
+	if querySchema != nil {
+		queryParams := schemaToQueryParameters(querySchema, rules)
 		operation.Parameters = append(operation.Parameters, queryParams...)
 	}
 
 	// Add request body for POST/PUT/PATCH
-	if (route.method == "POST" || route.method == "PUT" || route.method == "PATCH") && metadata.RequestSchema != nil {
-		schemaName := getSchemaName(metadata.RequestSchema)
-		schemaRef := fmt.Sprintf("#/components/schemas/%s", schemaName)
-
-		// Add schema to components if not already present
-		if _, exists := spec.Components.Schemas[schemaName]; !exists {
-			spec.Components.Schemas[schemaName] = schemaToOpenAPISchema(metadata.RequestSchema)
-		}
-
+	if (route.method == "POST" || route.method == "PUT" || route.method == "PATCH") && requestSchema != nil {
 		operation.RequestBody = &OpenAPIRequestBody{
 			Required: true,
 			Content: map[string]OpenAPIMediaType{
 				"application/json": {
-					Schema: &OpenAPISchema{
-						Ref: schemaRef,
-					},
+					// Named structs are added to components; anonymous ones are inlined
+					Schema:  nestedSchemaRef(requestSchema, spec.Components.Schemas, rules),
 					Example: metadata.RequestBody,
 				},
 			},
 		}
+
+		// Schemas with file fields accept multipart bodies; schemas with form tags accept URL-encoded bodies
+		if requestSchema.hasFiles {
+			operation.RequestBody.Content[MIMEMultipartForm] = OpenAPIMediaType{
+				Schema: schemaToFormSchema(requestSchema, spec.Components.Schemas, rules),
+			}
+		} else if requestSchema.hasForm {
+			operation.RequestBody.Content[MIMEApplicationForm] = OpenAPIMediaType{
+				Schema: schemaToFormSchema(requestSchema, spec.Components.Schemas, rules),
+			}
+		}
+	}
+
+	// Typed handlers declare their response type, documented for success responses
+	var responseSchema *OpenAPISchema
+	if route.spec != nil && route.spec.responseType != nil {
+		responseSchema = typeToOpenAPISchema(route.spec.responseType, spec.Components.Schemas, rules)
 	}
 
-	// Add responses
+	// Add responses. Success data is wrapped in the SuccessResponse envelope and errors are sent as ErrorResponse.
 	if len(metadata.ResponseSchema) > 0 {
 		for statusCode, example := range metadata.ResponseSchema {
+			var content map[string]OpenAPIMediaType
+			switch {
+			case statusCode == http.StatusNoContent:
+			case statusCode >= 400:
+				content = jsonContent(errorResponseRef(spec), example)
+			default:
+				data := &OpenAPISchema{}
+				if responseSchema != nil && statusCode >= 200 && statusCode < 300 {
+					data = responseSchema
+				}
+				content = jsonContent(successEnvelope(data), example)
+			}
 			operation.Responses[fmt.Sprintf("%d", statusCode)] = OpenAPIResponse{
 				Description: getStatusDescription(statusCode),
-				Content: map[string]OpenAPIMediaType{
-					"application/json": {
-						Schema: &OpenAPISchema{
-							Type: "object",
-						},
-						Example: example,
-					},
-				},
+				Content:     content,
 			}
 		}
 	} else {
-		// Default success response
-		operation.Responses["200"] = OpenAPIResponse{
-			Description: "Successful response",
-			Content: map[string]OpenAPIMediaType{
-				"application/json": {
-					Schema: &OpenAPISchema{
-						Type: "object",
-					},
-				},
-			},
+		data := responseSchema
+		if data == nil {
+			data = &OpenAPISchema{}
+		}
+		status := http.StatusOK
+		if route.spec != nil && route.spec.status != 0 {
+			status = route.spec.status
+		}
+		operation.Responses[fmt.Sprintf("%d", status)] = OpenAPIResponse{
+			Description: getStatusDescription(status),
+			Content:     jsonContent(successEnvelope(data), nil),
+		}
+		if route.spec != nil && route.spec.noContent {
+			operation.Responses["204"] = OpenAPIResponse{Description: getStatusDescription(http.StatusNoContent)}
 		}
 	}
 
 	// Always add error responses
-	operation.Responses["400"] = OpenAPIResponse{
-		Description: "Bad request",
-		Content: map[string]OpenAPIMediaType{
-			"application/json": {
-				Schema: &OpenAPISchema{
-					Type: "object",
-					Properties: map[string]*OpenAPISchema{
-						"error":   {Type: "string"},
-						"message": {Type: "string"},
+	if _, exists := operation.Responses["400"]; !exists {
+		operation.Responses["400"] = OpenAPIResponse{
+			Description: "Bad request",
+			Content:     jsonContent(validationErrorResponseRef(spec), nil),
+		}
+	}
+	if _, exists := operation.Responses["500"]; !exists {
+		operation.Responses["500"] = OpenAPIResponse{
+			Description: "Internal server error",
+			Content:     jsonContent(errorResponseRef(spec), nil),
+		}
+	}
+
+	return operation
+}
+
+// applyMiddlewareDocs documents what the route's middleware (router-level, group and route middleware
+// described with DescribeMiddleware) adds to the operation: security schemes, which are all required
+// together, error responses and response headers.
+func (r *Router) applyMiddlewareDocs(operation *OpenAPIOperation, route *Route, spec *OpenAPISpec) {
+	middlewares := append(append([]Middleware{}, r.table.Load().middlewares...), route.middlewares...)
+
+	requirement := make(map[string][]string)
+	headers := make(map[string]OpenAPIHeader)
+	for _, mw := range middlewares {
+		doc := lookupMiddlewareDoc(mw)
+		if doc == nil {
+			continue
+		}
+
+		if doc.SecurityScheme != nil && doc.SecurityName != "" {
+			if spec.Components.SecuritySchemes == nil {
+				spec.Components.SecuritySchemes = make(map[string]*SecurityScheme)
+			}
+			spec.Components.SecuritySchemes[doc.SecurityName] = doc.SecurityScheme
+
+			// Scopes are always listed, as an empty array when the scheme has none
+			scopes := requirement[doc.SecurityName]
+			if scopes == nil {
+				scopes = []string{}
+			}
+			requirement[doc.SecurityName] = append(scopes, doc.Scopes...)
+		}
+
+		// Responses documented by the route take precedence
+		for statusCode, resp := range doc.Responses {
+			code := fmt.Sprintf("%d", statusCode)
+			if _, exists := operation.Responses[code]; exists {
+				continue
+			}
+			operation.Responses[code] = OpenAPIResponse{
+				Description: resp.Description,
+				Headers:     resp.Headers,
+				Content:     jsonContent(errorResponseRef(spec), nil),
+			}
+		}
+		maps.Copy(headers, doc.Headers)
+	}
+
+	if len(requirement) > 0 {
+		operation.Security = []map[string][]string{requirement}
+	}
+
+	// Headers set on every response
+	if len(headers) > 0 {
+		for code, resp := range operation.Responses {
+			resp.Headers = maps.Clone(resp.Headers)
+			if resp.Headers == nil {
+				resp.Headers = make(map[string]OpenAPIHeader, len(headers))
+			}
+			maps.Copy(resp.Headers, headers)
+			operation.Responses[code] = resp
+		}
+	}
+}
+
+// jsonContent returns JSON response content with the given schema
+func jsonContent(schema *OpenAPISchema, example any) map[string]OpenAPIMediaType {
+	return map[string]OpenAPIMediaType{
+		"application/json": {Schema: schema, Example: example},
+	}
+}
+
+// successEnvelope returns the schema of a SuccessResponse wrapping data
+func successEnvelope(data *OpenAPISchema) *OpenAPISchema {
+	return &OpenAPISchema{
+		Type:     "object",
+		Required: []string{"success"},
+		Properties: map[string]*OpenAPISchema{
+			"success": {Type: "boolean"},
+			"data":    data,
+			"message": {Type: "string"},
+		},
+	}
+}
+
+// errorResponseRef returns a $ref to the ErrorResponse schema, adding it to components
+func errorResponseRef(spec *OpenAPISpec) *OpenAPISchema {
+	if _, exists := spec.Components.Schemas["ErrorResponse"]; !exists {
+		spec.Components.Schemas["ErrorResponse"] = &OpenAPISchema{
+			Type:     "object",
+			Required: []string{"error", "code"},
+			Properties: map[string]*OpenAPISchema{
+				"error":   {Type: "string", Description: "Error code"},
+				"message": {Type: "string"},
+				"code":    {Type: "integer", Description: "HTTP status code"},
+			},
+		}
+	}
+	return &OpenAPISchema{Ref: "#/components/schemas/ErrorResponse"}
+}
+
+// validationErrorResponseRef returns a $ref to the schema of validation failures (see
+// Context.SendValidationError), adding it to components
+func validationErrorResponseRef(spec *OpenAPISpec) *OpenAPISchema {
+	if _, exists := spec.Components.Schemas["ValidationErrorResponse"]; !exists {
+		spec.Components.Schemas["ValidationErrorResponse"] = &OpenAPISchema{
+			Type:     "object",
+			Required: []string{"error", "message"},
+			Properties: map[string]*OpenAPISchema{
+				"error":   {Type: "string", Description: "Error code"},
+				"message": {Type: "string"},
+				"details": {
+					Type: "array",
+					Items: &OpenAPISchema{
+						Type:     "object",
+						Required: []string{"field", "tag", "message"},
+						Properties: map[string]*OpenAPISchema{
+							"field":   {Type: "string"},
+							"value":   {Description: "Rejected value"},
+							"tag":     {Type: "string", Description: "Failed rule"},
+							"param":   {Type: "string"},
+							"message": {Type: "string"},
+							"offset":  {Type: "integer", Description: "Byte offset in the request body"},
+						},
 					},
 				},
 			},
-		},
+		}
 	}
-
-	return operation
+	return &OpenAPISchema{Ref: "#/components/schemas/ValidationErrorResponse"}
 }
 
-// schemaToOpenAPISchema converts a validation Schema to OpenAPI schema
-func schemaToOpenAPISchema(schema *Schema) *OpenAPISchema {
+// schemaToOpenAPISchema converts a validation Schema to OpenAPI schema.
+// Nested named structs are added to components and referenced with $ref.
+func schemaToOpenAPISchema(schema *Schema, components map[string]*OpenAPISchema, rules *ruleRegistry) *OpenAPISchema {
 	openAPISchema := &OpenAPISchema{
 		Type:       "object",
 		Properties: make(map[string]*OpenAPISchema),
 		Required:   []string{},
 	}
 
-	for fieldName, rule := range schema.fields {
-		propSchema := &OpenAPISchema{}
-
-		// Get field type from struct
-		structField, ok := schema.structType.FieldByName(getStructFieldName(schema.structType, fieldName))
-		if !ok {
+	for _, fieldName := range schema.order {
+		rule := schema.fields[fieldName]
+		// Form-only fields (e.g., file uploads) aren't part of the JSON body
+		if !rule.inJSON {
 			continue
 		}
 
-		// Determine type
-		switch structField.Type.Kind() {
-		case reflect.String:
-			propSchema.Type = "string"
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			propSchema.Type = "integer"
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			propSchema.Type = "integer"
-		case reflect.Float32, reflect.Float64:
-			propSchema.Type = "number"
-		case reflect.Bool:
-			propSchema.Type = "boolean"
-		default:
-			propSchema.Type = "string"
+		structField := schema.structType.FieldByIndex(rule.index)
+		openAPISchema.Properties[fieldName] = fieldToOpenAPISchema(structField.Type, rule, components, rules)
+
+		if rule.required {
+			openAPISchema.Required = append(openAPISchema.Required, fieldName)
 		}
+	}
+
+	return openAPISchema
+}
+
+// schemaToFormSchema converts a validation Schema to an OpenAPI schema for form bodies.
+// Properties are named by form field name; file fields become binary strings.
+func schemaToFormSchema(schema *Schema, components map[string]*OpenAPISchema, rules *ruleRegistry) *OpenAPISchema {
+	openAPISchema := &OpenAPISchema{
+		Type:       "object",
+		Properties: make(map[string]*OpenAPISchema),
+		Required:   []string{},
+	}
 
-		// Add validation constraints
-		if rule.minLength >= 0 {
-			minLen := rule.minLength
-			propSchema.MinLength = &minLen
+	for _, fieldName := range schema.order {
+		rule := schema.fields[fieldName]
+		structField := schema.structType.FieldByIndex(rule.index)
+		openAPISchema.Properties[rule.formName] = fieldToOpenAPISchema(structField.Type, rule, components, rules)
+
+		if rule.required {
+			openAPISchema.Required = append(openAPISchema.Required, rule.formName)
 		}
-		if rule.maxLength >= 0 {
-			maxLen := rule.maxLength
-			propSchema.MaxLength = &maxLen
+	}
+
+	return openAPISchema
+}
+
+// fieldToOpenAPISchema converts a struct field type and its validation rule to an OpenAPI property schema
+func fieldToOpenAPISchema(fieldType reflect.Type, rule fieldRule, components map[string]*OpenAPISchema, rules *ruleRegistry) *OpenAPISchema {
+	propSchema := &OpenAPISchema{}
+
+	// File uploads are documented as binary strings (arrays of them for multiple files)
+	switch fieldType {
+	case fileHeaderType:
+		propSchema.Type = "string"
+		propSchema.Format = "binary"
+		return propSchema
+	case fileHeaderSliceType:
+		propSchema.Type = "array"
+		propSchema.Items = &OpenAPISchema{Type: "string", Format: "binary"}
+		return propSchema
+	}
+
+	// Optional[T] is documented as a nullable T (a $ref can't carry nullable in OpenAPI 3.0)
+	if isOptionalType(fieldType) {
+		propSchema = fieldToOpenAPISchema(fieldType.Field(0).Type, rule, components, rules)
+		propSchema.Nullable = propSchema.Ref == ""
+		return propSchema
+	}
+
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	// Determine type
+	switch fieldType.Kind() {
+	case reflect.String:
+		propSchema.Type = "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		propSchema.Type = "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		propSchema.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		propSchema.Type = "number"
+	case reflect.Bool:
+		propSchema.Type = "boolean"
+	case reflect.Struct:
+		if fieldType == timeType {
+			propSchema.Type = "string"
+			propSchema.Format = "date-time"
+		} else if rule.nested != nil {
+			return nestedSchemaRef(rule.nested, components, rules)
 		}
-		if rule.min != nil {
-			minFloat := float64(*rule.min)
-			propSchema.Minimum = &minFloat
+	case reflect.Slice, reflect.Array:
+		propSchema.Type = "array"
+		propSchema.Items = elemToOpenAPISchema(fieldType.Elem(), rule.elem, components, rules)
+		if rule.minItems >= 0 {
+			minItems := rule.minItems
+			propSchema.MinItems = &minItems
 		}
-		if rule.max != nil {
-			maxFloat := float64(*rule.max)
-			propSchema.Maximum = &maxFloat
+		if rule.maxItems >= 0 {
+			maxItems := rule.maxItems
+			propSchema.MaxItems = &maxItems
 		}
-		if rule.pattern != nil {
-			propSchema.Pattern = rule.pattern.String()
+		if rule.length >= 0 {
+			length := rule.length
+			propSchema.MinItems = &length
+			propSchema.MaxItems = &length
 		}
-		if len(rule.enum) > 0 {
-			propSchema.Enum = make([]any, len(rule.enum))
-			for i, v := range rule.enum {
-				propSchema.Enum[i] = v
-			}
+		propSchema.UniqueItems = rule.unique
+		propSchema.Default = defaultDocValue(fieldType, rule)
+		propSchema.Example = rule.example
+		applyCustomRuleSchemas(propSchema, rule, rules)
+		return propSchema
+	case reflect.Map:
+		propSchema.Type = "object"
+		propSchema.AdditionalProperties = elemToOpenAPISchema(fieldType.Elem(), rule.elem, components, rules)
+		propSchema.Default = defaultDocValue(fieldType, rule)
+		propSchema.Example = rule.example
+		applyCustomRuleSchemas(propSchema, rule, rules)
+		return propSchema
+	default:
+		propSchema.Type = "string"
+	}
+
+	// Add validation constraints
+	if rule.minLength >= 0 {
+		minLen := rule.minLength
+		propSchema.MinLength = &minLen
+	}
+	if rule.maxLength >= 0 {
+		maxLen := rule.maxLength
+		propSchema.MaxLength = &maxLen
+	}
+	if rule.length >= 0 {
+		length := rule.length
+		propSchema.MinLength = &length
+		propSchema.MaxLength = &length
+	}
+	if rule.min != nil {
+		minFloat := *rule.min
+		propSchema.Minimum = &minFloat
+	}
+	if rule.max != nil {
+		maxFloat := *rule.max
+		propSchema.Maximum = &maxFloat
+	}
+	if rule.gte != nil {
+		gte := *rule.gte
+		propSchema.Minimum = &gte
+	}
+	if rule.lte != nil {
+		lte := *rule.lte
+		propSchema.Maximum = &lte
+	}
+	if rule.gt != nil {
+		gt := *rule.gt
+		propSchema.Minimum = &gt
+		propSchema.ExclusiveMinimum = true
+	}
+	if rule.lt != nil {
+		lt := *rule.lt
+		propSchema.Maximum = &lt
+		propSchema.ExclusiveMaximum = true
+	}
+	if rule.pattern != nil {
+		propSchema.Pattern = rule.pattern.String()
+	} else if pattern := affixPattern(rule); pattern != "" {
+		propSchema.Pattern = pattern
+	}
+	if len(rule.enum) > 0 {
+		propSchema.Enum = make([]any, len(rule.enum))
+		for i, v := range rule.enum {
+			propSchema.Enum[i] = v
 		}
-		if rule.email {
-			propSchema.Format = "email"
+	}
+	if len(rule.oneOf) > 0 {
+		propSchema.Enum = make([]any, len(rule.oneOf))
+		for i, v := range rule.oneOf {
+			propSchema.Enum[i] = v
+			// Numeric fields list numeric enum values
+			if propSchema.Type == "integer" || propSchema.Type == "number" {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					propSchema.Enum[i] = f
+				}
+			}
 		}
+	}
+	if rule.email {
+		propSchema.Format = "email"
+	}
+	for _, format := range rule.formats {
+		applyFormat(propSchema, format, rule.layout)
+	}
+	propSchema.Default = defaultDocValue(fieldType, rule)
+	propSchema.Example = rule.example
+	applyCustomRuleSchemas(propSchema, rule, rules)
 
-		openAPISchema.Properties[fieldName] = propSchema
+	return propSchema
+}
 
-		if rule.required {
-			openAPISchema.Required = append(openAPISchema.Required, fieldName)
+// applyFormat documents a string format rule as an OpenAPI format (or pattern)
+func applyFormat(propSchema *OpenAPISchema, format, layout string) {
+	switch format {
+	case "url":
+		propSchema.Format = "uri"
+	case "datetime":
+		switch layout {
+		case time.RFC3339, time.RFC3339Nano:
+			propSchema.Format = "date-time"
+		case time.DateOnly:
+			propSchema.Format = "date"
+		default:
+			propSchema.Format = "date-time"
+			propSchema.Description = fmt.Sprintf("Layout: %s", layout)
 		}
+	case "alpha":
+		if propSchema.Pattern == "" {
+			propSchema.Pattern = alphaRegex.String()
+		}
+	case "alphanumeric":
+		if propSchema.Pattern == "" {
+			propSchema.Pattern = alphanumericRegex.String()
+		}
+	default:
+		// uuid, ip, ipv4, ipv6, cidr, hostname, duration
+		propSchema.Format = format
 	}
+}
 
-	return openAPISchema
+// affixPattern builds a pattern for contains/startswith/endswith rules (when no explicit pattern is set)
+func affixPattern(rule fieldRule) string {
+	switch {
+	case rule.startsWith != "" && rule.endsWith != "":
+		return "^" + regexp.QuoteMeta(rule.startsWith) + ".*" + regexp.QuoteMeta(rule.endsWith) + "$"
+	case rule.startsWith != "":
+		return "^" + regexp.QuoteMeta(rule.startsWith)
+	case rule.endsWith != "":
+		return regexp.QuoteMeta(rule.endsWith) + "$"
+	case rule.contains != "":
+		return regexp.QuoteMeta(rule.contains)
+	}
+	return ""
+}
+
+// elemToOpenAPISchema converts a slice element or map value type to an OpenAPI schema
+func elemToOpenAPISchema(elemType reflect.Type, elemRule *fieldRule, components map[string]*OpenAPISchema, rules *ruleRegistry) *OpenAPISchema {
+	if elemRule == nil {
+		elemRule = &fieldRule{minLength: -1, maxLength: -1, minItems: -1, maxItems: -1}
+	}
+	return fieldToOpenAPISchema(elemType, *elemRule, components, rules)
 }
 
-// schemaToQueryParameters converts a Schema to query parameters
-func schemaToQueryParameters(schema *Schema) []OpenAPIParameter {
+// typeToOpenAPISchema converts a Go type (e.g., a typed handler's response) to an OpenAPI schema.
+// Named structs are added to components and referenced with $ref.
+func typeToOpenAPISchema(t reflect.Type, components map[string]*OpenAPISchema, rules *ruleRegistry) *OpenAPISchema {
+	return fieldToOpenAPISchema(t, buildFieldRule(t, "", make(map[reflect.Type]*Schema)), components, rules)
+}
+
+// nestedSchemaRef returns a $ref to a nested struct schema, adding it to components.
+// Anonymous structs have no component name and are inlined.
+// Without components (e.g., query parameters) nested structs are documented as plain objects.
+func nestedSchemaRef(schema *Schema, components map[string]*OpenAPISchema, rules *ruleRegistry) *OpenAPISchema {
+	if components == nil {
+		return &OpenAPISchema{Type: "object"}
+	}
+	if schema.structType.Name() == "" {
+		return schemaToOpenAPISchema(schema, components, rules)
+	}
+
+	name := componentName(schema.structType, components)
+	if _, exists := components[name]; !exists {
+		// Reserve the name first so recursive types terminate
+		components[name] = &OpenAPISchema{goType: schema.structType}
+		*components[name] = *schemaToOpenAPISchema(schema, components, rules)
+		components[name].goType = schema.structType
+	}
+	return &OpenAPISchema{Ref: fmt.Sprintf("#/components/schemas/%s", name)}
+}
+
+var (
+	typeArgPackage        = regexp.MustCompile(`[^\[\],*\s]*\.`) // Package path of a type argument (pkg/path.)
+	invalidComponentChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+)
+
+// componentName returns the component name of a named struct type: its name made valid for a
+// component key (Page[pkg.Item] becomes Page_Item), qualified by its package path when a
+// different type already uses that name (e.g., billing.Invoice and github.com_acme_legacy.Invoice)
+func componentName(t reflect.Type, components map[string]*OpenAPISchema) string {
+	available := func(name string) bool {
+		existing, exists := components[name]
+		return !exists || existing.goType == t
+	}
+
+	name := sanitizeComponentName(typeArgPackage.ReplaceAllString(t.Name(), ""))
+	if available(name) {
+		return name
+	}
+	qualified := sanitizeComponentName(t.PkgPath()) + "." + name
+	for i := 2; !available(qualified); i++ {
+		// Generic types instantiated with same-named types from different packages
+		qualified = fmt.Sprintf("%s.%s_%d", sanitizeComponentName(t.PkgPath()), name, i)
+	}
+	return qualified
+}
+
+// sanitizeComponentName replaces characters that aren't allowed in component names with underscores
+func sanitizeComponentName(name string) string {
+	return strings.Trim(invalidComponentChars.ReplaceAllString(name, "_"), "_")
+}
+
+// schemaToQueryParameters converts a Schema to query parameters, plus header and cookie
+// parameters for fields tagged `header` or `cookie`.
+// Arrays are documented as repeated keys (style form, explode) and maps as deepObject
+// parameters (filter[status]=active), matching how ValidateQuery binds them.
+func schemaToQueryParameters(schema *Schema, rules *ruleRegistry) []OpenAPIParameter {
 	params := []OpenAPIParameter{}
 
-	for fieldName, rule := range schema.fields {
-		structField, ok := schema.structType.FieldByName(getStructFieldName(schema.structType, fieldName))
-		if !ok {
-			continue
+	for _, fieldName := range schema.order {
+		rule := schema.fields[fieldName]
+		structField := schema.structType.FieldByIndex(rule.index)
+		fieldType := structField.Type
+		if rule.optional {
+			fieldType = fieldType.Field(0).Type
 		}
 
 		param := OpenAPIParameter{
-			Name:     fieldName,
+			Name:     rule.queryName,
 			In:       "query",
 			Required: rule.required,
-			Schema:   &OpenAPISchema{},
-		}
-
-		// Determine type
-		switch structField.Type.Kind() {
-		case reflect.String:
-			param.Schema.Type = "string"
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			param.Schema.Type = "integer"
-		case reflect.Float32, reflect.Float64:
-			param.Schema.Type = "number"
-		case reflect.Bool:
-			param.Schema.Type = "boolean"
-		}
-
-		// Add constraints
-		if rule.minLength >= 0 {
-			minLen := rule.minLength
-			param.Schema.MinLength = &minLen
-		}
-		if rule.maxLength >= 0 {
-			maxLen := rule.maxLength
-			param.Schema.MaxLength = &maxLen
-		}
-		if rule.min != nil {
-			minFloat := float64(*rule.min)
-			param.Schema.Minimum = &minFloat
-		}
-		if rule.max != nil {
-			maxFloat := float64(*rule.max)
-			param.Schema.Maximum = &maxFloat
-		}
-		if len(rule.enum) > 0 {
-			param.Schema.Enum = make([]any, len(rule.enum))
-			for i, v := range rule.enum {
-				param.Schema.Enum[i] = v
-			}
+			Schema:   fieldToOpenAPISchema(structField.Type, rule, nil, rules),
 		}
-		if rule.pattern != nil {
-			param.Schema.Pattern = rule.pattern.String()
+		switch {
+		case rule.headerName != "":
+			param.Name, param.In = rule.headerName, "header"
+		case rule.cookieName != "":
+			param.Name, param.In = rule.cookieName, "cookie"
 		}
-		if rule.email {
-			param.Schema.Format = "email"
+
+		if isTextOnlyType(fieldType) {
+			// Durations and TextUnmarshaler types are sent as their text form
+			param.Schema = &OpenAPISchema{Type: "string"}
+		} else if style := queryStyle(fieldType); style != "" && param.In == "query" {
+			explode := true
+			param.Style = style
+			param.Explode = &explode
 		}
 
 		params = append(params, param)
@@ -483,13 +1181,30 @@ func schemaToQueryParameters(schema *Schema) []OpenAPIParameter {
 	return params
 }
 
+// pathParamSchema documents a path parameter using the field bound to it by a `path` tag
+// (strings for parameters without one)
+func pathParamSchema(schema *Schema, param string, rules *ruleRegistry) *OpenAPISchema {
+	if schema != nil {
+		for _, fieldName := range schema.order {
+			if rule := schema.fields[fieldName]; rule.pathName == param {
+				fieldType := schema.structType.FieldByIndex(rule.index).Type
+				if isTextOnlyType(fieldType) {
+					return &OpenAPISchema{Type: "string"}
+				}
+				return fieldToOpenAPISchema(fieldType, rule, nil, rules)
+			}
+		}
+	}
+	return &OpenAPISchema{Type: "string"}
+}
+
 // Helper functions
 
 func convertPathParams(path string) string {
-	// Convert :param to {param}
+	// Convert :param and *param (catch-all) to {param}
 	parts := strings.Split(path, "/")
 	for i, part := range parts {
-		if strings.HasPrefix(part, ":") {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
 			parts[i] = "{" + part[1:] + "}"
 		}
 	}
@@ -500,7 +1215,7 @@ func extractPathParams(pattern string) []string {
 	params := []string{}
 	parts := strings.Split(pattern, "/")
 	for _, part := range parts {
-		if strings.HasPrefix(part, ":") {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
 			params = append(params, part[1:])
 		}
 	}
@@ -540,15 +1255,6 @@ func capitalize(s string) string {
 	return strings.ToUpper(s[:1]) + s[1:]
 }
 
-func getSchemaName(schema *Schema) string {
-	// Get the name of the struct type
-	typeName := schema.structType.Name()
-	if typeName == "" {
-		typeName = "Request"
-	}
-	return typeName
-}
-
 func getStatusDescription(code int) string {
 	descriptions := map[int]string{
 		200: "Successful response",
@@ -589,18 +1295,9 @@ func (r *Router) ServeSwaggerJSON(path string, config OpenAPIConfig) {
 	})
 }
 
-// ServeSwaggerUI serves a Swagger UI HTML page
+// ServeSwaggerUI serves a Swagger UI HTML page, loading its assets from a CDN (see ServeDocsUI)
 func (r *Router) ServeSwaggerUI(path, specURL string) {
-	// Cache the HTML template (generated once, reused for all requests)
-	var htmlCache string
-	var htmlOnce sync.Once
-
-	r.AddRoute(http.MethodGet, path, func(ctx *Context) (any, int, error) {
-		htmlOnce.Do(func() {
-			htmlCache = generateSwaggerUiHtml(specURL)
-		})
-		return ctx.HTML(200, htmlCache)
-	})
+	r.ServeDocsUI(path, specURL, RendererSwaggerUI, nil)
 }
 
 // GenerateOpenAPIFile generates and saves the OpenAPI spec to a JSON file
@@ -619,86 +1316,12 @@ func (r *Router) GenerateOpenAPIFile(filename string, config OpenAPIConfig) erro
 	return nil
 }
 
-// EnableSwagger sets up both Swagger UI and JSON spec endpoints
+// EnableSwagger sets up both the documentation UI and JSON spec endpoints.
+// The UI is selected with config.DocsRenderer, and its assets are served from config.DocsAssets
+// when set (see ServeDocsUI).
 // IMPORTANT: Call this AFTER all routes are registered, as the OpenAPI spec
 // is cached on first request and will not reflect routes added later
 func (r *Router) EnableSwagger(uiPath, jsonPath string, config OpenAPIConfig) {
-	r.ServeSwaggerUI(uiPath, jsonPath)
+	r.ServeDocsUI(uiPath, jsonPath, config.DocsRenderer, config.DocsAssets)
 	r.ServeSwaggerJSON(jsonPath, config)
 }
-
-func generateSwaggerUiHtml(specURL string) string {
-	return fmt.Sprintf(`<!DOCTYPE html>
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>API Documentation</title>
-    <link rel="stylesheet" type="text/css" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.10.5/swagger-ui.css">
-    <style>
-        body { 
-            margin: 0; 
-            padding: 0; 
-        }
-        #swagger-ui {
-            max-width: 100%%;
-        }
-        .loading {
-            display: flex;
-            justify-content: center;
-            align-items: center;
-            height: 100vh;
-            font-family: sans-serif;
-            color: #3b4151;
-        }
-    </style>
-</head>
-<body>
-    <div id="loading" class="loading">
-        <div>Loading API Documentation...</div>
-    </div>
-    <div id="swagger-ui"></div>
-    <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.10.5/swagger-ui-bundle.js" charset="UTF-8"></script>
-    <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.10.5/swagger-ui-standalone-preset.js" charset="UTF-8"></script>
-    <script>
-        window.onload = function() {
-            // Hide loading message
-            document.getElementById('loading').style.display = 'none';
-            
-            // Initialize Swagger UI
-            const ui = SwaggerUIBundle({
-                url: "%s",
-                dom_id: '#swagger-ui',
-                deepLinking: true,
-                presets: [
-                    SwaggerUIBundle.presets.apis,
-                    SwaggerUIStandalonePreset
-                ],
-                plugins: [
-                    SwaggerUIBundle.plugins.DownloadUrl
-                ],
-                layout: "StandaloneLayout",
-                onComplete: function() {
-                    console.log('Swagger UI loaded successfully');
-                },
-                onFailure: function(err) {
-                    console.error('Failed to load Swagger UI:', err);
-                    document.getElementById('swagger-ui').innerHTML = 
-                        '<div style="padding: 20px; color: red;">Failed to load API documentation. Check console for details.</div>';
-                }
-            });
-
-            window.ui = ui;
-        };
-        
-        // Add error handling for script loading
-        window.onerror = function(msg, url, lineNo, columnNo, error) {
-            console.error('Script error:', msg, 'at', url, lineNo + ':' + columnNo);
-            document.getElementById('loading').innerHTML = 
-                '<div style="color: red;">Error loading API documentation. Please check your internet connection.</div>';
-            return false;
-        };
-    </script>
-</body>
-</html>`, specURL)
-}
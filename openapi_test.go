@@ -1,7 +1,13 @@
 package nimbus
 
 import (
+	"encoding/json"
+	"image"
+	"maps"
 	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
 	"testing"
 )
 
@@ -146,6 +152,7 @@ func TestConvertPathParams(t *testing.T) {
 		{"/posts/:postId/comments/:commentId", "/posts/{postId}/comments/{commentId}"},
 		{"/users", "/users"},
 		{"/", "/"},
+		{"/static/*filepath", "/static/{filepath}"},
 	}
 
 	for _, tt := range tests {
@@ -165,6 +172,7 @@ func TestExtractPathParams(t *testing.T) {
 		{"/posts/:postId/comments/:commentId", []string{"postId", "commentId"}},
 		{"/users", []string{}},
 		{"/api/v1/:resource/:id", []string{"resource", "id"}},
+		{"/files/:bucket/*key", []string{"bucket", "key"}},
 	}
 
 	for _, tt := range tests {
@@ -206,7 +214,7 @@ func TestGenerateOperationID(t *testing.T) {
 
 func TestSchemaToOpenAPISchema(t *testing.T) {
 	userSchema := NewSchema(TestAPIUser{})
-	openAPISchema := schemaToOpenAPISchema(userSchema)
+	openAPISchema := schemaToOpenAPISchema(userSchema, map[string]*OpenAPISchema{}, nil)
 
 	if openAPISchema.Type != "object" {
 		t.Errorf("Expected type 'object', got '%s'", openAPISchema.Type)
@@ -259,7 +267,7 @@ func TestSchemaToOpenAPISchema(t *testing.T) {
 
 func TestSchemaToQueryParameters(t *testing.T) {
 	querySchema := NewSchema(TestAPIQuery{})
-	params := schemaToQueryParameters(querySchema)
+	params := schemaToQueryParameters(querySchema, nil)
 
 	if len(params) != 3 {
 		t.Errorf("Expected 3 query parameters, got %d", len(params))
@@ -405,3 +413,431 @@ func TestStaticAndDynamicRoutes(t *testing.T) {
 		}
 	}
 }
+
+func TestSchemaToOpenAPISchema_Nested(t *testing.T) {
+	components := map[string]*OpenAPISchema{}
+	orderSchema := schemaToOpenAPISchema(NewSchema(TestOrder{}), components, nil)
+
+	if ref := orderSchema.Properties["shipping"].Ref; ref != "#/components/schemas/TestAddress" {
+		t.Errorf("expected shipping to reference TestAddress, got %q", ref)
+	}
+	if ref := orderSchema.Properties["billing"].Ref; ref != "#/components/schemas/TestAddress" {
+		t.Errorf("expected billing to reference TestAddress, got %q", ref)
+	}
+	if address := components["TestAddress"]; address == nil || address.Properties["city"].MinLength == nil {
+		t.Errorf("expected TestAddress component with constraints, got %+v", address)
+	}
+
+	items := orderSchema.Properties["items"]
+	if items.Type != "array" || items.Items.Ref != "#/components/schemas/TestLineItem" {
+		t.Errorf("expected items to be an array of TestLineItem, got %+v", items)
+	}
+	if items.MinItems == nil || *items.MinItems != 1 || items.MaxItems == nil || *items.MaxItems != 3 {
+		t.Errorf("expected minItems=1 and maxItems=3, got %+v", items)
+	}
+
+	tags := orderSchema.Properties["tags"]
+	if !tags.UniqueItems || tags.Items.Type != "string" || tags.Items.MinLength == nil || *tags.Items.MinLength != 2 {
+		t.Errorf("expected unique string items with minLength, got %+v", tags)
+	}
+
+	labels := orderSchema.Properties["labels"]
+	if labels.Type != "object" || labels.AdditionalProperties == nil || *labels.AdditionalProperties.MaxLength != 5 {
+		t.Errorf("expected labels map with maxLength values, got %+v", labels)
+	}
+
+	// Recursive types terminate with a self reference
+	categorySchema := schemaToOpenAPISchema(NewSchema(TestCategory{}), components, nil)
+	if ref := categorySchema.Properties["children"].Items.Ref; ref != "#/components/schemas/TestCategory" {
+		t.Errorf("expected children to reference TestCategory, got %q", ref)
+	}
+	if _, ok := components["TestCategory"]; !ok {
+		t.Error("expected TestCategory component")
+	}
+}
+
+func TestGenerateOpenAPI_TypedHandlerSchemas(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(http.MethodPost, "/orders/:id", WithTyped(
+		func(ctx *Context, req *TypedRequest[TestOrderParams, TestOrderBody, TestOrderQuery]) (any, int, error) {
+			return nil, http.StatusCreated, nil
+		}, testOrderParamsValidator, testOrderBodyValidator, testOrderQueryValidator))
+	router.Route(http.MethodPost, "/orders/:id").WithDoc(RouteMetadata{Summary: "Create order"})
+	router.AddRoute(http.MethodPut, "/items/:id", Handle(func(ctx *Context, req *TestUpdateItemRequest) (*TestItem, error) {
+		return nil, nil
+	}))
+
+	// Plain handlers are never called to look for a spec
+	router.AddRoute(http.MethodGet, "/health", func(ctx *Context) (any, int, error) {
+		t.Fatal("plain handler called at registration")
+		return nil, http.StatusOK, nil
+	})
+
+	spec := router.GenerateOpenAPI(OpenAPIConfig{Title: "Orders", Version: "1.0.0"})
+
+	tests := []struct {
+		name       string
+		operation  *OpenAPIOperation
+		parameters map[string]string // name -> in
+		body       string
+	}{
+		{
+			name:       "WithTyped",
+			operation:  spec.Paths["/orders/{id}"].POST,
+			parameters: map[string]string{"id": "path", "limit": "query"},
+			body:       "TestOrderBody",
+		},
+		{
+			name:       "Handle",
+			operation:  spec.Paths["/items/{id}"].PUT,
+			parameters: map[string]string{"id": "path", "dry_run": "query", "X-Tenant-ID": "header", "session": "cookie"},
+			body:       "TestUpdateItemRequest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.operation.Parameters) != len(tt.parameters) {
+				t.Fatalf("expected %d parameters, got %+v", len(tt.parameters), tt.operation.Parameters)
+			}
+			for _, param := range tt.operation.Parameters {
+				if in, ok := tt.parameters[param.Name]; !ok || in != param.In {
+					t.Errorf("unexpected parameter %s in %s", param.Name, param.In)
+				}
+				if param.In == "path" && (param.Schema.Type != "integer" || param.Schema.Minimum == nil) {
+					t.Errorf("expected path parameter documented from its field, got %+v", param.Schema)
+				}
+			}
+
+			ref := "#/components/schemas/" + tt.body
+			if schema := tt.operation.RequestBody.Content["application/json"].Schema; schema.Ref != ref {
+				t.Errorf("expected request body %s, got %+v", ref, schema)
+			}
+		})
+	}
+
+	if op := spec.Paths["/orders/{id}"].POST; op.Summary != "Create order" {
+		t.Errorf("expected WithDoc prose to be kept, got %q", op.Summary)
+	}
+
+	// Only body fields are documented in a Handle request's body
+	body := spec.Components.Schemas["TestUpdateItemRequest"]
+	if len(body.Properties) != 2 || body.Properties["name"] == nil || body.Properties["quantity"] == nil {
+		t.Errorf("expected name and quantity body properties, got %+v", body.Properties)
+	}
+}
+
+type TestAPIPrice struct {
+	Amount   float64          `json:"amount" validate:"gt=0" example:"9.99"`
+	Currency Optional[string] `json:"currency"`
+}
+
+func newTestOpenAPI31Router() *Router {
+	router := NewRouter()
+	handler := func(ctx *Context) (any, int, error) { return nil, http.StatusOK, nil }
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace} {
+		router.AddRoute(method, "/prices/:id", handler)
+	}
+	router.AddRoute(http.MethodPut, "/prices/:id", WithTyped(
+		func(ctx *Context, req *TypedRequest[TestOrderParams, TestAPIPrice, struct{}]) (any, int, error) {
+			return nil, http.StatusOK, nil
+		}, testOrderParamsValidator, NewValidator(&TestAPIPrice{}), nil))
+	router.AddRoute(http.MethodGet, "/legacy/prices", handler)
+	router.Route(http.MethodGet, "/legacy/prices").WithDoc(RouteMetadata{
+		Deprecated:   true,
+		ExternalDocs: &ExternalDocs{URL: "https://example.com/migrate"},
+	})
+	return router
+}
+
+func TestGenerateOpenAPI_31(t *testing.T) {
+	config := OpenAPIConfig{
+		Title:          "Prices",
+		Version:        "1.0.0",
+		OpenAPIVersion: OpenAPIVersion31,
+		ExternalDocs:   &ExternalDocs{URL: "https://example.com/docs"},
+		Webhooks: []Webhook{{
+			Name:     "priceChanged",
+			Method:   http.MethodPost,
+			Metadata: RouteMetadata{RequestSchema: NewSchema(TestAPIPrice{})},
+		}},
+	}
+	spec := newTestOpenAPI31Router().GenerateOpenAPI(config)
+
+	if spec.OpenAPI != "3.1.0" {
+		t.Errorf("expected OpenAPI 3.1.0, got %s", spec.OpenAPI)
+	}
+
+	prices := spec.Paths["/prices/{id}"]
+	if prices.HEAD == nil || prices.OPTIONS == nil || prices.TRACE == nil {
+		t.Errorf("expected HEAD, OPTIONS and TRACE operations, got %+v", prices)
+	}
+	// The PUT route documents id as an integer, so path parameters stay on the operations
+	if len(prices.Parameters) != 0 || len(prices.PUT.Parameters) != 1 {
+		t.Errorf("expected differing path parameters to stay on operations, got %+v", prices.Parameters)
+	}
+	if legacy := spec.Paths["/legacy/prices"].GET; !legacy.Deprecated || legacy.ExternalDocs == nil {
+		t.Errorf("expected deprecated operation with external docs, got %+v", legacy)
+	}
+	if webhook := spec.Webhooks["priceChanged"].POST; webhook == nil || webhook.RequestBody == nil {
+		t.Errorf("expected priceChanged webhook, got %+v", spec.Webhooks)
+	}
+
+	data, err := json.Marshal(spec.Components.Schemas["TestAPIPrice"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var price struct {
+		Properties map[string]map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &price); err != nil {
+		t.Fatal(err)
+	}
+	amount, currency := price.Properties["amount"], price.Properties["currency"]
+	if amount["exclusiveMinimum"] != 0.0 || amount["minimum"] != nil {
+		t.Errorf("expected numeric exclusiveMinimum, got %v", amount)
+	}
+	if examples, ok := amount["examples"].([]any); !ok || len(examples) != 1 || examples[0] != 9.99 || amount["example"] != nil {
+		t.Errorf("expected examples array, got %v", amount)
+	}
+	if types, ok := currency["type"].([]any); !ok || len(types) != 2 || types[1] != "null" || currency["nullable"] != nil {
+		t.Errorf("expected nullable type array, got %v", currency)
+	}
+}
+
+func TestGenerateOpenAPI_31PathParameters(t *testing.T) {
+	router := NewRouter()
+	handler := func(ctx *Context) (any, int, error) { return nil, http.StatusOK, nil }
+	router.AddRoute(http.MethodGet, "/users/:id", handler)
+	router.AddRoute(http.MethodDelete, "/users/:id", handler)
+
+	spec := router.GenerateOpenAPI(OpenAPIConfig{Title: "Users", Version: "1.0.0", OpenAPIVersion: OpenAPIVersion31})
+	users := spec.Paths["/users/{id}"]
+	if len(users.Parameters) != 1 || users.Parameters[0].Name != "id" {
+		t.Errorf("expected shared path-level id parameter, got %+v", users.Parameters)
+	}
+	if len(users.GET.Parameters) != 0 || len(users.DELETE.Parameters) != 0 {
+		t.Errorf("expected path parameters removed from operations, got %+v %+v", users.GET.Parameters, users.DELETE.Parameters)
+	}
+}
+
+func TestGenerateOpenAPI_Deterministic(t *testing.T) {
+	for _, version := range []string{OpenAPIVersion30, OpenAPIVersion31} {
+		t.Run(version, func(t *testing.T) {
+			config := OpenAPIConfig{Title: "Prices", Version: "1.0.0", OpenAPIVersion: version}
+			first, err := json.Marshal(newTestOpenAPI31Router().GenerateOpenAPI(config))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 10; i++ {
+				next, _ := json.Marshal(newTestOpenAPI31Router().GenerateOpenAPI(config))
+				if string(next) != string(first) {
+					t.Fatalf("expected identical specs, got\n%s\n%s", first, next)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateOpenAPI_Security(t *testing.T) {
+	describe := func(name string, scheme *SecurityScheme, scopes ...string) Middleware {
+		return DescribeMiddleware(func(next Handler) Handler {
+			return func(ctx *Context) (any, int, error) { return next(ctx) }
+		}, MiddlewareDoc{SecurityName: name, SecurityScheme: scheme, Scopes: scopes})
+	}
+	apiKey := &SecurityScheme{Type: "apiKey", Name: "X-API-Key", In: "header"}
+	oauth := &SecurityScheme{Type: "oauth2", Flows: &OAuthFlows{ClientCredentials: &OAuthFlow{
+		TokenURL: "https://auth.example.com/token",
+		Scopes:   map[string]string{"orders:write": "Create orders"},
+	}}}
+
+	// Undescribed middleware is never called to look for docs
+	plain := func(next Handler) Handler {
+		if next == nil {
+			t.Fatal("undescribed middleware called with a nil handler")
+		}
+		return next
+	}
+
+	router := NewRouter()
+	router.Use(describe("apiKeyAuth", apiKey), plain)
+	handler := func(ctx *Context) (any, int, error) { return nil, http.StatusOK, nil }
+	router.AddRoute(http.MethodGet, "/orders", handler)
+	router.Group("/admin").AddRoute(http.MethodPost, "/orders", handler, describe("oauth2", oauth, "orders:write"))
+	router.Group("/internal", plain, describe("oauth2", oauth, "orders:write")).AddRoute(http.MethodGet, "/orders", handler)
+
+	spec := router.GenerateOpenAPI(OpenAPIConfig{
+		Title:          "Orders",
+		Version:        "1.0.0",
+		OpenAPIVersion: OpenAPIVersion31,
+		Webhooks:       []Webhook{{Name: "orderShipped", Method: http.MethodPost}},
+	})
+
+	if len(spec.Components.SecuritySchemes) != 2 || spec.Components.SecuritySchemes["oauth2"] != oauth {
+		t.Fatalf("expected apiKeyAuth and oauth2 schemes, got %+v", spec.Components.SecuritySchemes)
+	}
+
+	tests := []struct {
+		name     string
+		op       *OpenAPIOperation
+		expected map[string][]string
+	}{
+		{"router middleware", spec.Paths["/orders"].GET, map[string][]string{"apiKeyAuth": {}}},
+		{"route middleware", spec.Paths["/admin/orders"].POST, map[string][]string{"apiKeyAuth": {}, "oauth2": {"orders:write"}}},
+		{"group middleware", spec.Paths["/internal/orders"].GET, map[string][]string{"apiKeyAuth": {}, "oauth2": {"orders:write"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.op.Security) != 1 || !reflect.DeepEqual(tt.op.Security[0], tt.expected) {
+				t.Errorf("expected security %v, got %v", tt.expected, tt.op.Security)
+			}
+		})
+	}
+
+	// Webhooks are sent by the API, so router middleware doesn't apply to them
+	if webhook := spec.Webhooks["orderShipped"].POST; webhook == nil || len(webhook.Security) != 0 {
+		t.Errorf("expected webhook without security, got %+v", webhook)
+	}
+
+	// Schemes without scopes are serialized as empty arrays, not null
+	data, err := json.Marshal(spec.Paths["/orders"].GET.Security)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `[{"apiKeyAuth":[]}]` {
+		t.Errorf("unexpected security JSON %s", data)
+	}
+}
+
+func TestGenerateOpenAPI_ResponseEnvelopes(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(http.MethodGet, "/users/:id", func(ctx *Context) (any, int, error) { return nil, http.StatusOK, nil })
+	router.Route(http.MethodGet, "/users/:id").WithDoc(RouteMetadata{
+		ResponseSchema: map[int]any{200: nil, 204: nil, 404: nil},
+	})
+	router.AddRoute(http.MethodGet, "/health", func(ctx *Context) (any, int, error) { return nil, http.StatusOK, nil })
+
+	spec := router.GenerateOpenAPI(OpenAPIConfig{Title: "Users", Version: "1.0.0"})
+	schema := func(path, code string) *OpenAPISchema {
+		resp, ok := spec.Paths[path].GET.Responses[code]
+		if !ok {
+			t.Fatalf("expected %s response for %s", code, path)
+		}
+		if resp.Content == nil {
+			return nil
+		}
+		return resp.Content["application/json"].Schema
+	}
+
+	tests := []struct {
+		name string
+		path string
+		code string
+		ref  string // Expected $ref ("" for a success envelope, "-" for no content)
+	}{
+		{"default success", "/health", "200", ""},
+		{"documented success", "/users/{id}", "200", ""},
+		{"no content", "/users/{id}", "204", "-"},
+		{"documented error", "/users/{id}", "404", "#/components/schemas/ErrorResponse"},
+		{"validation error", "/health", "400", "#/components/schemas/ValidationErrorResponse"},
+		{"server error", "/health", "500", "#/components/schemas/ErrorResponse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := schema(tt.path, tt.code)
+			switch tt.ref {
+			case "-":
+				if got != nil {
+					t.Errorf("expected no content, got %+v", got)
+				}
+			case "":
+				if got == nil || got.Properties["success"] == nil || got.Properties["data"] == nil {
+					t.Errorf("expected success envelope, got %+v", got)
+				}
+			default:
+				if got == nil || got.Ref != tt.ref {
+					t.Errorf("expected $ref %s, got %+v", tt.ref, got)
+				}
+			}
+		})
+	}
+
+	errSchema := spec.Components.Schemas["ErrorResponse"]
+	if errSchema == nil || len(errSchema.Properties) != 3 {
+		t.Errorf("expected ErrorResponse component, got %+v", errSchema)
+	}
+	if spec.Components.Schemas["ValidationErrorResponse"] == nil {
+		t.Error("expected ValidationErrorResponse component")
+	}
+}
+
+type TestPage[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+// Point has the same name as image.Point
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+type TestShapes struct {
+	Page  TestPage[TestItem] `json:"page"`
+	Local Point              `json:"local"`
+	Image image.Point        `json:"image"`
+}
+
+func TestGenerateOpenAPI_ComponentNames(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(http.MethodGet, "/shapes", WithTypedResponse(
+		func(ctx *Context, req *TypedRequest[struct{}, struct{}, struct{}]) (*TestShapes, int, error) {
+			return nil, http.StatusOK, nil
+		}, nil, nil, nil))
+	for _, path := range []string{"/a", "/b"} {
+		router.AddRoute(http.MethodPost, path, WithTyped(
+			func(ctx *Context, req *TypedRequest[struct{}, struct {
+				Name string `json:"name" validate:"required"`
+			}, struct{}]) (any, int, error) {
+				return nil, http.StatusOK, nil
+			}, nil, NewValidator(&struct {
+				Name string `json:"name" validate:"required"`
+			}{}), nil))
+	}
+
+	spec := router.GenerateOpenAPI(OpenAPIConfig{Title: "Shapes", Version: "1.0.0"})
+
+	shapes := spec.Components.Schemas["TestShapes"]
+	if shapes == nil {
+		t.Fatalf("expected TestShapes component, got %v", slices.Sorted(maps.Keys(spec.Components.Schemas)))
+	}
+	expected := map[string]string{
+		"page":  "#/components/schemas/TestPage_TestItem",
+		"local": "#/components/schemas/Point",
+		"image": "#/components/schemas/image.Point",
+	}
+	for field, ref := range expected {
+		if got := shapes.Properties[field].Ref; got != ref {
+			t.Errorf("expected %s to reference %s, got %q", field, ref, got)
+		}
+	}
+	if spec.Components.Schemas["Point"].Properties["lat"] == nil || spec.Components.Schemas["image.Point"].Properties["lat"] != nil {
+		t.Errorf("expected Point and image.Point to keep their own schemas")
+	}
+	for name := range spec.Components.Schemas {
+		if !regexp.MustCompile(`^[a-zA-Z0-9._-]+$`).MatchString(name) {
+			t.Errorf("invalid component name %q", name)
+		}
+	}
+
+	// Anonymous request bodies are inlined instead of sharing a component
+	for _, path := range []string{"/a", "/b"} {
+		body := spec.Paths[path].POST.RequestBody.Content["application/json"].Schema
+		if body.Ref != "" || body.Properties["name"] == nil {
+			t.Errorf("expected inline body schema for %s, got %+v", path, body)
+		}
+	}
+	if issues := spec.Lint(); len(issues) != 0 {
+		t.Errorf("expected a valid spec, got %v", issues)
+	}
+}
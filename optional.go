@@ -0,0 +1,128 @@
+package nimbus
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Optional is a request field that records whether it was sent and whether it was null,
+// for PATCH endpoints where a missing field, null and a zero value mean different things.
+// Rules apply to Value only when the field was sent with a non-null value.
+//
+// Example:
+//
+//	type UpdateUserRequest struct {
+//	    Name     nimbus.Optional[string] `json:"name" validate:"minlen=2"`
+//	    Age      nimbus.Optional[int]    `json:"age" validate:"min=0"`
+//	    Nickname nimbus.Optional[string] `json:"nickname"` // null clears the nickname
+//	}
+//
+//	if age, ok := req.Age.Get(); ok {
+//	    user.Age = age
+//	}
+type Optional[T any] struct {
+	Value T
+	Set   bool // Field was present in the request (possibly null)
+	Null  bool // Field was explicitly null
+}
+
+// Some returns an Optional holding v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Set: true}
+}
+
+// Get returns the value and whether it was sent with a non-null value.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Set && !o.Null
+}
+
+// UnmarshalJSON records the field as set, and as null for a JSON null.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		var zero T
+		o.Value, o.Null = zero, true
+		return nil
+	}
+	o.Null = false
+	return json.Unmarshal(data, &o.Value)
+}
+
+// MarshalJSON encodes unset and null values as null.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Set || o.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+func (o Optional[T]) presence() fieldPresence {
+	switch {
+	case !o.Set:
+		return presenceAbsent
+	case o.Null:
+		return presenceNull
+	}
+	return presencePresent
+}
+
+// optionalValue is implemented by Optional[T] for any T
+type optionalValue interface {
+	presence() fieldPresence
+}
+
+var optionalValueType = reflect.TypeOf((*optionalValue)(nil)).Elem()
+
+// isOptionalType reports whether t is an Optional[T]
+func isOptionalType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.Implements(optionalValueType)
+}
+
+// fieldPresence describes whether a field was sent in a request
+type fieldPresence int
+
+const (
+	presenceUnknown fieldPresence = iota // No request document (e.g., validating a struct directly)
+	presenceAbsent
+	presenceNull
+	presencePresent
+)
+
+// lookupPresence finds a field in a request document: the decoded JSON (map[string]any for objects,
+// []any for arrays), or the set of keys sent in a query string or form.
+// Returns presenceUnknown when doc carries no information about the field.
+func lookupPresence(doc any, name string) (fieldPresence, any) {
+	obj, ok := doc.(map[string]any)
+	if !ok {
+		return presenceUnknown, nil
+	}
+
+	value, ok := obj[name]
+	if !ok {
+		// encoding/json matches keys case-insensitively
+		for key, v := range obj {
+			if strings.EqualFold(key, name) {
+				value, ok = v, true
+				break
+			}
+		}
+	}
+
+	switch {
+	case !ok:
+		return presenceAbsent, nil
+	case value == nil:
+		return presenceNull, nil
+	}
+	return presencePresent, value
+}
+
+// elemDocument returns the document for the i-th element of an array document
+func elemDocument(doc any, i int) any {
+	if items, ok := doc.([]any); ok && i < len(items) {
+		return items[i]
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type TestPresenceRequest struct {
+	Name     string  `json:"name" validate:"required"`
+	Age      int     `json:"age" validate:"required,min=0"`
+	Active   bool    `json:"active" validate:"required"`
+	Score    float64 `json:"score" validate:"gt=0"`
+	Nickname *string `json:"nickname" validate:"required,minlen=2"`
+}
+
+func TestValidateJSON_Presence(t *testing.T) {
+	schema := NewSchema(TestPresenceRequest{})
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedTags map[string]string // field -> tag
+	}{
+		{
+			name: "zero values are present",
+			body: `{"name":"Alice","age":0,"active":false,"nickname":"al"}`,
+		},
+		{
+			name:         "missing fields",
+			body:         `{"name":"Alice"}`,
+			expectedTags: map[string]string{"age": "required", "active": "required", "nickname": "required"},
+		},
+		{
+			name:         "null fields",
+			body:         `{"name":"Alice","age":null,"active":true,"nickname":null}`,
+			expectedTags: map[string]string{"age": "required", "nickname": "required"},
+		},
+		{
+			name:         "present zero is validated",
+			body:         `{"name":"Alice","age":-1,"active":true,"score":0,"nickname":"al"}`,
+			expectedTags: map[string]string{"age": "min", "score": "gt"},
+		},
+		{
+			name:         "empty string is still missing",
+			body:         `{"name":"","age":1,"active":true,"nickname":"al"}`,
+			expectedTags: map[string]string{"name": "required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req TestPresenceRequest
+			err := ValidateJSON([]byte(tt.body), &req, schema)
+
+			if len(tt.expectedTags) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			validationErrs, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("expected ValidationErrors, got %v", err)
+			}
+			if len(validationErrs) != len(tt.expectedTags) {
+				t.Fatalf("expected %d errors, got %v", len(tt.expectedTags), validationErrs)
+			}
+			for _, ve := range validationErrs {
+				if tt.expectedTags[ve.Field] != ve.Tag {
+					t.Errorf("unexpected error %s/%s: %s", ve.Field, ve.Tag, ve.Message)
+				}
+			}
+		})
+	}
+}
+
+// Without a request document (Schema.Validate, XML bodies) presence is unknown, so required zero
+// values pass and only nil pointers and empty strings, slices and maps are reported as missing
+func TestValidate_UnknownPresence(t *testing.T) {
+	schema := NewSchema(TestPresenceRequest{})
+
+	t.Run("schema", func(t *testing.T) {
+		errs := schema.Validate(TestPresenceRequest{Name: "Alice", Score: 1})
+		if len(errs) != 1 || errs[0].Field != "nickname" || errs[0].Tag != "required" {
+			t.Errorf("expected only nickname to be required, got %v", errs)
+		}
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		body := `<request><Name>Alice</Name><Score>1</Score></request>`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", MIMEApplicationXML)
+		ctx := &Context{Request: req}
+
+		var target TestPresenceRequest
+		errs, ok := ctx.BindAndValidateBody(&target, schema).(ValidationErrors)
+		if !ok || len(errs) != 1 || errs[0].Field != "nickname" || errs[0].Tag != "required" {
+			t.Errorf("expected only nickname to be required, got %v", errs)
+		}
+	})
+}
+
+func TestOptional_JSON(t *testing.T) {
+	var req struct {
+		A Optional[int]    `json:"a"`
+		B Optional[int]    `json:"b"`
+		C Optional[string] `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":0,"b":null}`), &req); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := req.A.Get(); !ok || v != 0 || !req.A.Set || req.A.Null {
+		t.Errorf("expected a to be set to 0, got %+v", req.A)
+	}
+	if _, ok := req.B.Get(); ok || !req.B.Set || !req.B.Null {
+		t.Errorf("expected b to be null, got %+v", req.B)
+	}
+	if req.C.Set {
+		t.Errorf("expected c to be unset, got %+v", req.C)
+	}
+
+	data, _ := json.Marshal(map[string]any{"a": Some(3), "b": Optional[int]{}})
+	if string(data) != `{"a":3,"b":null}` {
+		t.Errorf("unexpected encoding: %s", data)
+	}
+}
+
+type TestPatchUserRequest struct {
+	Name     Optional[string] `json:"name" validate:"required,minlen=2"`
+	Age      Optional[int]    `json:"age" validate:"min=0,max=150"`
+	Nickname Optional[string] `json:"nickname" validate:"maxlen=10"`
+}
+
+func TestWithTyped_PatchOptional(t *testing.T) {
+	validator := NewValidator(&TestPatchUserRequest{})
+
+	router := NewRouter()
+	router.AddRoute(http.MethodPatch, "/users/me", WithTyped(
+		func(ctx *Context, req *TypedRequest[struct{}, TestPatchUserRequest, struct{}]) (any, int, error) {
+			changes := map[string]any{}
+			if name, ok := req.Body.Name.Get(); ok {
+				changes["name"] = name
+			}
+			if age, ok := req.Body.Age.Get(); ok {
+				changes["age"] = age
+			}
+			if req.Body.Nickname.Null {
+				changes["nickname"] = nil
+			}
+			return changes, 200, nil
+		}, nil, validator, nil))
+
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/users/me", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(`{"name":"Alice","age":0,"nickname":null}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Data["age"] != float64(0) || resp.Data["name"] != "Alice" {
+		t.Errorf("expected zero age to be applied, got %v", resp.Data)
+	}
+	if v, ok := resp.Data["nickname"]; !ok || v != nil {
+		t.Errorf("expected nickname to be cleared, got %v", resp.Data)
+	}
+
+	w = send(`{"name":null,"age":200}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	expected := map[string]string{"name": "required", "age": "max"}
+	details := decodeValidationDetails(t, w)
+	if len(details) != len(expected) {
+		t.Fatalf("expected %d errors, got %+v", len(expected), details)
+	}
+	for _, d := range details {
+		if expected[d.Field] != d.Tag {
+			t.Errorf("unexpected error %s/%s: %s", d.Field, d.Tag, d.Message)
+		}
+	}
+}
+
+func TestValidateQuery_MissingParams(t *testing.T) {
+	type pageQuery struct {
+		Page    int `json:"page" validate:"min=1"`
+		PerPage int `json:"per_page" validate:"required,min=1"`
+	}
+	schema := NewSchema(pageQuery{})
+
+	// A missing optional parameter isn't validated as its zero value
+	var q pageQuery
+	if err := ValidateQuery(url.Values{"per_page": {"0"}}, &q, schema); err == nil {
+		t.Fatal("expected error for per_page=0")
+	} else if errs := err.(ValidationErrors); len(errs) != 1 || errs[0].Field != "per_page" || errs[0].Tag != "min" {
+		t.Errorf("expected only a min error for per_page, got %v", errs)
+	}
+
+	err := ValidateQuery(url.Values{}, &q, schema)
+	if errs, ok := err.(ValidationErrors); !ok || len(errs) != 1 || errs[0].Field != "per_page" || errs[0].Tag != "required" {
+		t.Errorf("expected required error for per_page, got %v", err)
+	}
+}
+
+func TestFieldToOpenAPISchema_Optional(t *testing.T) {
+	openAPISchema := schemaToOpenAPISchema(NewSchema(TestPatchUserRequest{}), map[string]*OpenAPISchema{}, nil)
+
+	age := openAPISchema.Properties["age"]
+	if age.Type != "integer" || !age.Nullable || *age.Maximum != 150 {
+		t.Errorf("expected nullable integer for Optional[int], got %+v", age)
+	}
+	if name := openAPISchema.Properties["name"]; name.Type != "string" || *name.MinLength != 2 {
+		t.Errorf("expected string for Optional[string], got %+v", name)
+	}
+}
@@ -0,0 +1,221 @@
+package nimbus
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// bindQuery binds query parameters to struct fields, returning a document of the parameters that were sent.
+// Fields tagged `header` or `cookie` are skipped (see bindHeaders). Slice fields accept repeated keys and comma-separated values (tag=a&tag=b or tag=a,b), and map
+// fields bind deepObject parameters (filter[status]=active). Fields of embedded structs are
+// bound like the struct's own fields.
+func bindQuery(queryParams url.Values, target any, schema *Schema) (map[string]any, error) {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("target must be a pointer to struct")
+	}
+
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("target must be a pointer to struct")
+	}
+	if v.Type() != schema.structType {
+		return nil, fmt.Errorf("target must be a pointer to %s", schema.structType)
+	}
+
+	// Bind query parameters to struct fields, recording which were sent
+	var bindErrs ValidationErrors
+	doc := make(map[string]any)
+	for _, fieldName := range schema.order {
+		rule := schema.fields[fieldName]
+		if rule.headerName != "" || rule.cookieName != "" {
+			continue // Bound by bindHeaders
+		}
+		fieldValue := v.FieldByIndex(rule.index)
+		if !fieldValue.CanSet() {
+			continue
+		}
+
+		sent, errs := bindQueryField(fieldValue, fieldName, rule, queryParams)
+		if sent {
+			doc[fieldName] = true
+		}
+		bindErrs = append(bindErrs, errs...)
+	}
+
+	if len(bindErrs) > 0 {
+		return doc, bindErrs
+	}
+	return doc, nil
+}
+
+// bindQueryField binds the query parameter for a field, reporting whether it was sent
+func bindQueryField(fieldValue reflect.Value, fieldName string, rule fieldRule, queryParams url.Values) (bool, ValidationErrors) {
+	// Optional[T] fields bind to their value
+	value := fieldValue
+	if rule.optional {
+		value = fieldValue.Field(0)
+	}
+
+	var sent bool
+	var errs ValidationErrors
+	switch queryStyle(value.Type()) {
+	case "form":
+		sent, errs = bindQuerySlice(value, fieldName, queryParams[rule.queryName])
+	case "deepObject":
+		sent, errs = bindQueryMap(value, fieldName, deepObjectValues(queryParams, rule.queryName))
+	default:
+		// Empty parameters are treated as missing
+		if paramValue := queryParams.Get(rule.queryName); paramValue != "" {
+			sent = true
+			if err := setFieldValue(value, paramValue); err != nil {
+				errs = ValidationErrors{typeError(fieldName, paramValue, err)}
+			}
+		}
+	}
+
+	if sent && rule.optional {
+		fieldValue.FieldByName("Set").SetBool(true)
+	}
+	return sent, errs
+}
+
+// queryStyle returns the OpenAPI style of a query parameter of type t: "form" for arrays,
+// "deepObject" for maps, or "" for values parsed from a single string
+func queryStyle(t reflect.Type) string {
+	if isTextType(t) {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return "form"
+	case reflect.Map:
+		return "deepObject"
+	}
+	return ""
+}
+
+// isTextType reports whether values of type t are parsed from a single string: strings, bools,
+// numbers, time.Duration and encoding.TextUnmarshaler types such as time.Time (or pointers to them)
+func isTextType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == durationType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	return t.Kind() == reflect.String || t.Kind() == reflect.Bool || isNumericKind(t.Kind())
+}
+
+// isTextOnlyType reports whether t is parsed from text but isn't a string, bool or number
+// (time.Duration, or TextUnmarshaler types other than time.Time such as net.IP)
+func isTextOnlyType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType || !isTextType(t) {
+		return false
+	}
+	return t == durationType || !(t.Kind() == reflect.String || t.Kind() == reflect.Bool || isNumericKind(t.Kind()))
+}
+
+// bindQuerySlice binds repeated and comma-separated values to a slice field
+func bindQuerySlice(field reflect.Value, fieldName string, values []string) (bool, ValidationErrors) {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	if len(items) == 0 {
+		return false, nil
+	}
+
+	var errs ValidationErrors
+	slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := setFieldValue(slice.Index(i), item); err != nil {
+			errs = append(errs, typeError(fmt.Sprintf("%s[%d]", fieldName, i), item, err))
+		}
+	}
+	field.Set(slice)
+	return true, errs
+}
+
+// bindQueryMap binds deepObject values (keyed by the part in brackets) to a map field.
+// Map values may be slices, which accept repeated and comma-separated values.
+func bindQueryMap(field reflect.Value, fieldName string, values map[string][]string) (bool, ValidationErrors) {
+	if len(values) == 0 {
+		return false, nil
+	}
+
+	// Sort keys so errors are reported in a stable order
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs ValidationErrors
+	m := reflect.MakeMapWithSize(field.Type(), len(values))
+	for _, key := range keys {
+		path := fmt.Sprintf("%s[%s]", fieldName, key)
+
+		mapKey := reflect.New(field.Type().Key()).Elem()
+		if err := setFieldValue(mapKey, key); err != nil {
+			errs = append(errs, typeError(path, key, err))
+			continue
+		}
+
+		elem := reflect.New(field.Type().Elem()).Elem()
+		if queryStyle(elem.Type()) == "form" {
+			sent, elemErrs := bindQuerySlice(elem, path, values[key])
+			errs = append(errs, elemErrs...)
+			if !sent {
+				continue
+			}
+		} else {
+			value := values[key][0]
+			if value == "" {
+				continue
+			}
+			if err := setFieldValue(elem, value); err != nil {
+				errs = append(errs, typeError(path, value, err))
+				continue
+			}
+		}
+		m.SetMapIndex(mapKey, elem)
+	}
+
+	if m.Len() == 0 && len(errs) == 0 {
+		return false, nil
+	}
+	field.Set(m)
+	return true, errs
+}
+
+// deepObjectValues collects the deepObject parameters for name (name[key]=value) by key
+func deepObjectValues(queryParams url.Values, name string) map[string][]string {
+	prefix := name + "["
+	var values map[string][]string
+	for param, paramValues := range queryParams {
+		key, ok := strings.CutPrefix(param, prefix)
+		if !ok || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		key = strings.TrimSuffix(key, "]")
+		if key == "" || strings.ContainsAny(key, "[]") || len(paramValues) == 0 {
+			continue
+		}
+		if values == nil {
+			values = make(map[string][]string)
+		}
+		values[key] = paramValues
+	}
+	return values
+}
@@ -0,0 +1,145 @@
+package nimbus
+
+import (
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type TestPagination struct {
+	Page  int `json:"page" default:"1" validate:"min=1"`
+	Limit int `json:"limit" default:"20" validate:"max=100"`
+}
+
+type TestProductFilters struct {
+	TestPagination
+	Tags     []string            `json:"tags" validate:"maxitems=3,dive,minlen=2"`
+	IDs      []int               `query:"id"`
+	Filter   map[string]string   `json:"filter" validate:"dive,oneof=active|archived|draft"`
+	Ranges   map[string][]int    `json:"range"`
+	Since    time.Time           `json:"since"`
+	Within   time.Duration       `json:"within"`
+	Until    *time.Time          `json:"until"`
+	ClientIP net.IP              `json:"client_ip"`
+	Sort     Optional[string]    `json:"sort"`
+	Labels   map[string][]string `json:"labels"`
+}
+
+func TestValidateQuery_Binding(t *testing.T) {
+	schema := NewSchema(TestProductFilters{})
+
+	query := url.Values{
+		"page":             {"2"},
+		"tags":             {"red,blue", "green"},
+		"id":               {"1", "2,3"},
+		"filter[status]":   {"active"},
+		"filter[owner]":    {"draft"},
+		"filter":           {"ignored"},
+		"range[price]":     {"10,20"},
+		"since":            {"2024-01-02T15:04:05Z"},
+		"within":           {"90m"},
+		"until":            {"2024-02-01T00:00:00Z"},
+		"client_ip":        {"10.0.0.1"},
+		"sort":             {"name"},
+		"labels[env]":      {"prod", "staging"},
+		"labels[a][b]":     {"nested keys are ignored"},
+		"unrelated[field]": {"x"},
+	}
+
+	var filters TestProductFilters
+	if err := ValidateQuery(query, &filters, schema); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	until := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	expected := TestProductFilters{
+		TestPagination: TestPagination{Page: 2, Limit: 20},
+		Tags:           []string{"red", "blue", "green"},
+		IDs:            []int{1, 2, 3},
+		Filter:         map[string]string{"status": "active", "owner": "draft"},
+		Ranges:         map[string][]int{"price": {10, 20}},
+		Since:          time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		Within:         90 * time.Minute,
+		Until:          &until,
+		ClientIP:       net.ParseIP("10.0.0.1"),
+		Sort:           Some("name"),
+		Labels:         map[string][]string{"env": {"prod", "staging"}},
+	}
+	if !reflect.DeepEqual(filters, expected) {
+		t.Errorf("expected %+v, got %+v", expected, filters)
+	}
+}
+
+func TestValidateQuery_BindingErrors(t *testing.T) {
+	schema := NewSchema(TestProductFilters{})
+
+	tests := []struct {
+		name     string
+		query    url.Values
+		expected map[string]string // field -> tag
+	}{
+		{
+			name:     "embedded struct rules",
+			query:    url.Values{"page": {"0"}, "limit": {"500"}},
+			expected: map[string]string{"page": "min", "limit": "max"},
+		},
+		{
+			name:     "slice elements",
+			query:    url.Values{"tags": {"a,bb,cc,dd"}, "id": {"1,x"}},
+			expected: map[string]string{"tags": "maxitems", "tags[0]": "minlen", "id[1]": "type"},
+		},
+		{
+			name:     "deep object values",
+			query:    url.Values{"filter[status]": {"deleted"}, "range[price]": {"10,high"}},
+			expected: map[string]string{"filter[status]": "oneof", "range[price][1]": "type"},
+		},
+		{
+			name:     "time values",
+			query:    url.Values{"since": {"yesterday"}, "within": {"soon"}, "client_ip": {"nope"}},
+			expected: map[string]string{"since": "type", "within": "type", "client_ip": "type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filters TestProductFilters
+			errs, ok := ValidateQuery(tt.query, &filters, schema).(ValidationErrors)
+			if !ok || len(errs) != len(tt.expected) {
+				t.Fatalf("expected %d errors, got %v", len(tt.expected), errs)
+			}
+			for _, err := range errs {
+				if tag, ok := tt.expected[err.Field]; !ok || tag != err.Tag {
+					t.Errorf("unexpected error %s/%s: %s", err.Field, err.Tag, err.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaToQueryParameters_Styles(t *testing.T) {
+	params := make(map[string]OpenAPIParameter)
+	for _, param := range schemaToQueryParameters(NewSchema(TestProductFilters{}), nil) {
+		params[param.Name] = param
+	}
+
+	if _, ok := params["page"]; !ok {
+		t.Error("expected embedded fields to be documented")
+	}
+	if p := params["id"]; p.Style != "form" || p.Explode == nil || !*p.Explode || p.Schema.Type != "array" {
+		t.Errorf("expected exploded form array for id, got %+v", p)
+	}
+	if p := params["filter"]; p.Style != "deepObject" || p.Schema.Type != "object" || p.Schema.AdditionalProperties == nil {
+		t.Errorf("expected deepObject for filter, got %+v", p)
+	}
+	if p := params["since"]; p.Style != "" || p.Schema.Format != "date-time" {
+		t.Errorf("expected date-time for since, got %+v", p.Schema)
+	}
+	if p := params["within"]; p.Schema.Type != "string" {
+		t.Errorf("expected string for duration, got %+v", p.Schema)
+	}
+	if p := params["client_ip"]; p.Style != "" || p.Schema.Type != "string" {
+		t.Errorf("expected string for TextUnmarshaler, got %+v", p)
+	}
+}
@@ -0,0 +1,230 @@
+package nimbus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sync"
+)
+
+// ErrTemplateNotFound is returned when rendering a template name that wasn't loaded.
+var ErrTemplateNotFound = errors.New("template not found")
+
+// Renderer renders a named template with data.
+// Set a renderer with Router.SetRenderer and use it from handlers via Context.Render.
+type Renderer interface {
+	Render(w io.Writer, name string, data any) error
+}
+
+// HTMLRendererConfig configures the built-in html/template renderer.
+type HTMLRendererConfig struct {
+	// FS is the filesystem templates are loaded from (e.g., embed.FS or os.DirFS("templates")).
+	FS fs.FS
+
+	// Layout is the path of the layout template (optional).
+	// When set, every page is rendered by executing the layout, which pulls in page
+	// content with {{block "content" .}} / {{template "content" .}}.
+	Layout string
+
+	// Partials are glob patterns for shared templates available to every page
+	// (e.g., "partials/*.html").
+	Partials []string
+
+	// Pages are glob patterns for page templates (default: "*.html").
+	// Pages are rendered by their path in FS, e.g. ctx.Render(200, "pages/home.html", data).
+	Pages []string
+
+	// Funcs are extra template functions available to all templates.
+	Funcs template.FuncMap
+
+	// Reload re-parses templates from FS on every render.
+	// Enable in development with os.DirFS to see template changes without restarting.
+	Reload bool
+}
+
+// HTMLRenderer is the built-in Renderer backed by html/template.
+// Each page is parsed into its own template set together with the layout and partials,
+// so pages can override the same block names without conflicting.
+type HTMLRenderer struct {
+	config    HTMLRendererConfig
+	templates map[string]*template.Template // page name -> parsed template set (nil when Reload is enabled)
+}
+
+// NewHTMLRenderer creates an html/template renderer and parses all templates up front,
+// so syntax errors are reported at startup rather than on the first request.
+func NewHTMLRenderer(config HTMLRendererConfig) (*HTMLRenderer, error) {
+	if config.FS == nil {
+		return nil, errors.New("HTMLRenderer: FS is required")
+	}
+	if len(config.Pages) == 0 {
+		config.Pages = []string{"*.html"}
+	}
+
+	r := &HTMLRenderer{config: config}
+
+	templates, err := r.parseAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// In reload mode templates are parsed per render; parsing once above still validates them
+	if !config.Reload {
+		r.templates = templates
+	}
+
+	return r, nil
+}
+
+// Render executes the page template name, writing the result to w.
+func (r *HTMLRenderer) Render(w io.Writer, name string, data any) error {
+	var t *template.Template
+
+	if r.config.Reload {
+		if !r.isPage(name) {
+			return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+		}
+		shared, err := r.sharedFiles()
+		if err != nil {
+			return err
+		}
+		t, err = r.parsePage(name, shared)
+		if err != nil {
+			return err
+		}
+	} else {
+		t = r.templates[name]
+		if t == nil {
+			return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+		}
+	}
+
+	entry := path.Base(name)
+	if r.config.Layout != "" {
+		entry = path.Base(r.config.Layout)
+	}
+
+	return t.ExecuteTemplate(w, entry, data)
+}
+
+// parseAll parses every page matched by the Pages patterns.
+func (r *HTMLRenderer) parseAll() (map[string]*template.Template, error) {
+	pages, err := globAll(r.config.FS, r.config.Pages)
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("HTMLRenderer: no pages match %v", r.config.Pages)
+	}
+
+	shared, err := r.sharedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]*template.Template, len(pages))
+	for _, page := range pages {
+		t, err := r.parsePage(page, shared)
+		if err != nil {
+			return nil, err
+		}
+		templates[page] = t
+	}
+
+	return templates, nil
+}
+
+// parsePage parses a single page together with the layout and partials.
+func (r *HTMLRenderer) parsePage(page string, shared []string) (*template.Template, error) {
+	files := make([]string, 0, len(shared)+1)
+	files = append(files, shared...)
+	files = append(files, page) // Parsed last so page blocks override layout defaults
+
+	t, err := template.New(path.Base(page)).Funcs(r.config.Funcs).ParseFS(r.config.FS, files...)
+	if err != nil {
+		return nil, fmt.Errorf("HTMLRenderer: parse %s: %w", page, err)
+	}
+	return t, nil
+}
+
+// sharedFiles returns the layout and partial files included in every page set.
+func (r *HTMLRenderer) sharedFiles() ([]string, error) {
+	partials, err := globAll(r.config.FS, r.config.Partials)
+	if err != nil {
+		return nil, err
+	}
+	if r.config.Layout == "" {
+		return partials, nil
+	}
+	return append([]string{r.config.Layout}, partials...), nil
+}
+
+// isPage reports whether name matches one of the configured page patterns.
+func (r *HTMLRenderer) isPage(name string) bool {
+	for _, pattern := range r.config.Pages {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// globAll expands glob patterns against fsys, preserving order and skipping duplicates.
+func globAll(fsys fs.FS, patterns []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("HTMLRenderer: invalid pattern %q: %w", pattern, err)
+		}
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+	return files, nil
+}
+
+// renderBufferPool reuses buffers for rendering templates before writing the response.
+var renderBufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// Render renders the template name with data using the router's Renderer and writes it as HTML.
+// The template is rendered into a buffer first, so a template error results in a 500 error
+// response instead of a half-written page.
+// Returns (nil, 0, nil) to signal the handler that the response has been written.
+//
+// Example:
+//
+//	func showUser(ctx *nimbus.Context) (any, int, error) {
+//	    return ctx.Render(http.StatusOK, "pages/user.html", user)
+//	}
+func (c *Context) Render(statusCode int, name string, data any) (any, int, error) {
+	if c.router == nil {
+		return nil, http.StatusInternalServerError, errors.New("no renderer configured: call Router.SetRenderer")
+	}
+	renderer := c.router.table.Load().renderer
+	if renderer == nil {
+		return nil, http.StatusInternalServerError, errors.New("no renderer configured: call Router.SetRenderer")
+	}
+
+	buf := renderBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer renderBufferPool.Put(buf)
+
+	if err := renderer.Render(buf, name, data); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("render %s: %w", name, err)
+	}
+
+	return c.Data(statusCode, "text/html; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,154 @@
+package nimbus
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func newTestTemplatesFS() fstest.MapFS {
+	return fstest.MapFS{
+		"layouts/base.html": {Data: []byte(
+			`<html><title>{{block "title" .}}Default{{end}}</title><body>{{template "nav" .}}{{block "content" .}}{{end}}</body></html>`)},
+		"partials/nav.html": {Data: []byte(`{{define "nav"}}<nav>{{upper .User}}</nav>{{end}}`)},
+		"pages/home.html": {Data: []byte(
+			`{{define "title"}}Home{{end}}{{define "content"}}<h1>Welcome {{.User}}</h1>{{end}}`)},
+		"pages/about.html": {Data: []byte(`{{define "content"}}<p>{{.User}} & co</p>{{end}}`)},
+	}
+}
+
+func newTestRenderer(t *testing.T, fsys fstest.MapFS, reload bool) *HTMLRenderer {
+	t.Helper()
+	renderer, err := NewHTMLRenderer(HTMLRendererConfig{
+		FS:       fsys,
+		Layout:   "layouts/base.html",
+		Partials: []string{"partials/*.html"},
+		Pages:    []string{"pages/*.html"},
+		Funcs:    template.FuncMap{"upper": strings.ToUpper},
+		Reload:   reload,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return renderer
+}
+
+func TestHTMLRenderer_LayoutsAndPartials(t *testing.T) {
+	renderer := newTestRenderer(t, newTestTemplatesFS(), false)
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"pages/home.html", `<html><title>Home</title><body><nav>ANN</nav><h1>Welcome Ann</h1></body></html>`},
+		{"pages/about.html", `<html><title>Default</title><body><nav>ANN</nav><p>Ann & co</p></body></html>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := renderer.Render(&sb, tt.name, map[string]string{"User": "Ann"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, sb.String())
+			}
+		})
+	}
+
+	var sb strings.Builder
+	if err := renderer.Render(&sb, "pages/missing.html", nil); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("expected ErrTemplateNotFound, got %v", err)
+	}
+}
+
+func TestHTMLRenderer_Reload(t *testing.T) {
+	fsys := newTestTemplatesFS()
+	renderer := newTestRenderer(t, fsys, true)
+	cached := newTestRenderer(t, fsys, false)
+
+	fsys["pages/home.html"] = &fstest.MapFile{Data: []byte(`{{define "content"}}updated{{end}}`)}
+
+	var sb strings.Builder
+	if err := renderer.Render(&sb, "pages/home.html", map[string]string{"User": "Ann"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(sb.String(), "updated") {
+		t.Errorf("expected reloaded template output, got %q", sb.String())
+	}
+
+	sb.Reset()
+	if err := cached.Render(&sb, "pages/home.html", map[string]string{"User": "Ann"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(sb.String(), "updated") {
+		t.Errorf("expected cached renderer to keep the original template, got %q", sb.String())
+	}
+}
+
+func TestNewHTMLRenderer_Errors(t *testing.T) {
+	if _, err := NewHTMLRenderer(HTMLRendererConfig{}); err == nil {
+		t.Error("expected error when FS is missing")
+	}
+
+	if _, err := NewHTMLRenderer(HTMLRendererConfig{FS: fstest.MapFS{}}); err == nil {
+		t.Error("expected error when no pages match")
+	}
+
+	broken := fstest.MapFS{"index.html": {Data: []byte(`{{if}}`)}}
+	if _, err := NewHTMLRenderer(HTMLRendererConfig{FS: broken}); err == nil {
+		t.Error("expected parse error for invalid template")
+	}
+}
+
+func TestContext_Render(t *testing.T) {
+	router := NewRouter()
+	router.SetRenderer(newTestRenderer(t, newTestTemplatesFS(), false))
+
+	router.AddRoute(http.MethodGet, "/", func(ctx *Context) (any, int, error) {
+		return ctx.Render(http.StatusOK, "pages/home.html", map[string]string{"User": "Ann"})
+	})
+	router.AddRoute(http.MethodGet, "/missing", func(ctx *Context) (any, int, error) {
+		return ctx.Render(http.StatusOK, "pages/missing.html", nil)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+		t.Errorf("unexpected Content-Type %q", got)
+	}
+	if !strings.Contains(w.Body.String(), "<h1>Welcome Ann</h1>") {
+		t.Errorf("unexpected body %q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500 for missing template, got %d", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "<html>") {
+		t.Error("expected no partial page output on render failure")
+	}
+}
+
+func TestContext_Render_NoRenderer(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(http.MethodGet, "/", func(ctx *Context) (any, int, error) {
+		return ctx.Render(http.StatusOK, "index.html", nil)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", w.Code)
+	}
+}
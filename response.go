@@ -4,6 +4,7 @@ package nimbus
 type APIError struct {
 	Code    string
 	Message string
+	Status  int // HTTP status used when the handler doesn't return one (0 = 500)
 }
 
 // Error implements the error interface
@@ -16,6 +17,12 @@ func NewAPIError(code, message string) *APIError {
 	return &APIError{Code: code, Message: message}
 }
 
+// WithStatus sets the HTTP status of the error, for handlers that only return an error (see Handle)
+func (e *APIError) WithStatus(status int) *APIError {
+	e.Status = status
+	return e
+}
+
 // ErrorResponse represents a standard error response
 type ErrorResponse struct {
 	Error   string `json:"error"`
@@ -1,6 +1,7 @@
 package nimbus
 
 import (
+	"errors"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -67,6 +68,7 @@ type Handler func(*Context) (any, int, error)
 
 // TypedRequest holds typed request parameters, body, and query data.
 // Any unused fields will be nil. This consolidates all typed inputs into a single struct.
+// Query structs may also bind request headers and cookies with `header` and `cookie` tags.
 type TypedRequest[P any, B any, Q any] struct {
 	Params *P // Typed path parameters (nil if not configured)
 	Body   *B // Typed request body (nil if not configured)
@@ -88,6 +90,16 @@ type TypedRequest[P any, B any, Q any] struct {
 //	}
 type HandlerFuncTyped[P any, B any, Q any] func(*Context, *TypedRequest[P, B, Q]) (any, int, error)
 
+// HandlerFuncTypedResponse is a HandlerFuncTyped that declares its response type, so the response
+// is documented in the OpenAPI spec (see WithTypedResponse).
+//
+// Example:
+//
+//	func getProduct(ctx *api.Context, req *api.TypedRequest[ProductParams, struct{}, struct{}]) (*Product, int, error) {
+//	    return products[req.Params.ID], 200, nil
+//	}
+type HandlerFuncTypedResponse[P any, B any, Q any, Resp any] func(*Context, *TypedRequest[P, B, Q]) (*Resp, int, error)
+
 // routingTable is an immutable snapshot of routing configuration.
 // Once created and stored in atomic.Pointer, it should never be modified.
 // This enables lock-free concurrent reads with zero contention.
@@ -99,6 +111,7 @@ type routingTable struct {
 	gen           uint64                                      // Generation counter for cache invalidation
 	notFoundRoute *Route                                      // Special synthetic route for 404 handler (also in chains map)
 	chains        map[*Route]Handler                          // Pre-built middleware chains (route -> compiled handler)
+	renderer      Renderer                                    // Template renderer used by Context.Render (nil if not configured)
 }
 
 // Router handles HTTP routing with middleware support.
@@ -109,6 +122,10 @@ type Router struct {
 	table        atomic.Pointer[routingTable] // Immutable routing table (lock-free, type-safe reads)
 	mu           sync.Mutex                   // Only protects writes (route registration, middleware changes)
 	cleanupFuncs []func()                     // Functions to call on Shutdown (e.g., rate limiter cleanup)
+
+	maxMultipartMemory atomic.Int64 // Bytes of multipart bodies held in memory (0 = DefaultMaxMultipartMemory)
+	rules              ruleRegistry // Custom validation rules registered with Router.RegisterRule
+	validateResponses  atomic.Bool  // Typed handler responses are validated (see SetResponseValidation)
 }
 
 // Route represents a single route with its middleware chain.
@@ -117,6 +134,7 @@ type Route struct {
 	handler     Handler
 	middlewares []Middleware
 	metadata    *RouteMetadata
+	spec        *handlerSpec // Request and response types of typed handlers (nil for plain handlers)
 	method      string
 	pattern     string
 }
@@ -187,6 +205,7 @@ func (r *Router) Use(middleware ...Middleware) {
 		gen:           old.gen + 1,       // Increment generation
 		notFoundRoute: old.notFoundRoute, // Share synthetic 404 route
 		chains:        newChains,         // Pre-built chains including 404
+		renderer:      old.renderer,      // Unchanged
 	}
 
 	// Atomic swap - readers get new table immediately, no locks needed
@@ -210,10 +229,15 @@ func (r *Router) AddRoute(method, path string, handler Handler, middleware ...Mi
 	route := &Route{
 		handler:     handler,
 		middlewares: middleware,
+		spec:        lookupHandlerSpec(handler),
 		method:      method,
 		pattern:     path,
 	}
 
+	if route.spec != nil {
+		route.spec.checkCustomRules(&r.rules)
+	}
+
 	// Clone maps for copy-on-write
 	newExactRoutes := copyExactRoutes(old.exactRoutes)
 	newTrees := copyTrees(old.trees)
@@ -252,6 +276,7 @@ func (r *Router) AddRoute(method, path string, handler Handler, middleware ...Mi
 		gen:           old.gen,           // Unchanged (only Use() increments)
 		notFoundRoute: old.notFoundRoute, // Unchanged
 		chains:        newChains,         // Updated with new route's chain
+		renderer:      old.renderer,      // Unchanged
 	}
 
 	r.table.Store(new)
@@ -436,6 +461,7 @@ func (g *Group) AddRoute(method, path string, handler Handler, middleware ...Mid
 // HTTP methods use unique.Handle as map keys for O(1) pointer-based hashing (faster than string hashing).
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	ctx := NewContext(w, req)
+	ctx.router = r
 	defer ctx.Release() // Return context to pool when done
 
 	// Zero-lock read: single atomic load operation (type-safe, no assertion needed)
@@ -483,14 +509,27 @@ func (r *Router) executeHandler(ctx *Context, handler Handler) {
 		return
 	}
 
+	// Headers were already committed (e.g., the handler wrote a response and then
+	// returned an error). Writing again would produce a superfluous WriteHeader
+	// and a corrupted body, so the returned values are dropped.
+	if ctx.Writer.Written() {
+		return
+	}
+
 	// Handle error response
 	if err != nil {
+		// APIErrors keep their status and code when wrapped (e.g., with fmt.Errorf and %w)
+		var apiErr *APIError
+		isAPIErr := errors.As(err, &apiErr)
 		if statusCode == 0 {
 			statusCode = http.StatusInternalServerError
+			if isAPIErr && apiErr.Status != 0 {
+				statusCode = apiErr.Status
+			}
 		}
 
 		// Check if error is a custom error with details
-		if apiErr, ok := err.(*APIError); ok {
+		if isAPIErr {
 			ctx.JSON(statusCode, NewErrorResponse(statusCode, apiErr.Code, apiErr.Message))
 			return
 		}
@@ -550,6 +589,40 @@ func (r *Router) NotFound(handler Handler) {
 		gen:           old.gen,
 		notFoundRoute: newNotFoundRoute, // New synthetic route
 		chains:        newChains,        // Updated chains with new 404
+		renderer:      old.renderer,
+	}
+
+	r.table.Store(new)
+}
+
+// SetRenderer sets the template renderer used by Context.Render.
+// Like Use(), this should be called during startup before serving requests.
+//
+// Example:
+//
+//	renderer, err := nimbus.NewHTMLRenderer(nimbus.HTMLRendererConfig{
+//	    FS:     templates,
+//	    Layout: "layouts/base.html",
+//	    Pages:  []string{"pages/*.html"},
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	router.SetRenderer(renderer)
+func (r *Router) SetRenderer(renderer Renderer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.table.Load()
+
+	new := &routingTable{
+		exactRoutes:   old.exactRoutes,
+		trees:         old.trees,
+		middlewares:   old.middlewares,
+		gen:           old.gen,
+		notFoundRoute: old.notFoundRoute,
+		chains:        old.chains,
+		renderer:      renderer, // Only the renderer changes
 	}
 
 	r.table.Store(new)
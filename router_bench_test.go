@@ -137,7 +137,7 @@ func BenchmarkContext_JSON(b *testing.B) {
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
 		w = httptest.NewRecorder()
-		ctx.Writer = w
+		ctx.writer.reset(w)
 		ctx.JSON(http.StatusOK, data)
 	}
 }
@@ -1,6 +1,8 @@
 package nimbus
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -55,6 +57,31 @@ func TestRouter_NotFound(t *testing.T) {
 	}
 }
 
+func TestRouter_WrappedAPIError(t *testing.T) {
+	router := NewRouter()
+
+	router.AddRoute(http.MethodGet, "/users/:id", func(ctx *Context) (any, int, error) {
+		err := NewAPIError("not_found", "user not found").WithStatus(http.StatusNotFound)
+		return nil, 0, fmt.Errorf("loading user %s: %w", ctx.PathParams["id"], err)
+	})
+
+	req := httptest.NewRequest("GET", "/users/123", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404, got %d", w.Code)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != "not_found" || resp.Message != "user not found" {
+		t.Errorf("Expected the wrapped API error, got %+v", resp)
+	}
+}
+
 func TestRouter_Middleware(t *testing.T) {
 	router := NewRouter()
 
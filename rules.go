@@ -0,0 +1,394 @@
+package nimbus
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	uuidRegex         = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	alphaRegex        = regexp.MustCompile(`^[a-zA-Z]+$`)
+	alphanumericRegex = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+	hostnameLabel     = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+	durationRegex     = regexp.MustCompile(`^P(?:\d+(?:\.\d+)?Y)?(?:\d+(?:\.\d+)?M)?(?:\d+(?:\.\d+)?W)?(?:\d+(?:\.\d+)?D)?(?:T(?:\d+(?:\.\d+)?H)?(?:\d+(?:\.\d+)?M)?(?:\d+(?:\.\d+)?S)?)?$`)
+)
+
+// splitRules splits a validation tag into rules on unescaped commas.
+// A comma can be escaped with a backslash (`pattern=^a\,b$`), and commas inside
+// (), [] and {} don't split, so regex quantifiers like `\d{1,3}` work unescaped.
+// Other backslashes are kept as-is so regex escapes such as `\d` are preserved.
+func splitRules(tag string) []string {
+	var rules []string
+	var current strings.Builder
+	depth := 0
+
+	for i := 0; i < len(tag); i++ {
+		c := tag[i]
+		switch {
+		case c == '\\' && i+1 < len(tag):
+			if tag[i+1] != ',' {
+				current.WriteByte(c) // Keep the backslash for regex escapes
+			}
+			current.WriteByte(tag[i+1])
+			i++
+		case c == '(' || c == '[' || c == '{':
+			depth++
+			current.WriteByte(c)
+		case (c == ')' || c == ']' || c == '}') && depth > 0:
+			depth--
+			current.WriteByte(c)
+		case c == ',' && depth == 0:
+			rules = append(rules, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	if last := strings.TrimSpace(current.String()); last != "" || len(rules) > 0 {
+		rules = append(rules, last)
+	}
+	return rules
+}
+
+// stringFormats are the format rules that take no parameter (except datetime's optional layout)
+var stringFormats = map[string]string{
+	"url":          "a valid URL",
+	"uuid":         "a valid UUID",
+	"ip":           "a valid IP address",
+	"ipv4":         "a valid IPv4 address",
+	"ipv6":         "a valid IPv6 address",
+	"cidr":         "a valid CIDR notation",
+	"hostname":     "a valid hostname",
+	"duration":     "a valid ISO 8601 duration",
+	"alpha":        "alphabetic characters only",
+	"alphanumeric": "alphanumeric characters only",
+	"datetime":     "a valid date/time",
+}
+
+// checkFormat reports whether str is valid for a string format rule
+func checkFormat(format, str, layout string) bool {
+	switch format {
+	case "url":
+		u, err := url.ParseRequestURI(str)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	case "uuid":
+		return uuidRegex.MatchString(str)
+	case "ip":
+		_, err := netip.ParseAddr(str)
+		return err == nil
+	case "ipv4":
+		addr, err := netip.ParseAddr(str)
+		return err == nil && addr.Is4()
+	case "ipv6":
+		addr, err := netip.ParseAddr(str)
+		return err == nil && addr.Is6()
+	case "cidr":
+		_, err := netip.ParsePrefix(str)
+		return err == nil
+	case "hostname":
+		return isHostname(str)
+	case "duration":
+		return str != "P" && !strings.HasSuffix(str, "T") && durationRegex.MatchString(str)
+	case "alpha":
+		return alphaRegex.MatchString(str)
+	case "alphanumeric":
+		return alphanumericRegex.MatchString(str)
+	case "datetime":
+		_, err := time.Parse(layout, str)
+		return err == nil
+	}
+	return true
+}
+
+// isHostname validates an RFC 1123 hostname
+func isHostname(str string) bool {
+	str = strings.TrimSuffix(str, ".")
+	if str == "" || len(str) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(str, ".") {
+		if !hostnameLabel.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleCheck validates a non-empty value against a group of rules
+type ruleCheck func(fieldName string, value any) ValidationErrors
+
+// compileChecks returns the rule groups used by a field's tag, so validation only
+// runs the checks that can fail instead of testing every rule on every value.
+func compileChecks(rule fieldRule) []ruleCheck {
+	var checks []ruleCheck
+
+	if rule.minLength >= 0 || rule.maxLength >= 0 || rule.length >= 0 || rule.email || rule.pattern != nil ||
+		len(rule.enum) > 0 || len(rule.oneOf) > 0 || len(rule.formats) > 0 ||
+		rule.contains != "" || rule.startsWith != "" || rule.endsWith != "" {
+		checks = append(checks, func(fieldName string, value any) ValidationErrors {
+			if str, ok := value.(string); ok {
+				return validateString(fieldName, str, rule)
+			}
+			return nil
+		})
+	}
+
+	if rule.min != nil || rule.max != nil || rule.gt != nil || rule.gte != nil ||
+		rule.lt != nil || rule.lte != nil || len(rule.oneOf) > 0 {
+		checks = append(checks, func(fieldName string, value any) ValidationErrors {
+			if num, ok := toFloat(value); ok {
+				return validateNumber(fieldName, value, num, rule)
+			}
+			return nil
+		})
+	}
+
+	if rule.maxSize > 0 || len(rule.mimeTypes) > 0 {
+		checks = append(checks, func(fieldName string, value any) ValidationErrors {
+			return validateFiles(fieldName, value, rule)
+		})
+	}
+
+	return checks
+}
+
+// validateString applies string rules (length, email, pattern, enum, formats, affixes, oneof)
+func validateString(fieldName, str string, rule fieldRule) ValidationErrors {
+	var errors ValidationErrors
+
+	if rule.minLength >= 0 && len(str) < rule.minLength {
+		errors = append(errors, ValidationError{
+			Field:   fieldName,
+			Value:   str,
+			Tag:     "minlen",
+			Message: fmt.Sprintf("%s must be at least %d characters", fieldName, rule.minLength),
+		})
+	}
+
+	if rule.maxLength >= 0 && len(str) > rule.maxLength {
+		errors = append(errors, ValidationError{
+			Field:   fieldName,
+			Value:   str,
+			Tag:     "maxlen",
+			Message: fmt.Sprintf("%s must be at most %d characters", fieldName, rule.maxLength),
+		})
+	}
+
+	if rule.email && !emailRegex.MatchString(str) {
+		errors = append(errors, ValidationError{
+			Field:   fieldName,
+			Value:   str,
+			Tag:     "email",
+			Message: fmt.Sprintf("%s must be a valid email", fieldName),
+		})
+	}
+
+	if rule.pattern != nil && !rule.pattern.MatchString(str) {
+		errors = append(errors, ValidationError{
+			Field:   fieldName,
+			Value:   str,
+			Tag:     "pattern",
+			Message: fmt.Sprintf("%s format is invalid", fieldName),
+		})
+	}
+
+	if len(rule.enum) > 0 && !containsString(rule.enum, str) {
+		errors = append(errors, ValidationError{
+			Field:   fieldName,
+			Value:   str,
+			Tag:     "enum",
+			Message: fmt.Sprintf("%s must be one of: %s", fieldName, strings.Join(rule.enum, ", ")),
+		})
+	}
+
+	for _, format := range rule.formats {
+		if !checkFormat(format, str, rule.layout) {
+			message := fmt.Sprintf("%s must be %s", fieldName, stringFormats[format])
+			if format == "datetime" {
+				message = fmt.Sprintf("%s must be a date/time in the format %s", fieldName, rule.layout)
+			}
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Value:   str,
+				Tag:     format,
+				Message: message,
+			})
+		}
+	}
+
+	if rule.length >= 0 && len(str) != rule.length {
+		errors = append(errors, ValidationError{
+			Field:   fieldName,
+			Value:   str,
+			Tag:     "len",
+			Message: fmt.Sprintf("%s must be exactly %d characters", fieldName, rule.length),
+		})
+	}
+
+	if rule.contains != "" && !strings.Contains(str, rule.contains) {
+		errors = append(errors, ValidationError{
+			Field:   fieldName,
+			Value:   str,
+			Tag:     "contains",
+			Message: fmt.Sprintf("%s must contain '%s'", fieldName, rule.contains),
+		})
+	}
+
+	if rule.startsWith != "" && !strings.HasPrefix(str, rule.startsWith) {
+		errors = append(errors, ValidationError{
+			Field:   fieldName,
+			Value:   str,
+			Tag:     "startswith",
+			Message: fmt.Sprintf("%s must start with '%s'", fieldName, rule.startsWith),
+		})
+	}
+
+	if rule.endsWith != "" && !strings.HasSuffix(str, rule.endsWith) {
+		errors = append(errors, ValidationError{
+			Field:   fieldName,
+			Value:   str,
+			Tag:     "endswith",
+			Message: fmt.Sprintf("%s must end with '%s'", fieldName, rule.endsWith),
+		})
+	}
+
+	if len(rule.oneOf) > 0 && !containsString(rule.oneOf, str) {
+		errors = append(errors, ValidationError{
+			Field:   fieldName,
+			Value:   str,
+			Tag:     "oneof",
+			Message: fmt.Sprintf("%s must be one of: %s", fieldName, strings.Join(rule.oneOf, ", ")),
+		})
+	}
+
+	return errors
+}
+
+// validateNumber applies numeric rules (min, max, gt, gte, lt, lte, oneof) using float comparison,
+// so fractional values are checked exactly (e.g., 0.5 fails gt=0.5 and min=1).
+func validateNumber(fieldName string, value any, num float64, rule fieldRule) ValidationErrors {
+	var errors ValidationErrors
+
+	check := func(failed bool, tag, format string, bound float64) {
+		if failed {
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Value:   value,
+				Tag:     tag,
+				Message: fmt.Sprintf(format, fieldName, formatNumber(bound)),
+			})
+		}
+	}
+
+	if rule.min != nil {
+		check(num < *rule.min, "min", "%s must be at least %s", *rule.min)
+	}
+	if rule.max != nil {
+		check(num > *rule.max, "max", "%s must be at most %s", *rule.max)
+	}
+	if rule.gt != nil {
+		check(num <= *rule.gt, "gt", "%s must be greater than %s", *rule.gt)
+	}
+	if rule.gte != nil {
+		check(num < *rule.gte, "gte", "%s must be greater than or equal to %s", *rule.gte)
+	}
+	if rule.lt != nil {
+		check(num >= *rule.lt, "lt", "%s must be less than %s", *rule.lt)
+	}
+	if rule.lte != nil {
+		check(num > *rule.lte, "lte", "%s must be less than or equal to %s", *rule.lte)
+	}
+
+	if len(rule.oneOf) > 0 {
+		found := false
+		for _, option := range rule.oneOf {
+			if f, err := strconv.ParseFloat(option, 64); err == nil && f == num {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Value:   value,
+				Tag:     "oneof",
+				Message: fmt.Sprintf("%s must be one of: %s", fieldName, strings.Join(rule.oneOf, ", ")),
+			})
+		}
+	}
+
+	return errors
+}
+
+// toFloat converts numeric values to float64
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+
+	// Other sizes and named numeric types (type Age int)
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// formatNumber formats a rule bound without trailing zeros (18, 0.5)
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// parseFloatRule parses a numeric rule parameter
+func parseFloatRule(param string) *float64 {
+	if val, err := strconv.ParseFloat(param, 64); err == nil {
+		return &val
+	}
+	return nil
+}
+
+// numberRule parses the parameter of a numeric rule such as min=18, panicking if it isn't a number
+func numberRule(name, param string) *float64 {
+	val := parseFloatRule(param)
+	if val == nil {
+		panic(fmt.Sprintf("%s expects a number, got %q", name, param))
+	}
+	return val
+}
+
+// countRule parses the parameter of a length or item count rule such as minlen=2,
+// panicking if it isn't a non-negative integer
+func countRule(name, param string) int {
+	val, err := strconv.Atoi(param)
+	if err != nil || val < 0 {
+		panic(fmt.Sprintf("%s expects a non-negative integer, got %q", name, param))
+	}
+	return val
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
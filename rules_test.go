@@ -0,0 +1,234 @@
+package nimbus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitRules(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected []string
+	}{
+		{"", nil},
+		{"required,minlen=2", []string{"required", "minlen=2"}},
+		{"required, email ", []string{"required", "email"}},
+		{`pattern=^\d{1,3}$,required`, []string{`pattern=^\d{1,3}$`, "required"}},
+		{`pattern=^a\,b$,required`, []string{`pattern=^a,b$`, "required"}},
+		{`pattern=^[a,b]+$`, []string{`pattern=^[a,b]+$`}},
+		{`pattern=^\(x$,len=3`, []string{`pattern=^\(x$`, "len=3"}},
+		{"datetime=Jan 2\\, 2006", []string{"datetime=Jan 2, 2006"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got := splitRules(tt.tag)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("splitRules(%q) = %q, expected %q", tt.tag, got, tt.expected)
+			}
+		})
+	}
+}
+
+type TestRulesRequest struct {
+	Website  string   `json:"website" validate:"url"`
+	ID       string   `json:"id" validate:"uuid"`
+	Address  string   `json:"address" validate:"ip"`
+	IPv4     string   `json:"ipv4" validate:"ipv4"`
+	IPv6     string   `json:"ipv6" validate:"ipv6"`
+	Network  string   `json:"network" validate:"cidr"`
+	Host     string   `json:"host" validate:"hostname"`
+	Date     string   `json:"date" validate:"datetime=2006-01-02"`
+	At       string   `json:"at" validate:"datetime"`
+	Timeout  string   `json:"timeout" validate:"duration"`
+	Code     string   `json:"code" validate:"alpha,len=3"`
+	Handle   string   `json:"handle" validate:"alphanumeric"`
+	Ref      string   `json:"ref" validate:"startswith=ord_,contains=-,endswith=!"`
+	Range    string   `json:"range" validate:"pattern=^\\d{1,3}-\\d{1,3}$"`
+	Price    float64  `json:"price" validate:"gt=0,lte=99.5"`
+	Discount float64  `json:"discount" validate:"gte=0,lt=1"`
+	Ratio    float64  `json:"ratio" validate:"min=0.5"`
+	Priority int      `json:"priority" validate:"oneof=1|2|3"`
+	Color    string   `json:"color" validate:"oneof=red|green"`
+	Pair     []string `json:"pair" validate:"len=2"`
+	Age      TestAge  `json:"age" validate:"min=18,lt=130"`
+	Level    uint8    `json:"level" validate:"max=10"`
+}
+
+type TestAge int
+
+func validRulesRequest() TestRulesRequest {
+	return TestRulesRequest{
+		Website:  "https://example.com/path?q=1",
+		ID:       "123e4567-e89b-12d3-a456-426614174000",
+		Address:  "2001:db8::1",
+		IPv4:     "192.168.0.1",
+		IPv6:     "::1",
+		Network:  "10.0.0.0/8",
+		Host:     "api.example.com",
+		Date:     "2024-02-29",
+		At:       "2024-02-29T12:00:00Z",
+		Timeout:  "PT1H30M",
+		Code:     "abc",
+		Handle:   "user42",
+		Ref:      "ord_12-34!",
+		Range:    "1-100",
+		Price:    99.5,
+		Discount: 0,
+		Ratio:    0.5,
+		Priority: 2,
+		Color:    "red",
+		Pair:     []string{"a", "b"},
+		Age:      30,
+		Level:    10,
+	}
+}
+
+func TestSchema_Validate_Rules(t *testing.T) {
+	schema := NewSchema(TestRulesRequest{})
+
+	if errs := schema.Validate(validRulesRequest()); len(errs) > 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(r *TestRulesRequest)
+		field    string
+		expected string
+	}{
+		{"url without scheme", func(r *TestRulesRequest) { r.Website = "example.com" }, "website", "url"},
+		{"uuid", func(r *TestRulesRequest) { r.ID = "123e4567-e89b-12d3-a456" }, "id", "uuid"},
+		{"ip", func(r *TestRulesRequest) { r.Address = "256.1.1.1" }, "address", "ip"},
+		{"ipv4 given ipv6", func(r *TestRulesRequest) { r.IPv4 = "::1" }, "ipv4", "ipv4"},
+		{"ipv6 given ipv4", func(r *TestRulesRequest) { r.IPv6 = "127.0.0.1" }, "ipv6", "ipv6"},
+		{"cidr", func(r *TestRulesRequest) { r.Network = "10.0.0.0" }, "network", "cidr"},
+		{"hostname", func(r *TestRulesRequest) { r.Host = "-bad-.example.com" }, "host", "hostname"},
+		{"datetime layout", func(r *TestRulesRequest) { r.Date = "2023-02-29" }, "date", "datetime"},
+		{"datetime rfc3339", func(r *TestRulesRequest) { r.At = "2024-02-29 12:00" }, "at", "datetime"},
+		{"duration", func(r *TestRulesRequest) { r.Timeout = "PT" }, "timeout", "duration"},
+		{"duration go syntax", func(r *TestRulesRequest) { r.Timeout = "1h30m" }, "timeout", "duration"},
+		{"alpha", func(r *TestRulesRequest) { r.Code = "ab1" }, "code", "alpha"},
+		{"len", func(r *TestRulesRequest) { r.Code = "abcd" }, "code", "len"},
+		{"alphanumeric", func(r *TestRulesRequest) { r.Handle = "user_42" }, "handle", "alphanumeric"},
+		{"startswith", func(r *TestRulesRequest) { r.Ref = "inv_12-34!" }, "ref", "startswith"},
+		{"contains", func(r *TestRulesRequest) { r.Ref = "ord_1234!" }, "ref", "contains"},
+		{"endswith", func(r *TestRulesRequest) { r.Ref = "ord_12-34" }, "ref", "endswith"},
+		{"pattern with comma", func(r *TestRulesRequest) { r.Range = "1000-1" }, "range", "pattern"},
+		{"gt", func(r *TestRulesRequest) { r.Price = 0 }, "price", "gt"},
+		{"lte", func(r *TestRulesRequest) { r.Price = 99.51 }, "price", "lte"},
+		{"gte", func(r *TestRulesRequest) { r.Discount = -0.1 }, "discount", "gte"},
+		{"lt", func(r *TestRulesRequest) { r.Discount = 1 }, "discount", "lt"},
+		{"float min", func(r *TestRulesRequest) { r.Ratio = 0.49 }, "ratio", "min"},
+		{"oneof number", func(r *TestRulesRequest) { r.Priority = 4 }, "priority", "oneof"},
+		{"oneof string", func(r *TestRulesRequest) { r.Color = "blue" }, "color", "oneof"},
+		{"len items", func(r *TestRulesRequest) { r.Pair = []string{"a"} }, "pair", "len"},
+		{"named number min", func(r *TestRulesRequest) { r.Age = 17 }, "age", "min"},
+		{"named number lt", func(r *TestRulesRequest) { r.Age = 130 }, "age", "lt"},
+		{"uint8 max", func(r *TestRulesRequest) { r.Level = 11 }, "level", "max"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validRulesRequest()
+			tt.modify(&req)
+
+			errs := schema.Validate(req)
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %v", errs)
+			}
+			if errs[0].Field != tt.field || errs[0].Tag != tt.expected {
+				t.Errorf("expected %s/%s, got %s/%s: %s", tt.field, tt.expected, errs[0].Field, errs[0].Tag, errs[0].Message)
+			}
+		})
+	}
+}
+
+func TestParseValidationTag_InvalidParams(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected string
+	}{
+		{"min=abc", `min expects a number, got "abc"`},
+		{"gt", `gt expects a number, got ""`},
+		{"maxlen=ten", `maxlen expects a non-negative integer, got "ten"`},
+		{"minitems=-1", `minitems expects a non-negative integer, got "-1"`},
+		{"pattern=^(a$", "pattern \"^(a$\" is invalid: error parsing regexp: missing closing ): `^(a$`"},
+		{"maxsize=5XB", `maxsize "5XB" is invalid: unknown unit: XB (use B, KB, MB, or GB)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tt.expected {
+					t.Errorf("expected panic %q, got %v", tt.expected, r)
+				}
+			}()
+			parseValidationTag(tt.tag)
+		})
+	}
+}
+
+func TestSchema_Validate_RuleMessages(t *testing.T) {
+	schema := NewSchema(TestRulesRequest{})
+
+	req := validRulesRequest()
+	req.Price = 0
+	req.Ratio = 0.25
+
+	expected := map[string]string{
+		"price": "price must be greater than 0",
+		"ratio": "ratio must be at least 0.5",
+	}
+	for _, err := range schema.Validate(req) {
+		if expected[err.Field] != err.Message {
+			t.Errorf("unexpected message for %s: %q", err.Field, err.Message)
+		}
+	}
+}
+
+func TestFieldToOpenAPISchema_Rules(t *testing.T) {
+	openAPISchema := schemaToOpenAPISchema(NewSchema(TestRulesRequest{}), map[string]*OpenAPISchema{}, nil)
+	props := openAPISchema.Properties
+
+	formats := map[string]string{
+		"website": "uri",
+		"id":      "uuid",
+		"ipv4":    "ipv4",
+		"ipv6":    "ipv6",
+		"host":    "hostname",
+		"date":    "date",
+		"at":      "date-time",
+		"timeout": "duration",
+	}
+	for field, format := range formats {
+		if props[field].Format != format {
+			t.Errorf("expected %s format %q, got %q", field, format, props[field].Format)
+		}
+	}
+
+	if p := props["code"]; p.Pattern != "^[a-zA-Z]+$" || *p.MinLength != 3 || *p.MaxLength != 3 {
+		t.Errorf("unexpected code schema: %+v", p)
+	}
+	if p := props["ref"]; p.Pattern != "^ord_.*!$" {
+		t.Errorf("unexpected ref pattern %q", p.Pattern)
+	}
+	if p := props["range"]; p.Pattern != `^\d{1,3}-\d{1,3}$` {
+		t.Errorf("unexpected range pattern %q", p.Pattern)
+	}
+	if p := props["price"]; *p.Minimum != 0 || !p.ExclusiveMinimum || *p.Maximum != 99.5 || p.ExclusiveMaximum {
+		t.Errorf("unexpected price bounds: %+v", p)
+	}
+	if p := props["discount"]; *p.Minimum != 0 || p.ExclusiveMinimum || *p.Maximum != 1 || !p.ExclusiveMaximum {
+		t.Errorf("unexpected discount bounds: %+v", p)
+	}
+	if p := props["priority"]; !reflect.DeepEqual(p.Enum, []any{1.0, 2.0, 3.0}) {
+		t.Errorf("expected numeric enum, got %v", p.Enum)
+	}
+	if p := props["color"]; !reflect.DeepEqual(p.Enum, []any{"red", "green"}) {
+		t.Errorf("expected string enum, got %v", p.Enum)
+	}
+	if p := props["pair"]; *p.MinItems != 2 || *p.MaxItems != 2 {
+		t.Errorf("expected minItems=maxItems=2, got %+v", p)
+	}
+}
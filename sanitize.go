@@ -0,0 +1,265 @@
+package nimbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// sanitizer transforms a bound field value in place before it is validated
+type sanitizer func(v reflect.Value)
+
+// parseSanitizers parses a `sanitize` tag ("trim,lower", "clamp", "clamp=1|100") for a field of type t.
+// Bare clamp uses the bounds of the field's min/max (or gte/lte) rules.
+// Panics on unknown sanitizers or ones that don't apply to the field's type.
+func parseSanitizers(fieldName, tag string, t reflect.Type, rule fieldRule) []sanitizer {
+	if tag == "" {
+		return nil
+	}
+
+	var sanitizers []sanitizer
+	for _, s := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(s), "=")
+
+		var fn sanitizer
+		switch name {
+		case "trim":
+			fn = stringSanitizer(strings.TrimSpace)
+		case "lower":
+			fn = stringSanitizer(strings.ToLower)
+		case "upper":
+			fn = stringSanitizer(strings.ToUpper)
+		case "collapse_spaces":
+			fn = stringSanitizer(func(s string) string { return strings.Join(strings.Fields(s), " ") })
+		case "clamp":
+			lo, hi := clampBounds(param, rule)
+			if lo == nil && hi == nil {
+				panic(fmt.Sprintf("field %s: clamp needs bounds (clamp=min|max, or min/max rules)", fieldName))
+			}
+			fn = clampSanitizer(lo, hi)
+		default:
+			panic(fmt.Sprintf("field %s: unknown sanitizer %q", fieldName, name))
+		}
+
+		if !sanitizerApplies(name, t) {
+			panic(fmt.Sprintf("field %s: sanitizer %s can't be applied to %s", fieldName, name, t))
+		}
+		sanitizers = append(sanitizers, fn)
+	}
+	return sanitizers
+}
+
+// sanitizerApplies reports whether a sanitizer can transform values of type t
+// (string sanitizers also apply to string slices, element by element)
+func sanitizerApplies(name string, t reflect.Type) bool {
+	if isOptionalType(t) {
+		t = t.Field(0).Type
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if name == "clamp" {
+		return isNumericKind(t.Kind())
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String
+}
+
+// stringSanitizer applies fn to a string, or to each string of a slice
+func stringSanitizer(fn func(string) string) sanitizer {
+	return func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.String:
+			v.SetString(fn(v.String()))
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				v.Index(i).SetString(fn(v.Index(i).String()))
+			}
+		}
+	}
+}
+
+// clampBounds returns the bounds of a clamp sanitizer: "1|100", "|100" and "1|" set them
+// explicitly; without a parameter the field's min/max (or gte/lte) rules are used
+func clampBounds(param string, rule fieldRule) (lo, hi *float64) {
+	if param == "" {
+		lo, hi = rule.min, rule.max
+		if lo == nil {
+			lo = rule.gte
+		}
+		if hi == nil {
+			hi = rule.lte
+		}
+		return lo, hi
+	}
+	min, max, _ := strings.Cut(param, "|")
+	return parseFloatRule(min), parseFloatRule(max)
+}
+
+// clampSanitizer limits a number to [lo, hi] (either bound may be nil)
+func clampSanitizer(lo, hi *float64) sanitizer {
+	return func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n := float64(v.Int())
+			if lo != nil && n < *lo {
+				v.SetInt(int64(math.Ceil(*lo)))
+			} else if hi != nil && n > *hi {
+				v.SetInt(int64(math.Floor(*hi)))
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n := float64(v.Uint())
+			if lo != nil && n < *lo {
+				v.SetUint(uint64(math.Ceil(math.Max(*lo, 0))))
+			} else if hi != nil && n > *hi {
+				v.SetUint(uint64(math.Floor(*hi)))
+			}
+		case reflect.Float32, reflect.Float64:
+			n := v.Float()
+			if lo != nil && n < *lo {
+				v.SetFloat(*lo)
+			} else if hi != nil && n > *hi {
+				v.SetFloat(*hi)
+			}
+		}
+	}
+}
+
+// isNumericKind reports whether k is an integer or floating point kind
+func isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// parseDefault converts a `default` tag to a value of the field's type (Optional[T] and
+// pointers are unwrapped). Scalars use the query parameter syntax; other types are JSON
+// (e.g., `default:"[\"new\"]"`).
+func parseDefault(t reflect.Type, tag string) (reflect.Value, error) {
+	if isOptionalType(t) {
+		t = t.Field(0).Type
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	v := reflect.New(t).Elem()
+	if isTextType(t) {
+		return v, setFieldValue(v, tag)
+	}
+	return v, json.Unmarshal([]byte(tag), v.Addr().Interface())
+}
+
+// applyDefault sets a field to its default value
+func applyDefault(field reflect.Value, rule fieldRule) {
+	def, err := parseDefault(field.Type(), rule.defaultTag)
+	if err != nil {
+		return // Checked when the schema was built
+	}
+
+	switch {
+	case rule.optional:
+		field.Field(0).Set(def)
+		field.FieldByName("Set").SetBool(true)
+	case field.Kind() == reflect.Ptr:
+		ptr := reflect.New(def.Type())
+		ptr.Elem().Set(def)
+		field.Set(ptr)
+	default:
+		field.Set(def)
+	}
+}
+
+// defaultDocValue returns a field's default for OpenAPI docs (nil if it has none)
+func defaultDocValue(t reflect.Type, rule fieldRule) any {
+	if !rule.hasDefault {
+		return nil
+	}
+	def, err := parseDefault(t, rule.defaultTag)
+	if err != nil {
+		return nil
+	}
+	return def.Interface()
+}
+
+// sanitize applies defaults to fields missing from the request and runs sanitizers on the
+// ones that were sent, recursing into nested structs. doc is the request document (see
+// lookupPresence); without one (XML and custom decoders, path parameters), only nil pointers
+// and unset Optional fields are treated as missing, so a zero value that was sent keeps it.
+// Fields given a default are marked as present in doc so they pass required rules.
+func (s *Schema) sanitize(v reflect.Value, doc any) {
+	for _, fieldName := range s.order {
+		rule := s.fields[fieldName]
+		if !rule.hasDefault && len(rule.sanitizers) == 0 && rule.nested == nil && rule.elem == nil {
+			continue
+		}
+
+		field := v.FieldByIndex(rule.index)
+		if !field.CanSet() {
+			continue
+		}
+
+		presence, fieldDoc := lookupPresence(doc, fieldName)
+		value := field
+		if rule.optional {
+			presence = field.Interface().(optionalValue).presence()
+			value = field.Field(0)
+		}
+
+		if rule.hasDefault {
+			missing := presence == presenceAbsent || (presence == presenceUnknown && value.Kind() == reflect.Ptr && value.IsNil())
+			if missing {
+				applyDefault(field, rule)
+				if obj, ok := doc.(map[string]any); ok {
+					obj[fieldName] = true
+				}
+				continue
+			}
+		}
+		if presence == presenceAbsent || presence == presenceNull {
+			continue
+		}
+
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+		for _, fn := range rule.sanitizers {
+			fn(value)
+		}
+		sanitizeValue(value, rule, fieldDoc)
+	}
+}
+
+// sanitizeValue recurses into nested structs and slice elements
+func sanitizeValue(v reflect.Value, rule fieldRule, doc any) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if rule.nested != nil && v.CanSet() {
+			rule.nested.sanitize(v, doc)
+		}
+	case reflect.Slice, reflect.Array:
+		if rule.elem != nil {
+			for i := 0; i < v.Len(); i++ {
+				sanitizeValue(v.Index(i), *rule.elem, elemDocument(doc, i))
+			}
+		}
+	}
+}
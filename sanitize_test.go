@@ -0,0 +1,193 @@
+package nimbus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type TestListQuery struct {
+	Page   int    `json:"page" default:"1" validate:"min=1"`
+	Limit  int    `json:"limit" default:"20" sanitize:"clamp" validate:"min=1,max=100"`
+	Sort   string `json:"sort" default:"created_at" sanitize:"trim,lower" validate:"oneof=created_at|name"`
+	Search string `json:"search" sanitize:"collapse_spaces"`
+	Offset *int   `json:"offset" default:"0"`
+}
+
+func TestValidateQuery_DefaultsAndSanitizers(t *testing.T) {
+	schema := NewSchema(TestListQuery{})
+
+	tests := []struct {
+		name     string
+		query    url.Values
+		expected TestListQuery
+	}{
+		{
+			name:     "defaults",
+			query:    url.Values{},
+			expected: TestListQuery{Page: 1, Limit: 20, Sort: "created_at"},
+		},
+		{
+			name:     "sent values override defaults",
+			query:    url.Values{"page": {"3"}, "limit": {"50"}, "sort": {"name"}},
+			expected: TestListQuery{Page: 3, Limit: 50, Sort: "name"},
+		},
+		{
+			name:     "sanitized before validation",
+			query:    url.Values{"sort": {"  NAME "}, "search": {"  red   running\tshoes "}},
+			expected: TestListQuery{Page: 1, Limit: 20, Sort: "name", Search: "red running shoes"},
+		},
+		{
+			name:     "clamped to min/max rules",
+			query:    url.Values{"limit": {"500"}},
+			expected: TestListQuery{Page: 1, Limit: 100, Sort: "created_at"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query TestListQuery
+			if err := ValidateQuery(tt.query, &query, schema); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query.Offset == nil || *query.Offset != 0 {
+				t.Errorf("expected default offset 0, got %v", query.Offset)
+			}
+			query.Offset = nil
+			if query != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, query)
+			}
+		})
+	}
+
+	// Defaults don't bypass validation of values that were sent
+	var query TestListQuery
+	err := ValidateQuery(url.Values{"page": {"0"}}, &query, schema)
+	if errs, ok := err.(ValidationErrors); !ok || len(errs) != 1 || errs[0].Field != "page" {
+		t.Errorf("expected page error, got %v", err)
+	}
+}
+
+type TestSanitizedAddress struct {
+	City    string `json:"city" sanitize:"trim" validate:"required"`
+	Country string `json:"country" default:"US" sanitize:"upper" validate:"len=2"`
+}
+
+type TestSanitizedSignup struct {
+	Email     string                 `json:"email" sanitize:"trim,lower" validate:"required,email"`
+	Name      Optional[string]       `json:"name" default:"Anonymous" sanitize:"trim"`
+	Tags      []string               `json:"tags" default:"[\"new\"]" sanitize:"trim,lower"`
+	Score     float64                `json:"score" sanitize:"clamp=0|1"`
+	Addresses []TestSanitizedAddress `json:"addresses"`
+}
+
+func TestValidateJSON_DefaultsAndSanitizers(t *testing.T) {
+	schema := NewSchema(TestSanitizedSignup{})
+
+	var signup TestSanitizedSignup
+	body := `{"email":"  Alice@Example.COM ","score":1.5,"addresses":[{"city":" Paris ","country":"fr"},{"city":"Austin"}]}`
+	if err := ValidateJSON([]byte(body), &signup, schema); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if signup.Email != "alice@example.com" || signup.Score != 1 {
+		t.Errorf("expected sanitized email and clamped score, got %+v", signup)
+	}
+	if name, ok := signup.Name.Get(); !ok || name != "Anonymous" {
+		t.Errorf("expected default name, got %+v", signup.Name)
+	}
+	if len(signup.Tags) != 1 || signup.Tags[0] != "new" {
+		t.Errorf("expected default tags, got %v", signup.Tags)
+	}
+	expected := []TestSanitizedAddress{{City: "Paris", Country: "FR"}, {City: "Austin", Country: "US"}}
+	if len(signup.Addresses) != 2 || signup.Addresses[0] != expected[0] || signup.Addresses[1] != expected[1] {
+		t.Errorf("expected nested fields sanitized, got %+v", signup.Addresses)
+	}
+
+	// Explicit nulls keep their value; whitespace-only values fail required after trimming
+	signup = TestSanitizedSignup{}
+	err := ValidateJSON([]byte(`{"email":"   ","name":null,"tags":["  A "]}`), &signup, schema)
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 1 || errs[0].Field != "email" || errs[0].Tag != "required" {
+		t.Errorf("expected required error for email, got %v", err)
+	}
+	if !signup.Name.Null || signup.Tags[0] != "a" {
+		t.Errorf("expected null name and sanitized tags, got %+v", signup)
+	}
+}
+
+// Without a request document, defaults only fill nil pointers, so zero values that were sent are kept
+func TestBindAndValidateBody_DefaultsWithoutPresence(t *testing.T) {
+	body := `<query><Page>0</Page><Limit>5</Limit></query>`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", MIMEApplicationXML)
+	ctx := &Context{Request: req}
+
+	var query TestListQuery
+	errs, ok := ctx.BindAndValidateBody(&query, NewSchema(TestListQuery{})).(ValidationErrors)
+	if !ok || len(errs) != 1 || errs[0].Field != "page" || errs[0].Tag != "min" {
+		t.Errorf("expected the sent page to fail min, got %v", errs)
+	}
+	if query.Page != 0 || query.Limit != 5 || query.Sort != "" {
+		t.Errorf("expected sent and zero values kept, got %+v", query)
+	}
+	if query.Offset == nil || *query.Offset != 0 {
+		t.Errorf("expected default offset, got %v", query.Offset)
+	}
+}
+
+func TestNewSchema_InvalidDefaultsAndSanitizers(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema func()
+	}{
+		{"invalid default", func() {
+			NewSchema(struct {
+				Limit int `json:"limit" default:"many"`
+			}{})
+		}},
+		{"unknown sanitizer", func() {
+			NewSchema(struct {
+				Name string `json:"name" sanitize:"titlecase"`
+			}{})
+		}},
+		{"string sanitizer on number", func() {
+			NewSchema(struct {
+				Age int `json:"age" sanitize:"trim"`
+			}{})
+		}},
+		{"clamp without bounds", func() {
+			NewSchema(struct {
+				Age int `json:"age" sanitize:"clamp"`
+			}{})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected NewSchema to panic")
+				}
+			}()
+			tt.schema()
+		})
+	}
+}
+
+func TestFieldToOpenAPISchema_Default(t *testing.T) {
+	openAPISchema := schemaToOpenAPISchema(NewSchema(TestSanitizedSignup{}), map[string]*OpenAPISchema{}, nil)
+	if def := openAPISchema.Properties["name"].Default; def != "Anonymous" {
+		t.Errorf("expected default for name, got %v", def)
+	}
+	if def, ok := openAPISchema.Properties["tags"].Default.([]string); !ok || len(def) != 1 || def[0] != "new" {
+		t.Errorf("expected default for tags, got %v", openAPISchema.Properties["tags"].Default)
+	}
+
+	params := schemaToQueryParameters(NewSchema(TestListQuery{}), nil)
+	if params[1].Name != "limit" || params[1].Schema.Default != 20 {
+		t.Errorf("expected default for limit parameter, got %+v", params[1].Schema)
+	}
+}
@@ -0,0 +1,243 @@
+package nimbus
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// File serves a file from the local filesystem.
+// Uses http.ServeContent, so Range, If-Modified-Since and HEAD requests are handled automatically.
+// Directories are served via their index.html (if present).
+// Returns (nil, 0, nil) once the response has been written, or a 404 error if the file doesn't exist.
+//
+// Example:
+//
+//	return ctx.File("./reports/latest.pdf")
+func (c *Context) File(filePath string) (any, int, error) {
+	return c.serveFS(os.DirFS(filepath.Dir(filePath)), filepath.Base(filePath), nil)
+}
+
+// FileFS serves a file from an fs.FS (e.g., embed.FS, os.DirFS, fstest.MapFS).
+// Behaves like File, but name must be a valid fs.FS path (slash-separated, no leading slash).
+//
+// Example:
+//
+//	//go:embed assets
+//	var assets embed.FS
+//
+//	return ctx.FileFS(assets, "assets/logo.png")
+func (c *Context) FileFS(fsys fs.FS, name string) (any, int, error) {
+	return c.serveFS(fsys, name, nil)
+}
+
+// Attachment sends content as a file download with the given filename.
+// If content is an io.ReadSeeker (e.g., *os.File, *bytes.Reader), Range requests are supported,
+// and if it also has a Stat method (e.g., *os.File) its modification time is used for If-Modified-Since.
+// Other readers are streamed as-is.
+//
+// Example:
+//
+//	return ctx.Attachment("export.csv", bytes.NewReader(csvData))
+func (c *Context) Attachment(filename string, content io.Reader) (any, int, error) {
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	if rs, ok := content.(io.ReadSeeker); ok {
+		var modTime time.Time
+		if statter, ok := content.(interface{ Stat() (fs.FileInfo, error) }); ok {
+			if info, err := statter.Stat(); err == nil {
+				modTime = info.ModTime()
+			}
+		}
+		http.ServeContent(c.Writer, c.Request, filename, modTime, rs)
+		c.Set(StatusCodeKey, c.Writer.Status()) // Store for logging
+		return nil, 0, nil
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(filename))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Set(StatusCodeKey, http.StatusOK) // Store for logging
+	c.Writer.Header().Set("Content-Type", contentType)
+	c.Writer.WriteHeader(http.StatusOK)
+	if c.Request.Method == http.MethodHead {
+		return nil, 0, nil
+	}
+	_, err := io.Copy(c.Writer, content)
+	return nil, 0, err
+}
+
+// serveFS serves name from fsys with http.ServeContent.
+// If etags is non-nil, files without a modification time (e.g., embed.FS) get a
+// content-hash ETag, cached by name, so clients can revalidate with If-None-Match.
+func (c *Context) serveFS(fsys fs.FS, name string, etags *sync.Map) (any, int, error) {
+	f, info, name, err := openFSFile(fsys, name)
+	if err != nil {
+		return fileError(err)
+	}
+	defer f.Close()
+
+	// http.ServeContent needs to seek for Range requests and content sniffing
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return fileError(err)
+		}
+		content = bytes.NewReader(data)
+	}
+
+	if etags != nil && info.ModTime().IsZero() {
+		etag, err := contentETag(etags, name, content)
+		if err != nil {
+			return fileError(err)
+		}
+		c.Header("ETag", etag)
+	}
+
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), content)
+	c.Set(StatusCodeKey, c.Writer.Status()) // Store for logging
+	return nil, 0, nil
+}
+
+// openFSFile opens name in fsys, resolving directories to their index.html.
+// Returns the resolved name alongside the open file and its info.
+func openFSFile(fsys fs.FS, name string) (fs.File, fs.FileInfo, string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, "", err
+	}
+
+	if !info.IsDir() {
+		return f, info, name, nil
+	}
+
+	// Never list directories; serve the index page instead
+	f.Close()
+	indexName := path.Join(name, "index.html")
+	f, err = fsys.Open(indexName)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	info, err = f.Stat()
+	if err != nil || info.IsDir() {
+		f.Close()
+		return nil, nil, "", fs.ErrNotExist
+	}
+	return f, info, indexName, nil
+}
+
+// contentETag returns a strong ETag derived from the file content.
+// Content is assumed immutable for the lifetime of the cache (true for embed.FS).
+func contentETag(etags *sync.Map, name string, content io.ReadSeeker) (string, error) {
+	if cached, ok := etags.Load(name); ok {
+		return cached.(string), nil
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	etag := `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+	etags.Store(name, etag)
+	return etag, nil
+}
+
+// fileError maps filesystem errors to API errors without leaking paths to the client.
+func fileError(err error) (any, int, error) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrInvalid):
+		return nil, http.StatusNotFound, NewAPIError("not_found", "file not found")
+	case errors.Is(err, fs.ErrPermission):
+		return nil, http.StatusForbidden, NewAPIError("forbidden", "file access denied")
+	default:
+		return nil, http.StatusInternalServerError, NewAPIError("internal_server_error", "failed to read file")
+	}
+}
+
+// cleanStaticPath converts a catch-all path parameter into an fs.FS name.
+// Returns false for anything that could escape the filesystem root
+// (".." elements, backslashes, NUL bytes) so traversal attempts get a 404.
+func cleanStaticPath(name string) (string, bool) {
+	if strings.ContainsAny(name, "\\\x00") {
+		return "", false
+	}
+
+	name = strings.TrimSuffix(name, "/")
+	if name == "" {
+		return ".", true
+	}
+
+	if !fs.ValidPath(name) {
+		return "", false
+	}
+	return name, true
+}
+
+// staticHandler builds the handler used by Router.Static and Group.Static.
+func staticHandler(fsys fs.FS) Handler {
+	// One ETag cache per mounted filesystem
+	etags := &sync.Map{}
+
+	return func(ctx *Context) (any, int, error) {
+		name, ok := cleanStaticPath(ctx.Param("filepath"))
+		if !ok {
+			return nil, http.StatusNotFound, NewAPIError("not_found", "file not found")
+		}
+		return ctx.serveFS(fsys, name, etags)
+	}
+}
+
+// Static serves files from fsys under the given URL prefix.
+// Registers GET and HEAD catch-all routes (prefix + "/*filepath"), so "/assets/css/app.css"
+// serves "css/app.css" from fsys. Directories serve their index.html; listings are never shown.
+// Path traversal ("..") is rejected with a 404.
+//
+// Files without a modification time (embed.FS) get a content-hash ETag, so browsers can
+// revalidate with If-None-Match. Files with a modification time support If-Modified-Since.
+//
+// Example:
+//
+//	//go:embed public
+//	var public embed.FS
+//
+//	assets, _ := fs.Sub(public, "public")
+//	router.Static("/assets", assets)
+//	router.Static("/uploads", os.DirFS("./uploads"), middleware.Auth(validateToken))
+func (r *Router) Static(prefix string, fsys fs.FS, middleware ...Middleware) {
+	pattern := strings.TrimSuffix(prefix, "/") + "/*filepath"
+	handler := staticHandler(fsys)
+	r.AddRoute(http.MethodGet, pattern, handler, middleware...)
+	r.AddRoute(http.MethodHead, pattern, handler, middleware...)
+}
+
+// Static serves files from fsys under the group prefix + prefix.
+// The group middleware is applied. See Router.Static.
+func (g *Group) Static(prefix string, fsys fs.FS, middleware ...Middleware) {
+	pattern := strings.TrimSuffix(prefix, "/") + "/*filepath"
+	handler := staticHandler(fsys)
+	g.AddRoute(http.MethodGet, pattern, handler, middleware...)
+	g.AddRoute(http.MethodHead, pattern, handler, middleware...)
+}
@@ -0,0 +1,247 @@
+package nimbus
+
+import (
+	"bytes"
+	"embed"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+//go:embed testdata/static
+var testStaticFS embed.FS
+
+func newStaticRouter(t *testing.T) *Router {
+	t.Helper()
+	assets, err := fs.Sub(testStaticFS, "testdata/static")
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := NewRouter()
+	router.Static("/assets", assets)
+	return router
+}
+
+func TestStatic_ServesEmbeddedFiles(t *testing.T) {
+	router := newStaticRouter(t)
+
+	tests := []struct {
+		path        string
+		contentType string
+		body        string
+	}{
+		{"/assets/css/app.css", "text/css; charset=utf-8", "body { margin: 0; }\n"},
+		{"/assets/index.html", "text/html; charset=utf-8", "<h1>Home</h1>\n"},
+		{"/assets/", "text/html; charset=utf-8", "<h1>Home</h1>\n"},
+		{"/assets/css/", "", ""}, // directory without index.html
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if tt.body == "" {
+				if w.Code != http.StatusNotFound {
+					t.Errorf("expected status 404, got %d", w.Code)
+				}
+				return
+			}
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.contentType, got)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("expected body %q, got %q", tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestStatic_ETagRevalidation(t *testing.T) {
+	router := newStaticRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/css/app.css", nil))
+
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header for embedded file")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/assets/css/app.css", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("expected status 304, got %d", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("expected empty body for 304, got %q", w.Body.String())
+	}
+}
+
+func TestStatic_BlocksPathTraversal(t *testing.T) {
+	router := NewRouter()
+	router.Static("/files", os.DirFS("testdata/static"))
+
+	paths := []string{
+		"/files/../secret.txt",
+		"/files/css/../../secret.txt",
+		"/files/%2e%2e/secret.txt",
+		"/files/..%5csecret.txt",
+	}
+
+	for _, p := range paths {
+		t.Run(p, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.URL.Path = strings.ReplaceAll(strings.ReplaceAll(p, "%2e", "."), "%5c", "\\")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusNotFound {
+				t.Errorf("expected status 404, got %d", w.Code)
+			}
+			if strings.Contains(w.Body.String(), "secret") {
+				t.Error("response leaked file outside the static root")
+			}
+		})
+	}
+}
+
+func TestStatic_RangeAndHead(t *testing.T) {
+	router := NewRouter()
+	router.Static("/files", fstest.MapFS{
+		"data.txt": {Data: []byte("0123456789")},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/files/data.txt", nil)
+	req.Header.Set("Range", "bytes=2-5")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPartialContent {
+		t.Fatalf("expected status 206, got %d", w.Code)
+	}
+	if w.Body.String() != "2345" {
+		t.Errorf("expected body '2345', got %q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/files/data.txt", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 for HEAD, got %d", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("expected empty body for HEAD, got %q", w.Body.String())
+	}
+}
+
+func TestGroup_Static(t *testing.T) {
+	router := NewRouter()
+	called := false
+	group := router.Group("/v1", func(next Handler) Handler {
+		return func(ctx *Context) (any, int, error) {
+			called = true
+			return next(ctx)
+		}
+	})
+	group.Static("/docs", fstest.MapFS{"readme.txt": {Data: []byte("hello")}})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/docs/readme.txt", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "hello" {
+		t.Errorf("expected 200 'hello', got %d %q", w.Code, w.Body.String())
+	}
+	if !called {
+		t.Error("expected group middleware to run")
+	}
+}
+
+func TestContext_File(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "report.txt")
+	if err := os.WriteFile(filePath, []byte("quarterly numbers"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(filePath, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	router := NewRouter()
+	router.AddRoute(http.MethodGet, "/report", func(ctx *Context) (any, int, error) {
+		return ctx.File(filePath)
+	})
+	router.AddRoute(http.MethodGet, "/missing", func(ctx *Context) (any, int, error) {
+		return ctx.File(filepath.Join(dir, "missing.txt"))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "quarterly numbers" {
+		t.Errorf("expected 200 with file body, got %d %q", w.Code, w.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/report", nil)
+	req.Header.Set("If-Modified-Since", modTime.Add(time.Hour).Format(http.TimeFormat))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("expected status 304, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestContext_Attachment(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(http.MethodGet, "/export", func(ctx *Context) (any, int, error) {
+		return ctx.Attachment("export.csv", bytes.NewReader([]byte("id,name\n1,alice\n")))
+	})
+	router.AddRoute(http.MethodGet, "/stream", func(ctx *Context) (any, int, error) {
+		return ctx.Attachment("log.txt", strings.NewReader("line"))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename=export.csv` {
+		t.Errorf("unexpected Content-Disposition %q", got)
+	}
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/csv") {
+		t.Errorf("expected text/csv Content-Type, got %q", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/export", nil)
+	req.Header.Set("Range", "bytes=0-1")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusPartialContent || w.Body.String() != "id" {
+		t.Errorf("expected 206 'id', got %d %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "line" {
+		t.Errorf("expected 200 'line', got %d %q", w.Code, w.Body.String())
+	}
+}
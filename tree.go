@@ -27,6 +27,7 @@ type node struct {
 	// Children
 	children   []*node // Static and param children
 	paramChild *node   // Single param child (:param)
+	catchAll   *node   // Single catch-all child (*param), always a leaf
 }
 
 // tree represents a radix tree for a specific HTTP method
@@ -95,6 +96,23 @@ func (n *node) insert(path string, route *Route) {
 		segType = static
 	}
 
+	// Handle catch-all nodes (matches the rest of the path, including slashes)
+	if segType == wildcard {
+		if remaining != "" {
+			panic("catch-all parameter '" + segment + "' must be the last segment of the route")
+		}
+		if n.catchAll == nil {
+			n.catchAll = &node{
+				nType:    wildcard,
+				prefix:   segment,
+				paramKey: paramKey,
+				children: make([]*node, 0),
+			}
+		}
+		n.catchAll.route = route
+		return
+	}
+
 	// Handle parameter nodes
 	if segType == param {
 		if n.paramChild == nil {
@@ -221,10 +239,17 @@ func (t *tree) search(path string) (*Route, map[string]string) {
 	return route, params
 }
 
-// search recursively searches for a route in the tree
+// search recursively searches for a route in the tree.
+// Priority is static > param > catch-all; if a more specific branch fails to match
+// deeper in the tree, the search falls back to the less specific ones.
 func (n *node) search(path string, params *map[string]string) *Route {
 	// Handle root path
 	if path == "/" || path == "" {
+		if n.route == nil && n.catchAll != nil {
+			// Catch-all matches an empty remainder (e.g., "/static/" for "/static/*filepath")
+			setParam(params, n.catchAll.paramKey, "")
+			return n.catchAll.route
+		}
 		return n.route
 	}
 
@@ -251,37 +276,69 @@ func (n *node) search(path string, params *map[string]string) *Route {
 
 		// Check if segment starts with child's prefix
 		if strings.HasPrefix(segment, child.prefix) {
+			var route *Route
 			if len(segment) == len(child.prefix) {
 				// Exact match
 				if remaining == "" {
-					return child.route
+					route = child.route
+				} else {
+					route = child.search(remaining, params)
 				}
-				return child.search(remaining, params)
-			} else if len(segment) > len(child.prefix) {
+			} else {
 				// Segment is longer - continue matching
 				newPath := "/" + segment[len(child.prefix):] + remaining
-				return child.search(newPath, params)
+				route = child.search(newPath, params)
+			}
+			if route != nil {
+				return route
 			}
+			// Static children have distinct labels, so no other static child can match
+			break
 		}
 	}
 
 	// Try parameter child
 	if n.paramChild != nil {
-		// Lazy allocate params map only when we actually have parameters (1 bucket = 8 capacity)
-		if *params == nil {
-			*params = make(map[string]string, 8)
-		}
-		(*params)[n.paramChild.paramKey] = segment
+		key := n.paramChild.paramKey
+		previous, hadPrevious := (*params)[key]
+		setParam(params, key, segment)
 
+		var route *Route
 		if remaining == "" {
-			return n.paramChild.route
+			route = n.paramChild.route
+		} else {
+			route = n.paramChild.search(remaining, params)
+		}
+		if route != nil {
+			return route
 		}
-		return n.paramChild.search(remaining, params)
+
+		// Reset the param before backtracking; a param of an enclosing segment may share its name
+		if hadPrevious {
+			(*params)[key] = previous
+		} else {
+			delete(*params, key)
+		}
+	}
+
+	// Try catch-all child (captures the remaining path without the leading slash)
+	if n.catchAll != nil {
+		setParam(params, n.catchAll.paramKey, path)
+		return n.catchAll.route
 	}
 
 	return nil
 }
 
+// setParam stores a path parameter, lazily allocating the params map
+// only when a route actually has parameters (1 bucket = 8 capacity)
+func setParam(params *map[string]string, key, value string) {
+	if *params == nil {
+		*params = make(map[string]string, 8)
+	}
+	(*params)[key] = value
+}
+
 // longestCommonPrefix returns the length of the longest common prefix
 func longestCommonPrefix(a, b string) int {
 	max := len(a)
@@ -323,6 +380,11 @@ func (n *node) collectRoutes(routes *[]*Route) {
 	if n.paramChild != nil {
 		n.paramChild.collectRoutes(routes)
 	}
+
+	// Collect from catch-all child
+	if n.catchAll != nil {
+		n.catchAll.collectRoutes(routes)
+	}
 }
 
 // clone creates a deep copy of the tree for thread-safe copy-on-write semantics.
@@ -368,6 +430,11 @@ func (n *node) clone() *node {
 		newNode.paramChild = n.paramChild.clone()
 	}
 
+	// Deep copy catch-all child
+	if n.catchAll != nil {
+		newNode.catchAll = n.catchAll.clone()
+	}
+
 	return newNode
 }
 
@@ -400,6 +467,7 @@ func (n *node) insertWithCopy(path string, route *Route) *node {
 		prefix:   n.prefix,
 		paramKey: n.paramKey,
 		route:    n.route,
+		catchAll: n.catchAll, // Share catch-all child (replaced below if modified)
 	}
 
 	// Handle root path
@@ -440,6 +508,23 @@ func (n *node) insertWithCopy(path string, route *Route) *node {
 		segType = static
 	}
 
+	// Handle catch-all nodes (matches the rest of the path, including slashes)
+	if segType == wildcard {
+		if remaining != "" {
+			panic("catch-all parameter '" + segment + "' must be the last segment of the route")
+		}
+		newNode.children = n.children     // Share static children (unchanged)
+		newNode.paramChild = n.paramChild // Share param child (unchanged)
+		newNode.catchAll = &node{
+			nType:    wildcard,
+			prefix:   segment,
+			paramKey: paramKey,
+			route:    route,
+			children: make([]*node, 0),
+		}
+		return newNode
+	}
+
 	// Handle parameter nodes
 	if segType == param {
 		newNode.children = n.children // Share static children (unchanged)
@@ -470,6 +555,7 @@ func (n *node) insertWithCopy(path string, route *Route) *node {
 					route:      route,                   // Updated route
 					children:   n.paramChild.children,   // Share children
 					paramChild: n.paramChild.paramChild, // Share param child
+					catchAll:   n.paramChild.catchAll,   // Share catch-all child
 				}
 			} else {
 				newNode.paramChild = n.paramChild.insertWithCopy(remaining, route)
@@ -519,6 +605,7 @@ func (n *node) insertWithCopy(path string, route *Route) *node {
 						route:      route,                   // Updated route
 						children:   matchedChild.children,   // Share children
 						paramChild: matchedChild.paramChild, // Share param child
+						catchAll:   matchedChild.catchAll,   // Share catch-all child
 					}
 				} else {
 					newChildren[matchedIdx] = matchedChild.insertWithCopy(remaining, route)
@@ -547,6 +634,7 @@ func (n *node) insertWithCopy(path string, route *Route) *node {
 				route:      matchedChild.route,      // Keep original route
 				children:   matchedChild.children,   // Share children
 				paramChild: matchedChild.paramChild, // Share param child
+				catchAll:   matchedChild.catchAll,   // Share catch-all child
 			}
 			splitNode.children = append(splitNode.children, updatedChild)
 
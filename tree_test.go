@@ -2,6 +2,7 @@ package nimbus
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -221,6 +222,103 @@ func TestTree_NoMatch(t *testing.T) {
 	}
 }
 
+func TestTree_CatchAll(t *testing.T) {
+	staticRoute := &Route{pattern: "/static/robots.txt"}
+	catchAllRoute := &Route{pattern: "/static/*filepath"}
+	paramRoute := &Route{pattern: "/static/:version/manifest"}
+
+	tests := []struct {
+		path          string
+		expectedRoute *Route
+		expectedParam string
+	}{
+		{"/static/robots.txt", staticRoute, ""},
+		{"/static/css/app.css", catchAllRoute, "css/app.css"},
+		{"/static/app.js", catchAllRoute, "app.js"},
+		{"/static/", catchAllRoute, ""},
+		{"/static/v1/manifest", paramRoute, ""},
+		{"/static/v1/other.js", catchAllRoute, "v1/other.js"}, // param branch fails, falls back
+		{"/other", nil, ""},
+	}
+
+	// Exercise both the mutating insert and the copy-on-write insert
+	trees := map[string]*tree{"insert": newTree(), "insertWithCopy": newTree()}
+	trees["insert"].insert(catchAllRoute.pattern, catchAllRoute)
+	trees["insert"].insert(staticRoute.pattern, staticRoute)
+	trees["insert"].insert(paramRoute.pattern, paramRoute)
+	cow := trees["insertWithCopy"]
+	cow = cow.insertWithCopy(catchAllRoute.pattern, catchAllRoute)
+	cow = cow.insertWithCopy(staticRoute.pattern, staticRoute)
+	cow = cow.insertWithCopy(paramRoute.pattern, paramRoute)
+	trees["insertWithCopy"] = cow
+
+	for name, tr := range trees {
+		for _, tt := range tests {
+			t.Run(name+tt.path, func(t *testing.T) {
+				found, params := tr.search(tt.path)
+				if found != tt.expectedRoute {
+					t.Fatalf("Expected route %v, got %v", tt.expectedRoute, found)
+				}
+				if found == catchAllRoute && params["filepath"] != tt.expectedParam {
+					t.Errorf("Expected filepath=%q, got %q", tt.expectedParam, params["filepath"])
+				}
+				if found == paramRoute && len(params) != 1 {
+					t.Errorf("Expected only the version param, got %v", params)
+				}
+			})
+		}
+	}
+
+	if routes := trees["insertWithCopy"].collectRoutes(); len(routes) != 3 {
+		t.Errorf("Expected 3 collected routes, got %d", len(routes))
+	}
+}
+
+func TestTree_BacktrackingResetsParams(t *testing.T) {
+	commentsRoute := &Route{pattern: "/users/:id/posts/:slug/comments"}
+	userRoute := &Route{pattern: "/users/:id/*rest"}
+	settingsRoute := &Route{pattern: "/orgs/:id/repos/:id/settings"} // Nested groups reusing a param name
+	orgRoute := &Route{pattern: "/orgs/:id/:tab/*rest"}
+
+	tr := newTree()
+	for _, route := range []*Route{commentsRoute, userRoute, settingsRoute, orgRoute} {
+		tr = tr.insertWithCopy(route.pattern, route)
+	}
+
+	tests := []struct {
+		path           string
+		expectedRoute  *Route
+		expectedParams map[string]string
+	}{
+		{"/users/7/posts/hello/comments", commentsRoute, map[string]string{"id": "7", "slug": "hello"}},
+		{"/users/7/posts/hello", userRoute, map[string]string{"id": "7", "rest": "posts/hello"}},
+		{"/orgs/1/repos/2/settings", settingsRoute, map[string]string{"id": "2"}},
+		{"/orgs/1/repos/2/issues", orgRoute, map[string]string{"id": "1", "tab": "repos", "rest": "2/issues"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			found, params := tr.search(tt.path)
+			if found != tt.expectedRoute {
+				t.Fatalf("Expected route %v, got %v", tt.expectedRoute, found)
+			}
+			if !reflect.DeepEqual(params, tt.expectedParams) {
+				t.Errorf("Expected params %v, got %v", tt.expectedParams, params)
+			}
+		})
+	}
+}
+
+func TestTree_CatchAllMustBeLast(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for catch-all parameter that is not the last segment")
+		}
+	}()
+
+	newTree().insert("/files/*path/edit", &Route{})
+}
+
 func TestLongestCommonPrefix(t *testing.T) {
 	tests := []struct {
 		a, b     string
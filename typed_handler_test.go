@@ -265,3 +265,98 @@ func TestWithTyped_NoParameters(t *testing.T) {
 		t.Errorf("expected status 200, got %d", w.Code)
 	}
 }
+
+type TestItemResponse struct {
+	ID   string   `json:"id" validate:"required"`
+	Name string   `json:"name" validate:"required"`
+	Tags []string `json:"tags"`
+}
+
+func TestWithTypedResponse(t *testing.T) {
+	router := NewRouter()
+	router.SetResponseValidation(true)
+
+	handler := func(ctx *Context, req *TypedRequest[TestParams, struct{}, struct{}]) (*TestItemResponse, int, error) {
+		switch req.Params.ID {
+		case "missing":
+			return nil, http.StatusOK, nil
+		case "drift":
+			return &TestItemResponse{ID: req.Params.ID}, http.StatusOK, nil
+		}
+		return &TestItemResponse{ID: req.Params.ID, Name: "Widget"}, http.StatusCreated, nil
+	}
+	router.AddRoute(http.MethodPost, "/items/:id", WithTypedResponse(handler, testParamsValidator, nil, nil))
+
+	tests := []struct {
+		name   string
+		id     string
+		status int
+	}{
+		{"typed response", "1", http.StatusCreated},
+		{"nil response", "missing", http.StatusNoContent},
+		{"response validation", "drift", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/items/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// Without response validation, drifted responses are sent as is
+	router.SetResponseValidation(false)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items/drift", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 without response validation, got %d", w.Code)
+	}
+}
+
+func TestGenerateOpenAPI_TypedResponses(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(http.MethodGet, "/items/:id", WithTypedResponse(
+		func(ctx *Context, req *TypedRequest[TestParams, struct{}, struct{}]) (*TestItemResponse, int, error) {
+			return nil, http.StatusOK, nil
+		}, testParamsValidator, nil, nil))
+	router.AddRoute(http.MethodGet, "/items", Handle(func(ctx *Context, req *struct{}) (*[]TestItemResponse, error) {
+		return nil, nil
+	}))
+	router.AddRoute(http.MethodPost, "/items", WithTypedResponse(
+		func(ctx *Context, req *TypedRequest[struct{}, TestBody, struct{}]) (*TestItemResponse, int, error) {
+			return nil, http.StatusCreated, nil
+		}, nil, testBodyValidator, nil))
+	router.Route(http.MethodPost, "/items").WithDoc(RouteMetadata{
+		ResponseSchema: map[int]any{201: TestItemResponse{ID: "1", Name: "Widget"}, 409: nil},
+	})
+
+	spec := router.GenerateOpenAPI(OpenAPIConfig{Title: "Items", Version: "1.0.0"})
+
+	// Responses are wrapped in the SuccessResponse envelope
+	ref := "#/components/schemas/TestItemResponse"
+	data := func(resp OpenAPIResponse) *OpenAPISchema {
+		return resp.Content["application/json"].Schema.Properties["data"]
+	}
+	if schema := data(spec.Paths["/items/{id}"].GET.Responses["200"]); schema.Ref != ref {
+		t.Errorf("expected response $ref, got %+v", schema)
+	}
+	if schema := data(spec.Paths["/items"].GET.Responses["200"]); schema.Type != "array" || schema.Items.Ref != ref {
+		t.Errorf("expected array of response $refs, got %+v", schema)
+	}
+	post := spec.Paths["/items"].POST.Responses
+	if schema := data(post["201"]); schema.Ref != ref {
+		t.Errorf("expected documented 201 to use the response type, got %+v", schema)
+	}
+	if schema := post["409"].Content["application/json"].Schema; schema.Ref != "#/components/schemas/ErrorResponse" {
+		t.Errorf("expected error response without the response type, got %+v", schema)
+	}
+
+	component := spec.Components.Schemas["TestItemResponse"]
+	if component == nil || len(component.Properties) != 3 || len(component.Required) != 2 {
+		t.Errorf("expected TestItemResponse component, got %+v", component)
+	}
+}
@@ -1,13 +1,19 @@
 package nimbus
 
 import (
-	"encoding/json"
+	"bytes"
+	"encoding"
 	"fmt"
 	"net/url"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/DylanHalstead/nimbus/internal/bytesize"
 )
 
 var (
@@ -19,7 +25,11 @@ type ValidationError struct {
 	Field   string `json:"field"`
 	Value   any    `json:"value"`
 	Tag     string `json:"tag"`
+	Param   string `json:"param,omitempty"` // Rule parameter, e.g. "8" for minlen=8
 	Message string `json:"message"`
+	Offset  int64  `json:"offset,omitempty"` // Byte offset in the request body (JSON decoding errors only)
+
+	messageKey string // The field's `msg` tag, used as the catalog key when localizing
 }
 
 // ValidationErrors is a collection of validation errors
@@ -39,22 +49,82 @@ func (ve ValidationErrors) Error() string {
 type Schema struct {
 	structType reflect.Type
 	fields     map[string]fieldRule
+	order      []string // Field names in declaration order, so errors are reported deterministically
+	hasForm    bool     // At least one field has an explicit `form` tag
+	hasFiles   bool     // At least one field is a *multipart.FileHeader or []*multipart.FileHeader
+	hasHeaders bool     // At least one field is bound from a header or cookie
+
+	structValidators []StructValidator
+	jsonOptions      JSONOptions
 }
 
 type fieldRule struct {
-	jsonTag   string
-	required  bool
-	minLength int
-	maxLength int
-	min       *int
-	max       *int
-	email     bool
-	pattern   *regexp.Regexp
-	enum      []string
-	custom    func(any) error
-}
-
-// NewSchema creates a new validation schema from a struct type
+	jsonTag    string
+	index      []int  // Struct field index, resolved once when the schema is built
+	optional   bool   // Field is an Optional[T]
+	inJSON     bool   // Field has a `json` tag (part of the JSON body)
+	formName   string // Form field name (`form` tag, falling back to the JSON name)
+	queryName  string // Query parameter name (`query` tag, falling back to the JSON name)
+	headerName string // Request header name (`header` tag); the field isn't a query parameter
+	cookieName string // Cookie name (`cookie` tag); the field isn't a query parameter
+	pathName   string // Path parameter name (`path` tag)
+	in         string // Explicit request location (path, query, header or cookie tag; "" otherwise)
+	required   bool
+	minLength  int
+	maxLength  int
+	min        *float64
+	max        *float64
+	email      bool
+	pattern    *regexp.Regexp
+	enum       []string
+	maxSize    int64    // Maximum upload size in bytes for file fields (0 = unlimited)
+	mimeTypes  []string // Allowed (sniffed) content types for file fields
+	minItems   int      // Minimum slice/map length (-1 = unset)
+	maxItems   int      // Maximum slice/map length (-1 = unset)
+	unique     bool     // Slice elements must be unique
+	length     int      // Exact string length or slice/map length (-1 = unset)
+	gt         *float64
+	gte        *float64
+	lt         *float64
+	lte        *float64
+	oneOf      []string // Allowed string or numeric values
+	formats    []string // String formats: url, uuid, ip, ipv4, ipv6, cidr, hostname, datetime, duration, alpha, alphanumeric
+	layout     string   // Time layout for datetime (default time.RFC3339)
+	contains   string
+	startsWith string
+	endsWith   string
+	nested     *Schema    // Schema for struct (or *struct) fields
+	elem       *fieldRule // Rules for slice elements and map values (rules after "dive", or nested struct elements)
+	custom     func(any) error
+
+	conditions  []conditionalRule // required_if, required_unless, required_with, required_without
+	crossFields []crossFieldRule  // eqfield, nefield, gtfield, gtefield, ltfield, ltefield
+	params      map[string]string // Raw rule parameters by tag, reported in ValidationError.Param
+	message     string            // Message override from the `msg` tag
+
+	customRules []customRuleRef // Rules registered with RegisterRule, looked up when validating
+	checks      []ruleCheck     // Rule groups that apply to the field (see compileChecks)
+
+	defaultTag string      // Value from the `default` tag, applied when the field is missing
+	hasDefault bool        // Field has a `default` tag
+	example    any         // Value from the `example` tag, for OpenAPI docs
+	sanitizers []sanitizer // Transformations from the `sanitize` tag, applied before validation
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// schemaCache holds the schemas built for each struct type (reflect.Type -> *Schema).
+// Cached schemas are never modified; NewSchema hands out clones that can be customized.
+var schemaCache sync.Map
+
+// NewSchema creates a new validation schema from a struct type.
+// Nested structs, slices and maps are validated recursively; errors use
+// dotted/indexed field paths such as "items[2].sku" or "labels[env]".
+// Field indexes and rules are resolved once per type and cached, so creating
+// several schemas for the same type is cheap.
 func NewSchema(structPtr any) *Schema {
 	t := reflect.TypeOf(structPtr)
 	if t.Kind() == reflect.Ptr {
@@ -65,31 +135,235 @@ func NewSchema(structPtr any) *Schema {
 		panic("NewSchema expects a struct or pointer to struct")
 	}
 
+	if cached, ok := schemaCache.Load(t); ok {
+		return cached.(*Schema).clone()
+	}
+
+	seen := make(map[reflect.Type]*Schema)
+	schema := newSchema(t, seen)
+	for typ, built := range seen {
+		schemaCache.LoadOrStore(typ, built)
+	}
+	return schema.clone()
+}
+
+// newSchema builds the schema for a struct type. seen holds schemas under construction
+// so recursive types (e.g., a Category with Children []Category) reuse the same schema.
+func newSchema(t reflect.Type, seen map[reflect.Type]*Schema) *Schema {
+	if schema, ok := seen[t]; ok {
+		return schema
+	}
+	if cached, ok := schemaCache.Load(t); ok {
+		return cached.(*Schema)
+	}
+
 	schema := &Schema{
 		structType: t,
 		fields:     make(map[string]fieldRule),
 	}
+	seen[t] = schema
+
+	schema.addFields(t, nil, make(map[string]int), seen)
 
+	schema.checkFieldReferences()
+
+	return schema
+}
+
+// addFields adds the fields of struct type t, found at index within the schema's struct.
+// Fields of embedded structs without a JSON name (e.g., a shared Pagination struct) are
+// promoted as encoding/json does; depths tracks how deeply each field is embedded so
+// outer fields take precedence over promoted ones.
+func (schema *Schema) addFields(t reflect.Type, index []int, depths map[string]int, seen map[reflect.Type]*Schema) {
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		jsonTag := field.Tag.Get("json")
-		validateTag := field.Tag.Get("validate")
+		field.Index = append(append([]int(nil), index...), i)
 
-		if jsonTag == "" || jsonTag == "-" {
+		if isPromotedStruct(field) {
+			schema.addFields(field.Type, field.Index, depths, seen)
 			continue
 		}
 
-		// Parse JSON tag to get field name
-		jsonName := strings.Split(jsonTag, ",")[0]
+		formTag := field.Tag.Get("form")
+		validateTag := field.Tag.Get("validate")
+
+		// Fields without a JSON tag are still validated if they are form, query, path, header or cookie fields
+		jsonName, inJSON := schemaFieldName(field)
+		if jsonName == "" {
+			continue
+		}
+		if depth, exists := depths[jsonName]; exists && depth < len(index) {
+			continue
+		}
 
 		// Parse validation rules
-		rule := parseValidationTag(validateTag)
+		rule := buildFieldRule(field.Type, validateTag, seen)
 		rule.jsonTag = jsonName
+		rule.index = field.Index
+		rule.optional = isOptionalType(field.Type)
+		rule.inJSON = inJSON
+		rule.message = field.Tag.Get("msg")
+		rule.formName = jsonName
+		if formTag != "" && formTag != "-" {
+			rule.formName = formTag
+			schema.hasForm = true
+		}
+		rule.defaultTag, rule.hasDefault = field.Tag.Lookup("default")
+		if rule.hasDefault {
+			if _, err := parseDefault(field.Type, rule.defaultTag); err != nil {
+				panic(fmt.Sprintf("field %s: invalid default %q: %v", jsonName, rule.defaultTag, err))
+			}
+		}
+		if tag, ok := field.Tag.Lookup("example"); ok {
+			example, err := parseDefault(field.Type, tag)
+			if err != nil {
+				panic(fmt.Sprintf("field %s: invalid example %q: %v", jsonName, tag, err))
+			}
+			rule.example = example.Interface()
+		}
+		rule.sanitizers = parseSanitizers(jsonName, field.Tag.Get("sanitize"), field.Type, rule)
+		rule.queryName = jsonName
+		if queryTag := field.Tag.Get("query"); queryTag != "" {
+			rule.queryName = queryTag
+		}
+		rule.pathName = tagName(field, "path")
+		rule.headerName = tagName(field, "header")
+		rule.cookieName = tagName(field, "cookie")
+		if rule.headerName != "" || rule.cookieName != "" {
+			schema.hasHeaders = true
+		}
+		for _, in := range []string{"path", "query", "header", "cookie"} {
+			if tagName(field, in) != "" {
+				rule.in = in
+				break
+			}
+		}
+		if isFileType(field.Type) {
+			schema.hasFiles = true
+		}
 
+		if _, exists := depths[jsonName]; !exists {
+			schema.order = append(schema.order, jsonName)
+		}
+		depths[jsonName] = len(index)
 		schema.fields[jsonName] = rule
 	}
+}
 
-	return schema
+// isPromotedStruct reports whether field is an embedded struct whose fields are promoted
+// into the outer schema (exported, not a pointer, and without a JSON name of its own)
+func isPromotedStruct(field reflect.StructField) bool {
+	if !field.Anonymous || !field.IsExported() || field.Type.Kind() != reflect.Struct || field.Type == timeType {
+		return false
+	}
+	tag := field.Tag.Get("json")
+	name, _, _ := strings.Cut(tag, ",")
+	return tag != "-" && name == ""
+}
+
+// clone returns a copy of a cached schema that can be customized (AddCustomValidator,
+// AddStructValidator, WithJSONOptions) without affecting other schemas for the type.
+// Recursive references to the schema itself point to the copy.
+func (s *Schema) clone() *Schema {
+	c := *s
+	c.fields = make(map[string]fieldRule, len(s.fields))
+	for name, rule := range s.fields {
+		c.fields[name] = rule.rebind(s, &c)
+	}
+	c.structValidators = append([]StructValidator(nil), s.structValidators...)
+	return &c
+}
+
+// subset returns a copy of the schema with only the fields keep reports true for
+// (e.g., the body fields of a Handle request struct)
+func (s *Schema) subset(keep func(fieldRule) bool) *Schema {
+	c := *s
+	c.fields = make(map[string]fieldRule)
+	c.order = nil
+	for _, fieldName := range s.order {
+		if rule := s.fields[fieldName]; keep(rule) {
+			c.fields[fieldName] = rule
+			c.order = append(c.order, fieldName)
+		}
+	}
+	return &c
+}
+
+// rebind returns the rule with references to schema from replaced by to
+func (r fieldRule) rebind(from, to *Schema) fieldRule {
+	if r.nested == from {
+		r.nested = to
+	}
+	if r.elem != nil {
+		elem := r.elem.rebind(from, to)
+		r.elem = &elem
+	}
+	return r
+}
+
+// buildFieldRule parses a validation tag for a field of type t, attaching nested schemas
+// for struct fields and element rules for slices and maps.
+// Rules after "dive" apply to each slice element or map value (e.g., "maxitems=5,dive,minlen=2").
+func buildFieldRule(t reflect.Type, tag string, seen map[reflect.Type]*Schema) fieldRule {
+	fieldRules, elemRules, hasDive := splitDive(splitRules(tag))
+	rule := parseValidationRules(fieldRules)
+
+	// Optional[T] fields are validated as T
+	if isOptionalType(t) {
+		t = t.Field(0).Type
+	}
+
+	if isFileType(t) {
+		return rule
+	}
+
+	base := t
+	if base.Kind() == reflect.Ptr {
+		base = base.Elem()
+	}
+
+	switch base.Kind() {
+	case reflect.Struct:
+		if base != timeType {
+			rule.nested = newSchema(base, seen)
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		elemType := base.Elem()
+		if elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		// Struct elements are always validated; scalar elements only with dive
+		if hasDive || (elemType.Kind() == reflect.Struct && elemType != timeType) {
+			elem := buildElemRule(base.Elem(), elemRules, seen)
+			rule.elem = &elem
+		}
+	}
+
+	return rule
+}
+
+// buildElemRule builds the rule for slice elements or map values from the rules after "dive"
+func buildElemRule(t reflect.Type, rules []string, seen map[reflect.Type]*Schema) fieldRule {
+	tag := ""
+	if len(rules) > 0 {
+		// Re-escape commas so splitRules yields the same rules
+		escaped := make([]string, len(rules))
+		for i, r := range rules {
+			escaped[i] = strings.ReplaceAll(r, ",", "\\,")
+		}
+		tag = strings.Join(escaped, ",")
+	}
+	return buildFieldRule(t, tag, seen)
+}
+
+// splitDive splits validation rules at the first "dive" rule
+func splitDive(rules []string) (fieldRules, elemRules []string, hasDive bool) {
+	for i, r := range rules {
+		if r == "dive" {
+			return rules[:i], rules[i+1:], true
+		}
+	}
+	return rules, nil, false
 }
 
 // AddCustomValidator adds a custom validation function for a specific field (by JSON name)
@@ -103,59 +377,132 @@ func (s *Schema) AddCustomValidator(fieldName string, validator func(any) error)
 	return s
 }
 
-// parseValidationTag parses validation rules from struct tag
+// parseValidationTag parses validation rules from struct tag.
+// Rules are separated by commas; see splitRules for escaping.
 func parseValidationTag(tag string) fieldRule {
+	return parseValidationRules(splitRules(tag))
+}
+
+// parseValidationRules parses a list of validation rules
+func parseValidationRules(rules []string) fieldRule {
 	rule := fieldRule{
 		minLength: -1,
 		maxLength: -1,
+		minItems:  -1,
+		maxItems:  -1,
+		length:    -1,
 	}
 
-	if tag == "" {
-		return rule
-	}
-
-	rules := strings.Split(tag, ",")
 	for _, r := range rules {
-		r = strings.TrimSpace(r)
-
-		switch {
-		case r == "required":
-			rule.required = true
-		case r == "email":
-			rule.email = true
-		case strings.HasPrefix(r, "min="):
-			if val, err := strconv.Atoi(r[4:]); err == nil {
-				rule.min = &val
-			}
-		case strings.HasPrefix(r, "max="):
-			if val, err := strconv.Atoi(r[4:]); err == nil {
-				rule.max = &val
-			}
-		case strings.HasPrefix(r, "minlen="):
-			if val, err := strconv.Atoi(r[7:]); err == nil {
-				rule.minLength = val
-			}
-		case strings.HasPrefix(r, "maxlen="):
-			if val, err := strconv.Atoi(r[7:]); err == nil {
-				rule.maxLength = val
-			}
-		case strings.HasPrefix(r, "pattern="):
-			if regex, err := regexp.Compile(r[8:]); err == nil {
-				rule.pattern = regex
+		name, param, _ := strings.Cut(r, "=")
+		if param != "" {
+			if rule.params == nil {
+				rule.params = make(map[string]string)
 			}
-		case strings.HasPrefix(r, "enum="):
-			enumStr := r[5:]
-			rule.enum = strings.Split(enumStr, "|")
+			rule.params[name] = param
+		}
+
+		if parse, ok := builtinRules[name]; ok {
+			parse(&rule, name, param)
+		} else if name != "" {
+			rule.customRules = append(rule.customRules, customRuleRef{name: name, param: param})
 		}
 	}
 
+	rule.checks = compileChecks(rule)
 	return rule
 }
 
-// Validate validates a struct against the schema
+// builtinRules parse the built-in rules by name. Other names are custom rules (see RegisterRule).
+var builtinRules = map[string]func(rule *fieldRule, name, param string){
+	"required": func(rule *fieldRule, _, _ string) { rule.required = true },
+	"email":    func(rule *fieldRule, _, _ string) { rule.email = true },
+	"min":      func(rule *fieldRule, name, param string) { rule.min = numberRule(name, param) },
+	"max":      func(rule *fieldRule, name, param string) { rule.max = numberRule(name, param) },
+	"gt":       func(rule *fieldRule, name, param string) { rule.gt = numberRule(name, param) },
+	"gte":      func(rule *fieldRule, name, param string) { rule.gte = numberRule(name, param) },
+	"lt":       func(rule *fieldRule, name, param string) { rule.lt = numberRule(name, param) },
+	"lte":      func(rule *fieldRule, name, param string) { rule.lte = numberRule(name, param) },
+	"minlen":   func(rule *fieldRule, name, param string) { rule.minLength = countRule(name, param) },
+	"maxlen":   func(rule *fieldRule, name, param string) { rule.maxLength = countRule(name, param) },
+	"len":      func(rule *fieldRule, name, param string) { rule.length = countRule(name, param) },
+	"pattern": func(rule *fieldRule, _, param string) {
+		regex, err := regexp.Compile(param)
+		if err != nil {
+			panic(fmt.Sprintf("pattern %q is invalid: %v", param, err))
+		}
+		rule.pattern = regex
+	},
+	"enum":       func(rule *fieldRule, _, param string) { rule.enum = strings.Split(param, "|") },
+	"oneof":      func(rule *fieldRule, _, param string) { rule.oneOf = strings.Split(param, "|") },
+	"contains":   func(rule *fieldRule, _, param string) { rule.contains = param },
+	"startswith": func(rule *fieldRule, _, param string) { rule.startsWith = param },
+	"endswith":   func(rule *fieldRule, _, param string) { rule.endsWith = param },
+	"datetime": func(rule *fieldRule, name, param string) {
+		rule.layout = time.RFC3339
+		if param != "" {
+			rule.layout = param
+		}
+		rule.formats = append(rule.formats, name)
+	},
+	"url":          parseFormatRule,
+	"uuid":         parseFormatRule,
+	"ip":           parseFormatRule,
+	"ipv4":         parseFormatRule,
+	"ipv6":         parseFormatRule,
+	"cidr":         parseFormatRule,
+	"hostname":     parseFormatRule,
+	"duration":     parseFormatRule,
+	"alpha":        parseFormatRule,
+	"alphanumeric": parseFormatRule,
+	"maxsize": func(rule *fieldRule, _, param string) {
+		val, err := bytesize.Parse(param)
+		if err != nil {
+			panic(fmt.Sprintf("maxsize %q is invalid: %v", param, err))
+		}
+		rule.maxSize = val
+	},
+	"mimetype":         func(rule *fieldRule, _, param string) { rule.mimeTypes = strings.Split(param, "|") },
+	"minitems":         func(rule *fieldRule, name, param string) { rule.minItems = countRule(name, param) },
+	"maxitems":         func(rule *fieldRule, name, param string) { rule.maxItems = countRule(name, param) },
+	"unique":           func(rule *fieldRule, _, _ string) { rule.unique = true },
+	"required_if":      parseConditionalRules,
+	"required_unless":  parseConditionalRules,
+	"required_with":    parseConditionalRules,
+	"required_without": parseConditionalRules,
+	"eqfield":          parseCrossFieldRule,
+	"nefield":          parseCrossFieldRule,
+	"gtfield":          parseCrossFieldRule,
+	"gtefield":         parseCrossFieldRule,
+	"ltfield":          parseCrossFieldRule,
+	"ltefield":         parseCrossFieldRule,
+	"dive":             func(*fieldRule, string, string) {}, // Handled by splitDive
+}
+
+func parseFormatRule(rule *fieldRule, name, _ string) {
+	rule.formats = append(rule.formats, name)
+}
+
+func parseConditionalRules(rule *fieldRule, name, param string) {
+	rule.conditions = append(rule.conditions, parseConditionalRule(name, param))
+}
+
+func parseCrossFieldRule(rule *fieldRule, name, param string) {
+	rule.crossFields = append(rule.crossFields, crossFieldRule{tag: name, field: param})
+}
+
+// Validate validates a struct against the schema.
+// There's no request document to tell a missing field from a zero value, so `required` only fails
+// for nil pointers and empty strings, slices and maps: use a pointer or Optional[T] for fields whose
+// zero value must still be reported as missing.
 func (s *Schema) Validate(data any) ValidationErrors {
-	var errors ValidationErrors
+	return s.validate(data, nil, nil)
+}
 
+// validate validates data against the schema. doc is the decoded request document used to tell
+// missing and null fields from zero values (see lookupPresence); nil when unavailable.
+// rules are the router's custom rules (nil for global rules only).
+func (s *Schema) validate(data any, doc any, rules *ruleRegistry) ValidationErrors {
 	v := reflect.ValueOf(data)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
@@ -167,126 +514,228 @@ func (s *Schema) Validate(data any) ValidationErrors {
 			Message: "expected struct type",
 		}}
 	}
+	if v.Type() != s.structType {
+		return ValidationErrors{{
+			Field:   "root",
+			Message: fmt.Sprintf("expected %s, got %s", s.structType, v.Type()),
+		}}
+	}
 
-	// Check each field in the schema
-	for fieldName, rule := range s.fields {
-		fieldValue := v.FieldByName(getStructFieldName(s.structType, fieldName))
+	return s.validateStruct("", v, doc, rules)
+}
+
+// validateStruct validates a struct value, prefixing field names with path (for nested structs)
+func (s *Schema) validateStruct(path string, v reflect.Value, doc any, rules *ruleRegistry) ValidationErrors {
+	var errors ValidationErrors
+
+	// Check each field in the schema, in declaration order
+	for _, fieldName := range s.order {
+		rule := s.fields[fieldName]
+		fieldPath := fieldName
+		if path != "" {
+			fieldPath = path + "." + fieldName
+		}
 
-		if !fieldValue.IsValid() {
+		fieldValue := v.FieldByIndex(rule.index)
+
+		presence, fieldDoc := lookupPresence(doc, fieldName)
+		if rule.optional {
+			presence = fieldValue.Interface().(optionalValue).presence()
+			fieldValue = fieldValue.Field(0)
+		}
+
+		// Missing and null fields are only checked for presence, so a zero value
+		// that was actually sent (e.g., "age": 0) is still validated
+		if presence == presenceAbsent || presence == presenceNull {
 			if rule.required {
-				errors = append(errors, ValidationError{
-					Field:   fieldName,
+				message := fmt.Sprintf("%s is required", fieldPath)
+				if presence == presenceNull {
+					message = fmt.Sprintf("%s must not be null", fieldPath)
+				}
+				errors = append(errors, rule.annotate(fieldPath, ValidationErrors{{
+					Field:   fieldPath,
 					Tag:     "required",
-					Message: fmt.Sprintf("%s is required", fieldName),
-				})
+					Message: message,
+				}})...)
+			} else if cond, ok := s.conditionallyRequired(v, rule); ok {
+				errors = append(errors, rule.annotate(fieldPath, ValidationErrors{{
+					Field:   fieldPath,
+					Tag:     cond.tag,
+					Message: conditionalMessage(fieldPath, cond),
+				}})...)
 			}
 			continue
 		}
 
+		// Conditionally required fields report the condition's tag instead of "required"
+		if !rule.required && len(rule.conditions) > 0 && isEmptyField(fieldValue) {
+			if cond, ok := s.conditionallyRequired(v, rule); ok {
+				errors = append(errors, rule.annotate(fieldPath, ValidationErrors{{
+					Field:   fieldPath,
+					Tag:     cond.tag,
+					Message: conditionalMessage(fieldPath, cond),
+				}})...)
+				continue
+			}
+		}
+
 		// Validate the field
-		if fieldErrors := s.validateField(fieldName, fieldValue.Interface(), rule); len(fieldErrors) > 0 {
+		if fieldErrors := s.validateValue(fieldPath, fieldValue, rule, fieldDoc, rules); len(fieldErrors) > 0 {
 			errors = append(errors, fieldErrors...)
+		} else if len(rule.crossFields) > 0 && !isEmptyField(fieldValue) {
+			errors = append(errors, rule.annotate(fieldPath, s.validateCrossFields(fieldPath, v, fieldValue, rule))...)
 		}
 	}
 
-	return errors
+	return append(errors, s.validateStructLevel(path, v)...)
 }
 
-// validateField validates a single field against its rule
-func (s *Schema) validateField(fieldName string, value any, rule fieldRule) ValidationErrors {
+// validateValue validates a value against its rule, recursing into nested structs,
+// slice elements and map values. doc is the value's part of the request document, if any.
+func (s *Schema) validateValue(path string, v reflect.Value, rule fieldRule, doc any, rules *ruleRegistry) ValidationErrors {
+	// Only box the value when a rule needs it
+	empty := isEmptyField(v)
 	var errors ValidationErrors
-
-	// Handle nil/empty values
-	if value == nil || (reflect.ValueOf(value).Kind() == reflect.String && value.(string) == "") {
-		if rule.required {
-			errors = append(errors, ValidationError{
-				Field:   fieldName,
-				Value:   value,
-				Tag:     "required",
-				Message: fmt.Sprintf("%s is required", fieldName),
-			})
+	if (empty && rule.required) || (!empty && (len(rule.checks) > 0 || rule.custom != nil)) {
+		errors = rule.annotate(path, s.validateField(path, v.Interface(), rule))
+	}
+	if empty {
+		// An empty slice or map still has an item count for minitems and len to check
+		if len(errors) == 0 && (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) {
+			errors = rule.annotate(path, validateItems(path, v, rule))
 		}
 		return errors
 	}
+	if len(rule.customRules) > 0 {
+		errors = append(errors, rule.annotate(path, validateCustomRules(path, v, rule, rules))...)
+	}
 
-	// String validations
-	if str, ok := value.(string); ok {
-		if rule.minLength >= 0 && len(str) < rule.minLength {
-			errors = append(errors, ValidationError{
-				Field:   fieldName,
-				Value:   value,
-				Tag:     "minlen",
-				Message: fmt.Sprintf("%s must be at least %d characters", fieldName, rule.minLength),
-			})
-		}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
 
-		if rule.maxLength >= 0 && len(str) > rule.maxLength {
-			errors = append(errors, ValidationError{
-				Field:   fieldName,
-				Value:   value,
-				Tag:     "maxlen",
-				Message: fmt.Sprintf("%s must be at most %d characters", fieldName, rule.maxLength),
-			})
+	switch v.Kind() {
+	case reflect.Struct:
+		if rule.nested != nil {
+			errors = append(errors, rule.nested.validateStruct(path, v, doc, rules)...)
 		}
-
-		if rule.email {
-			if !emailRegex.MatchString(str) {
-				errors = append(errors, ValidationError{
-					Field:   fieldName,
-					Value:   value,
-					Tag:     "email",
-					Message: fmt.Sprintf("%s must be a valid email", fieldName),
-				})
+	case reflect.Slice, reflect.Array:
+		errors = append(errors, rule.annotate(path, validateItems(path, v, rule))...)
+		if rule.elem != nil {
+			for i := 0; i < v.Len(); i++ {
+				errors = append(errors, s.validateValue(fmt.Sprintf("%s[%d]", path, i), v.Index(i), *rule.elem, elemDocument(doc, i), rules)...)
 			}
 		}
-
-		if rule.pattern != nil && !rule.pattern.MatchString(str) {
-			errors = append(errors, ValidationError{
-				Field:   fieldName,
-				Value:   value,
-				Tag:     "pattern",
-				Message: fmt.Sprintf("%s format is invalid", fieldName),
+	case reflect.Map:
+		errors = append(errors, rule.annotate(path, validateItems(path, v, rule))...)
+		if rule.elem != nil {
+			// Sort keys so errors are reported in a stable order
+			keys := v.MapKeys()
+			sort.Slice(keys, func(i, j int) bool {
+				return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
 			})
+			for _, key := range keys {
+				_, elemDoc := lookupPresence(doc, fmt.Sprint(key.Interface()))
+				errors = append(errors, s.validateValue(fmt.Sprintf("%s[%v]", path, key.Interface()), v.MapIndex(key), *rule.elem, elemDoc, rules)...)
+			}
 		}
+	}
 
-		if len(rule.enum) > 0 {
-			found := false
-			for _, allowed := range rule.enum {
-				if str == allowed {
-					found = true
-					break
-				}
+	return errors
+}
+
+// annotate fills in rule parameters and applies the field's `msg` override to errors reported for
+// the value at path. Errors for nested fields are left alone, as they carry their own rules.
+func (r fieldRule) annotate(path string, errs ValidationErrors) ValidationErrors {
+	for i := range errs {
+		if errs[i].Field != path && !strings.HasPrefix(errs[i].Field, path+"[") {
+			continue
+		}
+		if errs[i].Param == "" {
+			errs[i].Param = r.params[errs[i].Tag]
+		}
+		if r.message != "" {
+			errs[i].messageKey = r.message
+			errs[i].Message = renderMessage(r.message, errs[i])
+		}
+	}
+	return errs
+}
+
+// validateItems applies minitems, maxitems and unique rules to a slice, array or map
+func validateItems(path string, v reflect.Value, rule fieldRule) ValidationErrors {
+	var errors ValidationErrors
+
+	if rule.minItems >= 0 && v.Len() < rule.minItems {
+		errors = append(errors, ValidationError{
+			Field:   path,
+			Value:   v.Len(),
+			Tag:     "minitems",
+			Message: fmt.Sprintf("%s must contain at least %d items", path, rule.minItems),
+		})
+	}
+
+	if rule.maxItems >= 0 && v.Len() > rule.maxItems {
+		errors = append(errors, ValidationError{
+			Field:   path,
+			Value:   v.Len(),
+			Tag:     "maxitems",
+			Message: fmt.Sprintf("%s must contain at most %d items", path, rule.maxItems),
+		})
+	}
+
+	if rule.length >= 0 && v.Len() != rule.length {
+		errors = append(errors, ValidationError{
+			Field:   path,
+			Value:   v.Len(),
+			Tag:     "len",
+			Message: fmt.Sprintf("%s must contain exactly %d items", path, rule.length),
+		})
+	}
+
+	if rule.unique && v.Kind() != reflect.Map {
+		seen := make(map[any]struct{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if !v.Index(i).Comparable() {
+				continue
 			}
-			if !found {
+			item := v.Index(i).Interface()
+			if _, dup := seen[item]; dup {
 				errors = append(errors, ValidationError{
-					Field:   fieldName,
-					Value:   value,
-					Tag:     "enum",
-					Message: fmt.Sprintf("%s must be one of: %s", fieldName, strings.Join(rule.enum, ", ")),
+					Field:   fmt.Sprintf("%s[%d]", path, i),
+					Value:   item,
+					Tag:     "unique",
+					Message: fmt.Sprintf("%s must contain unique items", path),
 				})
+				break
 			}
+			seen[item] = struct{}{}
 		}
 	}
 
-	// Numeric validations
-	if num, ok := convertToInt(value); ok {
-		if rule.min != nil && num < *rule.min {
-			errors = append(errors, ValidationError{
-				Field:   fieldName,
-				Value:   value,
-				Tag:     "min",
-				Message: fmt.Sprintf("%s must be at least %d", fieldName, *rule.min),
-			})
-		}
+	return errors
+}
 
-		if rule.max != nil && num > *rule.max {
+// validateField validates a single field against its rule
+func (s *Schema) validateField(fieldName string, value any, rule fieldRule) ValidationErrors {
+	var errors ValidationErrors
+
+	// Handle nil/empty values
+	if isEmptyValue(value) {
+		if rule.required {
 			errors = append(errors, ValidationError{
 				Field:   fieldName,
 				Value:   value,
-				Tag:     "max",
-				Message: fmt.Sprintf("%s must be at most %d", fieldName, *rule.max),
+				Tag:     "required",
+				Message: fmt.Sprintf("%s is required", fieldName),
 			})
 		}
+		return errors
+	}
+
+	// Rule groups compiled for the field (string, numeric, file rules)
+	for _, check := range rule.checks {
+		errors = append(errors, check(fieldName, value)...)
 	}
 
 	// Custom validation
@@ -304,18 +753,51 @@ func (s *Schema) validateField(fieldName string, value any, rule fieldRule) Vali
 	return errors
 }
 
-// Helper function to get struct field name from JSON tag
-func getStructFieldName(t reflect.Type, jsonName string) string {
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		jsonTag := field.Tag.Get("json")
-		if jsonTag != "" {
-			tagName := strings.Split(jsonTag, ",")[0]
-			if tagName == jsonName {
-				return field.Name
-			}
+// isEmptyValue reports whether a value should fail a "required" rule:
+// nil, empty strings, nil pointers, and empty slices/maps.
+func isEmptyValue(value any) bool {
+	return isEmptyField(reflect.ValueOf(value))
+}
+
+// isEmptyField is isEmptyValue for a reflect.Value, avoiding boxing the value
+func isEmptyField(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Ptr:
+		return v.IsNil()
+	case reflect.Interface:
+		return v.IsNil() || isEmptyField(v.Elem())
+	}
+	return false
+}
+
+// schemaFieldName returns the schema key for a struct field: its JSON name, falling back to
+// the form, query or path tag for fields that aren't part of the JSON body.
+func schemaFieldName(field reflect.StructField) (name string, inJSON bool) {
+	if jsonTag := field.Tag.Get("json"); jsonTag != "" && jsonTag != "-" {
+		name = strings.Split(jsonTag, ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		return name, true
+	}
+	for _, key := range []string{"form", "query", "path", "header", "cookie"} {
+		if tag := tagName(field, key); tag != "" {
+			return tag, false
 		}
 	}
+	return "", false
+}
+
+// tagName returns the value of a field's tag for key ("" if it's missing or "-")
+func tagName(field reflect.StructField, key string) string {
+	if tag := field.Tag.Get(key); tag != "-" {
+		return tag
+	}
 	return ""
 }
 
@@ -372,99 +854,54 @@ func NewValidator[T any](example *T) *Validator[T] {
 	}
 }
 
-// ValidateJSON validates JSON data against a schema and unmarshal it
+// ValidateJSON validates JSON data against a schema and unmarshal it.
+// Decoding problems such as type mismatches are returned as ValidationErrors
+// (see JSONOptions for stricter decoding).
 func ValidateJSON(data []byte, target any, schema *Schema) error {
-	// First unmarshal into a map to check for missing/extra fields
-	var jsonData map[string]any
-	if err := json.Unmarshal(data, &jsonData); err != nil {
-		return fmt.Errorf("invalid JSON: %w", err)
-	}
-
-	// Unmarshal into the target struct
-	if err := json.Unmarshal(data, target); err != nil {
-		return fmt.Errorf("JSON unmarshal error: %w", err)
-	}
-
-	// Validate using schema
-	if errors := schema.Validate(target); len(errors) > 0 {
-		return errors
-	}
-
-	// Check if the struct implements ValidatedStruct for custom validation
-	if validator, ok := target.(ValidatedStruct); ok {
-		if err := validator.Validate(); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	doc, err := decodeJSONDocument(bytes.NewReader(data), target, schema)
+	return validateTarget(target, schema, doc, err, nil)
 }
 
 // ValidateQuery validates query parameters against a schema and binds them to a struct
 func ValidateQuery(queryParams url.Values, target any, schema *Schema) error {
-	v := reflect.ValueOf(target)
-	if v.Kind() != reflect.Ptr {
-		return fmt.Errorf("target must be a pointer to struct")
-	}
-
-	v = v.Elem()
-	if v.Kind() != reflect.Struct {
-		return fmt.Errorf("target must be a pointer to struct")
-	}
-
-	// Bind query parameters to struct fields
-	for fieldName, rule := range schema.fields {
-		structFieldName := getStructFieldName(schema.structType, fieldName)
-		if structFieldName == "" {
-			continue
-		}
-
-		fieldValue := v.FieldByName(structFieldName)
-		if !fieldValue.IsValid() || !fieldValue.CanSet() {
-			continue
-		}
-
-		// Get the query parameter value (use query tag or json tag)
-		structField, ok := schema.structType.FieldByName(structFieldName)
-		if !ok {
-			continue
-		}
-
-		queryTag := structField.Tag.Get("query")
-		if queryTag == "" {
-			queryTag = rule.jsonTag
-		}
-
-		paramValue := queryParams.Get(queryTag)
-
-		// Skip if empty and not required
-		if paramValue == "" {
-			continue
-		}
+	doc, err := bindQuery(queryParams, target, schema)
+	return validateTarget(target, schema, doc, err, nil)
+}
 
-		// Convert and set the value based on field type
-		if err := setFieldValue(fieldValue, paramValue); err != nil {
-			return fmt.Errorf("error setting field %s: %w", fieldName, err)
+// setFieldValue sets a struct field value from a string.
+// Pointers are allocated, time.Duration uses time.ParseDuration, and types implementing
+// encoding.TextUnmarshaler (time.Time, net.IP, custom IDs) parse themselves.
+func setFieldValue(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setFieldValue(ptr.Elem(), value); err != nil {
+			return err
 		}
+		field.Set(ptr)
+		return nil
 	}
 
-	// Validate using schema
-	if errors := schema.Validate(target); len(errors) > 0 {
-		return errors
+	if field.CanAddr() {
+		if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := unmarshaler.UnmarshalText([]byte(value)); err != nil {
+				if field.Type() == timeType {
+					return fmt.Errorf("invalid time value (expected RFC 3339): %s", value)
+				}
+				return fmt.Errorf("invalid %s value: %s", field.Type(), value)
+			}
+			return nil
+		}
 	}
 
-	// Check if the struct implements ValidatedStruct for custom validation
-	if validator, ok := target.(ValidatedStruct); ok {
-		if err := validator.Validate(); err != nil {
-			return err
+	if field.Type() == durationType {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration value: %s", value)
 		}
+		field.SetInt(int64(duration))
+		return nil
 	}
 
-	return nil
-}
-
-// setFieldValue sets a struct field value from a string
-func setFieldValue(field reflect.Value, value string) error {
 	switch field.Kind() {
 	case reflect.String:
 		field.SetString(value)
@@ -510,11 +947,8 @@ func WithBodyValidation[T any](validator *Validator[T]) func(Handler) Handler {
 			}
 
 			// Validate the request body
-			if err := ctx.BindAndValidateJSON(body, validator.Schema); err != nil {
-				if validationErrs, ok := err.(ValidationErrors); ok {
-					return ctx.SendValidationError(validationErrs)
-				}
-				return nil, 400, NewAPIError("invalid_request", err.Error())
+			if err := ctx.BindAndValidateBody(body, validator.Schema); err != nil {
+				return ctx.sendBindError(err, "invalid_request")
 			}
 
 			// Store validated body in context
@@ -536,10 +970,7 @@ func WithQueryValidation[T any](validator *Validator[T]) func(Handler) Handler {
 
 			// Validate the query parameters
 			if err := ctx.BindAndValidateQuery(query, validator.Schema); err != nil {
-				if validationErrs, ok := err.(ValidationErrors); ok {
-					return ctx.SendValidationError(validationErrs)
-				}
-				return nil, 400, NewAPIError("invalid_request", err.Error())
+				return ctx.sendBindError(err, "invalid_request")
 			}
 
 			// Store validated query in context
@@ -551,7 +982,15 @@ func WithQueryValidation[T any](validator *Validator[T]) func(Handler) Handler {
 	}
 }
 
-// populatePathParams populates a struct from path parameters using the "path" tag
+// ValidatePathParams binds path parameters to a struct using the "path" tag and validates it against a schema.
+// Values are converted to the field type (string, integers, floats, bool). Every path parameter is
+// always sent, so presence isn't tracked: `required` is met by any value, including 0.
+func ValidatePathParams(pathParams map[string]string, target any, schema *Schema) error {
+	return validateTarget(target, schema, nil, populatePathParams(pathParams, target), nil)
+}
+
+// populatePathParams populates a struct from path parameters using the "path" tag.
+// Values that can't be converted to the field type are returned as ValidationErrors.
 func populatePathParams(pathParams map[string]string, target any) error {
 	val := reflect.ValueOf(target)
 	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
@@ -561,6 +1000,7 @@ func populatePathParams(pathParams map[string]string, target any) error {
 	val = val.Elem()
 	typ := val.Type()
 
+	var bindErrs ValidationErrors
 	for i := 0; i < val.NumField(); i++ {
 		field := val.Field(i)
 		fieldType := typ.Field(i)
@@ -582,14 +1022,16 @@ func populatePathParams(pathParams map[string]string, target any) error {
 			return fmt.Errorf("required path parameter '%s' not found", pathTag)
 		}
 
-		// Set the field value
-		if field.Kind() == reflect.String {
-			field.SetString(paramValue)
-		} else {
-			return fmt.Errorf("path parameter '%s' has unsupported type %s (only string is supported)", pathTag, field.Kind())
+		// Convert and set the field value
+		if err := setFieldValue(field, paramValue); err != nil {
+			fieldName, _ := schemaFieldName(fieldType)
+			bindErrs = append(bindErrs, typeError(fieldName, paramValue, err))
 		}
 	}
 
+	if len(bindErrs) > 0 {
+		return bindErrs
+	}
 	return nil
 }
 
@@ -611,9 +1053,9 @@ func WithPathParams[T any](validator *Validator[T]) func(Handler) Handler {
 				return nil, 400, NewAPIError("invalid_request", "params factory returned nil")
 			}
 
-			// Extract path parameters and populate the struct
-			if err := populatePathParams(ctx.PathParams, params); err != nil {
-				return nil, 400, NewAPIError("invalid_path_params", err.Error())
+			// Extract path parameters, populate the struct and validate it
+			if err := ctx.BindAndValidatePathParams(params, validator.Schema); err != nil {
+				return ctx.sendBindError(err, "invalid_path_params")
 			}
 
 			// Store validated params in context
@@ -631,6 +1073,8 @@ func WithPathParams[T any](validator *Validator[T]) func(Handler) Handler {
 
 // WithTyped wraps a typed handler with automatic validation and injection of parameters.
 // Pass nil for any validator you don't need. Unused fields in TypedRequest will be nil.
+// The validators are captured when the route is registered, so the generated OpenAPI spec documents
+// the route's path parameters, query parameters and request body without RouteMetadata.
 //
 // Parameters:
 //   - handler: Your typed handler function
@@ -680,7 +1124,9 @@ func WithTyped[P any, B any, Q any](
 	body *Validator[B],
 	query *Validator[Q],
 ) Handler {
-	return func(ctx *Context) (any, int, error) {
+	spec := &handlerSpec{params: validatorSchema(params), body: validatorSchema(body), query: validatorSchema(query)}
+
+	return describeHandler(func(ctx *Context) (any, int, error) {
 		var paramsPtr *P
 		var bodyPtr *B
 		var queryPtr *Q
@@ -691,8 +1137,8 @@ func WithTyped[P any, B any, Q any](
 			if paramsPtr == nil {
 				return nil, 400, NewAPIError("invalid_request", "params factory returned nil")
 			}
-			if err := populatePathParams(ctx.PathParams, paramsPtr); err != nil {
-				return nil, 400, NewAPIError("invalid_path_params", err.Error())
+			if err := ctx.BindAndValidatePathParams(paramsPtr, params.Schema); err != nil {
+				return ctx.sendBindError(err, "invalid_path_params")
 			}
 			ctx.Set(ContextKeyValidatedParams, paramsPtr)
 		}
@@ -703,8 +1149,8 @@ func WithTyped[P any, B any, Q any](
 			if bodyPtr == nil {
 				return nil, 400, NewAPIError("invalid_request", "body factory returned nil")
 			}
-			if err := ctx.BindAndValidateJSON(bodyPtr, body.Schema); err != nil {
-				return nil, 400, NewAPIError("invalid_request", err.Error())
+			if err := ctx.BindAndValidateBody(bodyPtr, body.Schema); err != nil {
+				return ctx.sendBindError(err, "invalid_request")
 			}
 			ctx.Set(ContextKeyValidatedBody, bodyPtr)
 		}
@@ -716,10 +1162,7 @@ func WithTyped[P any, B any, Q any](
 				return nil, 400, NewAPIError("invalid_request", "query factory returned nil")
 			}
 			if err := ctx.BindAndValidateQuery(queryPtr, query.Schema); err != nil {
-				if validationErrs, ok := err.(ValidationErrors); ok {
-					return ctx.SendValidationError(validationErrs)
-				}
-				return nil, 400, NewAPIError("invalid_request", err.Error())
+				return ctx.sendBindError(err, "invalid_request")
 			}
 			ctx.Set(ContextKeyValidatedQuery, queryPtr)
 		}
@@ -732,5 +1175,41 @@ func WithTyped[P any, B any, Q any](
 		}
 
 		return handler(ctx, req)
-	}
+	}, spec)
+}
+
+// WithTypedResponse is WithTyped for handlers that return a typed response.
+// The response type is added to the generated OpenAPI spec's components/schemas, and is checked
+// against its `validate` tags when the router enables response validation (see Router.SetResponseValidation).
+//
+// Example:
+//
+//	func getUser(ctx *api.Context, req *api.TypedRequest[UserParams, struct{}, struct{}]) (*User, int, error) {
+//	    return users[req.Params.ID], 200, nil
+//	}
+//	router.AddRoute(http.MethodGet, "/users/:id",
+//	    api.WithTypedResponse(getUser, userParamsValidator, nil, nil))
+func WithTypedResponse[P any, B any, Q any, Resp any](
+	handler HandlerFuncTypedResponse[P, B, Q, Resp],
+	params *Validator[P],
+	body *Validator[B],
+	query *Validator[Q],
+) Handler {
+	respSchema := responseSchema(reflect.TypeFor[Resp]())
+
+	typed := WithTyped(func(ctx *Context, req *TypedRequest[P, B, Q]) (any, int, error) {
+		resp, status, err := handler(ctx, req)
+		// A typed nil would be sent as "data": null instead of 204 No Content
+		if err != nil || resp == nil {
+			return nil, status, err
+		}
+		if err := ctx.checkResponse(resp, respSchema); err != nil {
+			return nil, 0, err
+		}
+		return resp, status, nil
+	}, params, body, query)
+
+	spec := *lookupHandlerSpec(typed)
+	spec.responseType = reflect.TypeFor[Resp]()
+	return describeHandler(typed, &spec)
 }
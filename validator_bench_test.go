@@ -0,0 +1,101 @@
+package nimbus
+
+import (
+	"net/url"
+	"testing"
+)
+
+func BenchmarkNewSchema(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewSchema(TestOrder{})
+	}
+}
+
+func BenchmarkSchema_Validate_Valid(b *testing.B) {
+	schema := NewSchema(TestUser{})
+	user := TestUser{Name: "John Doe", Email: "john@example.com", Age: 25, Role: "user", Password: "password123"}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		schema.Validate(user)
+	}
+}
+
+func BenchmarkSchema_Validate_Invalid(b *testing.B) {
+	schema := NewSchema(TestUser{})
+	user := TestUser{Name: "J", Email: "invalid", Age: 15, Role: "guest", Password: "short"}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		schema.Validate(user)
+	}
+}
+
+func BenchmarkSchema_Validate_Pattern(b *testing.B) {
+	schema := NewSchema(TestContact{})
+	contact := TestContact{
+		Name:       "John Doe",
+		Email:      "john@example.com",
+		Phone:      "555-123-4567",
+		Website:    "https://example.com",
+		PostalCode: "12345-6789",
+		HexColor:   "#FF5733",
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		schema.Validate(contact)
+	}
+}
+
+func BenchmarkSchema_Validate_Nested(b *testing.B) {
+	schema := NewSchema(TestOrder{})
+	order := TestOrder{
+		Customer: "alice",
+		Shipping: TestAddress{Street: "1 Main St", City: "Springfield"},
+		Items:    []TestLineItem{{SKU: "ABC-1", Quantity: 2}, {SKU: "XYZ-9", Quantity: 1}},
+		Tags:     []string{"gift", "rush"},
+		Labels:   map[string]string{"env": "prod"},
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		schema.Validate(order)
+	}
+}
+
+func BenchmarkValidateJSON(b *testing.B) {
+	schema := NewSchema(TestUser{})
+	data := []byte(`{"name":"John Doe","email":"john@example.com","age":25,"role":"user","password":"password123"}`)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var user TestUser
+		ValidateJSON(data, &user, schema)
+	}
+}
+
+func BenchmarkValidateQuery(b *testing.B) {
+	schema := NewSchema(TestSearchQuery{})
+	query := url.Values{
+		"query":     {"laptop"},
+		"category":  {"electronics"},
+		"min_price": {"100"},
+		"max_price": {"2000"},
+		"page":      {"1"},
+		"limit":     {"10"},
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var params TestSearchQuery
+		ValidateQuery(query, &params, schema)
+	}
+}
@@ -68,6 +68,60 @@ func TestNewSchema(t *testing.T) {
 	}
 }
 
+func TestNewSchema_Cached(t *testing.T) {
+	first := NewSchema(TestOrder{})
+	second := NewSchema(&TestOrder{})
+
+	if first == second {
+		t.Fatal("expected each NewSchema call to return its own schema")
+	}
+	if first.fields["shipping"].nested != second.fields["shipping"].nested {
+		t.Error("expected nested schemas to be shared from the cache")
+	}
+
+	// Customizing one schema doesn't affect others for the same type
+	first.AddCustomValidator("customer", func(any) error { return errors.New("blocked") })
+	order := TestOrder{
+		Customer: "alice",
+		Shipping: TestAddress{Street: "1 Main St", City: "Springfield"},
+		Items:    []TestLineItem{{SKU: "ABC-1", Quantity: 1}},
+	}
+	if errs := first.Validate(order); len(errs) != 1 || errs[0].Tag != "custom" {
+		t.Errorf("expected custom error, got %v", errs)
+	}
+	if errs := second.Validate(order); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+	if errs := NewSchema(TestOrder{}).Validate(order); len(errs) != 0 {
+		t.Errorf("expected no errors from a new schema, got %v", errs)
+	}
+}
+
+func TestSchema_Validate_DeclarationOrder(t *testing.T) {
+	schema := NewSchema(TestUser{})
+	user := TestUser{Name: "J", Email: "invalid", Age: 15, Role: "guest", Password: "short"}
+
+	expected := []string{"name", "email", "age", "role", "password"}
+	for i := 0; i < 10; i++ {
+		errs := schema.Validate(user)
+		if len(errs) != len(expected) {
+			t.Fatalf("expected %d errors, got %v", len(expected), errs)
+		}
+		for j, err := range errs {
+			if err.Field != expected[j] {
+				t.Fatalf("expected errors in declaration order %v, got %v", expected, errs)
+			}
+		}
+	}
+}
+
+func TestSchema_Validate_WrongType(t *testing.T) {
+	errs := NewSchema(TestUser{}).Validate(TestProduct{Name: "Widget"})
+	if len(errs) != 1 || errs[0].Field != "root" {
+		t.Errorf("expected a root error for a mismatched type, got %v", errs)
+	}
+}
+
 func TestSchema_Validate_Success(t *testing.T) {
 	schema := NewSchema(TestUser{})
 
@@ -1031,3 +1085,212 @@ func TestAddCustomValidator_CombinedWithBuiltIn(t *testing.T) {
 		t.Error("Expected custom validation error for username")
 	}
 }
+
+type TestAddress struct {
+	Street string `json:"street" validate:"required"`
+	City   string `json:"city" validate:"required,minlen=2"`
+}
+
+type TestLineItem struct {
+	SKU      string `json:"sku" validate:"required,pattern=^[A-Z]{3}-\\d+$"`
+	Quantity int    `json:"quantity" validate:"min=1"`
+}
+
+type TestOrder struct {
+	Customer string                   `json:"customer" validate:"required"`
+	Shipping TestAddress              `json:"shipping"`
+	Billing  *TestAddress             `json:"billing"`
+	Items    []TestLineItem           `json:"items" validate:"required,minitems=1,maxitems=3"`
+	Tags     []string                 `json:"tags" validate:"unique,dive,minlen=2"`
+	Labels   map[string]string        `json:"labels" validate:"maxitems=2,dive,maxlen=5"`
+	Extras   map[string]*TestLineItem `json:"extras"`
+}
+
+type TestCategory struct {
+	Name     string         `json:"name" validate:"required"`
+	Children []TestCategory `json:"children"`
+}
+
+func TestSchema_Validate_Nested(t *testing.T) {
+	schema := NewSchema(TestOrder{})
+
+	valid := TestOrder{
+		Customer: "alice",
+		Shipping: TestAddress{Street: "1 Main St", City: "Springfield"},
+		Items:    []TestLineItem{{SKU: "ABC-1", Quantity: 2}},
+		Tags:     []string{"gift", "rush"},
+		Labels:   map[string]string{"env": "prod"},
+	}
+	if errs := schema.Validate(valid); len(errs) > 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(o *TestOrder)
+		expected map[string]string // field path -> tag
+	}{
+		{
+			name:     "nested struct",
+			modify:   func(o *TestOrder) { o.Shipping.City = "" },
+			expected: map[string]string{"shipping.city": "required"},
+		},
+		{
+			name:     "nested pointer struct",
+			modify:   func(o *TestOrder) { o.Billing = &TestAddress{City: "X"} },
+			expected: map[string]string{"billing.street": "required", "billing.city": "minlen"},
+		},
+		{
+			name: "slice elements",
+			modify: func(o *TestOrder) {
+				o.Items = append(o.Items, TestLineItem{SKU: "abc", Quantity: 1}, TestLineItem{SKU: "XYZ-9", Quantity: 0})
+			},
+			expected: map[string]string{"items[1].sku": "pattern", "items[2].quantity": "min"},
+		},
+		{
+			name:     "empty required slice",
+			modify:   func(o *TestOrder) { o.Items = nil },
+			expected: map[string]string{"items": "required"},
+		},
+		{
+			name: "maxitems",
+			modify: func(o *TestOrder) {
+				o.Items = []TestLineItem{{"ABC-1", 1}, {"ABC-2", 1}, {"ABC-3", 1}, {"ABC-4", 1}}
+			},
+			expected: map[string]string{"items": "maxitems"},
+		},
+		{
+			name:     "dive and unique",
+			modify:   func(o *TestOrder) { o.Tags = []string{"gift", "x", "gift"} },
+			expected: map[string]string{"tags[2]": "unique", "tags[1]": "minlen"},
+		},
+		{
+			name:     "map values",
+			modify:   func(o *TestOrder) { o.Labels = map[string]string{"env": "production"} },
+			expected: map[string]string{"labels[env]": "maxlen"},
+		},
+		{
+			name:     "map of structs",
+			modify:   func(o *TestOrder) { o.Extras = map[string]*TestLineItem{"bonus": {SKU: "ABC-1"}} },
+			expected: map[string]string{"extras[bonus].quantity": "min"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := valid
+			order.Items = append([]TestLineItem(nil), valid.Items...)
+			tt.modify(&order)
+
+			errs := schema.Validate(order)
+			if len(errs) != len(tt.expected) {
+				t.Fatalf("expected %d errors, got %v", len(tt.expected), errs)
+			}
+			for _, err := range errs {
+				if tt.expected[err.Field] != err.Tag {
+					t.Errorf("unexpected error %s/%s: %s", err.Field, err.Tag, err.Message)
+				}
+			}
+		})
+	}
+}
+
+type TestItemCountRequest struct {
+	Tags  []string       `json:"tags" validate:"minitems=1"`
+	Pair  []int          `json:"pair" validate:"len=2"`
+	Attrs map[string]int `json:"attrs" validate:"minitems=1"`
+}
+
+func TestSchema_Validate_EmptyItemCount(t *testing.T) {
+	schema := NewSchema(TestItemCountRequest{})
+
+	tests := []struct {
+		name     string
+		body     string // Validated with ValidateJSON, or Schema.Validate when empty
+		value    TestItemCountRequest
+		expected map[string]string // field path -> tag
+	}{
+		{
+			name:     "empty slices and maps",
+			value:    TestItemCountRequest{Tags: []string{}, Pair: []int{}, Attrs: map[string]int{}},
+			expected: map[string]string{"tags": "minitems", "pair": "len", "attrs": "minitems"},
+		},
+		{
+			name:     "nil slices and maps",
+			expected: map[string]string{"tags": "minitems", "pair": "len", "attrs": "minitems"},
+		},
+		{
+			name:     "empty in json",
+			body:     `{"tags":[],"pair":[],"attrs":{}}`,
+			expected: map[string]string{"tags": "minitems", "pair": "len", "attrs": "minitems"},
+		},
+		{
+			name: "absent in json",
+			body: `{}`,
+		},
+		{
+			name:  "enough items",
+			value: TestItemCountRequest{Tags: []string{"a"}, Pair: []int{1, 2}, Attrs: map[string]int{"a": 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs ValidationErrors
+			if tt.body != "" {
+				var req TestItemCountRequest
+				if err := ValidateJSON([]byte(tt.body), &req, schema); err != nil {
+					var ok bool
+					if errs, ok = err.(ValidationErrors); !ok {
+						t.Fatalf("expected ValidationErrors, got %v", err)
+					}
+				}
+			} else {
+				errs = schema.Validate(tt.value)
+			}
+
+			if len(errs) != len(tt.expected) {
+				t.Fatalf("expected %d errors, got %v", len(tt.expected), errs)
+			}
+			for _, err := range errs {
+				if tt.expected[err.Field] != err.Tag {
+					t.Errorf("unexpected error %s/%s: %s", err.Field, err.Tag, err.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestSchema_Validate_RecursiveType(t *testing.T) {
+	schema := NewSchema(TestCategory{})
+
+	if schema.fields["children"].elem.nested != schema {
+		t.Error("expected recursive type to reuse its schema")
+	}
+
+	root := TestCategory{
+		Name: "root",
+		Children: []TestCategory{
+			{Name: "a"},
+			{Name: "b", Children: []TestCategory{{Name: ""}}},
+		},
+	}
+
+	errs := schema.Validate(root)
+	if len(errs) != 1 || errs[0].Field != "children[1].children[0].name" {
+		t.Errorf("expected error on children[1].children[0].name, got %v", errs)
+	}
+}
+
+func TestValidateJSON_Nested(t *testing.T) {
+	schema := NewSchema(TestOrder{})
+	data := []byte(`{"customer":"bob","shipping":{"street":"x","city":"Paris"},"items":[{"sku":"ABC-1","quantity":1},{"sku":"","quantity":1}]}`)
+
+	var order TestOrder
+	err := ValidateJSON(data, &order, schema)
+
+	validationErrs, ok := err.(ValidationErrors)
+	if !ok || len(validationErrs) != 1 || validationErrs[0].Field != "items[1].sku" || validationErrs[0].Tag != "required" {
+		t.Errorf("expected required error for items[1].sku, got %v", err)
+	}
+}
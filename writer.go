@@ -0,0 +1,147 @@
+package nimbus
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// noWritten is the sentinel size for a response whose headers have not been committed yet.
+const noWritten = -1
+
+// ResponseWriter wraps http.ResponseWriter and records what has been sent to the client.
+// Middleware can inspect the final status code and body size after the handler chain runs,
+// and the router uses Written() to avoid writing a second response.
+//
+// Optional interfaces of the underlying writer (http.Flusher, http.Hijacker, io.ReaderFrom)
+// are preserved, and Unwrap() exposes the original writer to http.ResponseController.
+type ResponseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+	http.Hijacker
+	io.ReaderFrom
+
+	// Status returns the HTTP status code of the response (200 if not yet written).
+	Status() int
+	// Size returns the number of body bytes written (0 if not yet written).
+	Size() int
+	// Written reports whether the status line and headers have been committed.
+	Written() bool
+	// Unwrap returns the underlying http.ResponseWriter (used by http.ResponseController).
+	Unwrap() http.ResponseWriter
+}
+
+// responseWriter is the pooled ResponseWriter implementation embedded in every Context.
+// It is stored by value in Context so wrapping the writer costs no extra allocation.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+var _ ResponseWriter = (*responseWriter)(nil)
+
+// reset prepares the writer for a new request.
+func (w *responseWriter) reset(rw http.ResponseWriter) {
+	w.ResponseWriter = rw
+	w.status = http.StatusOK
+	w.size = noWritten
+}
+
+// WriteHeader records the status code and commits headers.
+// Subsequent calls are ignored, preventing "superfluous WriteHeader" responses
+// when a handler writes a response and then returns an error.
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.Written() {
+		return
+	}
+	// 1xx informational responses can be sent multiple times before the final header
+	if statusCode >= 100 && statusCode <= 199 && statusCode != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	w.status = statusCode
+	w.size = 0
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// writeHeaderIfNeeded commits an implicit 200 before the first body write.
+func (w *responseWriter) writeHeaderIfNeeded() {
+	if !w.Written() {
+		w.WriteHeader(w.status)
+	}
+}
+
+// Write writes body bytes, committing headers with the recorded status if needed.
+func (w *responseWriter) Write(data []byte) (int, error) {
+	w.writeHeaderIfNeeded()
+	n, err := w.ResponseWriter.Write(data)
+	w.size += n
+	return n, err
+}
+
+// WriteString writes a string body without converting to []byte when the
+// underlying writer supports io.StringWriter.
+func (w *responseWriter) WriteString(s string) (int, error) {
+	w.writeHeaderIfNeeded()
+	n, err := io.WriteString(w.ResponseWriter, s)
+	w.size += n
+	return n, err
+}
+
+// ReadFrom copies from r using the underlying writer's io.ReaderFrom when available
+// (e.g., sendfile for *os.File on TCP connections).
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.writeHeaderIfNeeded()
+	var n int64
+	var err error
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(r)
+	} else {
+		n, err = io.Copy(w.ResponseWriter, r)
+	}
+	w.size += int(n)
+	return n, err
+}
+
+// Flush sends buffered data to the client, committing headers first.
+// Silently does nothing if the underlying writer cannot flush.
+func (w *responseWriter) Flush() {
+	w.writeHeaderIfNeeded()
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack lets the caller take over the connection (e.g., for WebSockets).
+// Returns http.ErrNotSupported if the underlying writer cannot be hijacked.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && !w.Written() {
+		// The connection now belongs to the caller; nothing more may be written by the router
+		w.size = 0
+	}
+	return conn, rw, err
+}
+
+// Status returns the recorded HTTP status code.
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+// Size returns the number of body bytes written.
+func (w *responseWriter) Size() int {
+	if w.size == noWritten {
+		return 0
+	}
+	return w.size
+}
+
+// Written reports whether headers have been committed.
+func (w *responseWriter) Written() bool {
+	return w.size != noWritten
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,179 @@
+package nimbus
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseWriter_TracksStatusAndSize(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	if ctx.Writer.Written() {
+		t.Fatal("expected writer to be uncommitted before any write")
+	}
+	if ctx.Writer.Status() != http.StatusOK {
+		t.Errorf("expected default status 200, got %d", ctx.Writer.Status())
+	}
+
+	ctx.String(http.StatusAccepted, "hello")
+
+	if !ctx.Writer.Written() {
+		t.Error("expected writer to be committed")
+	}
+	if ctx.Writer.Status() != http.StatusAccepted {
+		t.Errorf("expected status 202, got %d", ctx.Writer.Status())
+	}
+	if ctx.Writer.Size() != 5 {
+		t.Errorf("expected size 5, got %d", ctx.Writer.Size())
+	}
+}
+
+func TestResponseWriter_ImplicitWriteHeader(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	ctx.Writer.Write([]byte("ok"))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if ctx.Writer.Status() != http.StatusOK {
+		t.Errorf("expected recorded status 200, got %d", ctx.Writer.Status())
+	}
+}
+
+func TestResponseWriter_IgnoresSecondWriteHeader(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	ctx.Writer.WriteHeader(http.StatusCreated)
+	ctx.Writer.WriteHeader(http.StatusInternalServerError)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected first status 201 to win, got %d", w.Code)
+	}
+	if ctx.Writer.Status() != http.StatusCreated {
+		t.Errorf("expected recorded status 201, got %d", ctx.Writer.Status())
+	}
+}
+
+func TestRouter_NoDoubleWriteOnErrorAfterWrite(t *testing.T) {
+	router := NewRouter()
+
+	router.AddRoute(http.MethodGet, "/partial", func(ctx *Context) (any, int, error) {
+		ctx.String(http.StatusOK, "partial")
+		return nil, http.StatusInternalServerError, errors.New("failed after write")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/partial", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if w.Body.String() != "partial" {
+		t.Errorf("expected body 'partial', got %q", w.Body.String())
+	}
+}
+
+func TestResponseWriter_Flush(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	ctx.Writer.Flush()
+
+	if !w.Flushed {
+		t.Error("expected underlying recorder to be flushed")
+	}
+	if !ctx.Writer.Written() {
+		t.Error("expected flush to commit headers")
+	}
+}
+
+func TestResponseWriter_ReadFrom(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	n, err := ctx.Writer.ReadFrom(strings.NewReader("streamed body"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 13 || ctx.Writer.Size() != 13 {
+		t.Errorf("expected 13 bytes, got n=%d size=%d", n, ctx.Writer.Size())
+	}
+	if w.Body.String() != "streamed body" {
+		t.Errorf("unexpected body %q", w.Body.String())
+	}
+}
+
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (h *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.hijacked = true
+	return nil, nil, nil
+}
+
+func TestResponseWriter_Hijack(t *testing.T) {
+	// Recorder does not support hijacking
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if _, _, err := ctx.Writer.Hijack(); !errors.Is(err, http.ErrNotSupported) {
+		t.Errorf("expected http.ErrNotSupported, got %v", err)
+	}
+	ctx.Release()
+
+	h := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder()}
+	ctx = NewContext(h, httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	if _, _, err := ctx.Writer.Hijack(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !h.hijacked {
+		t.Error("expected underlying writer to be hijacked")
+	}
+	if !ctx.Writer.Written() {
+		t.Error("expected hijacked writer to be marked as written")
+	}
+}
+
+func TestResponseWriter_ResponseController(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := NewContext(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	if err := http.NewResponseController(ctx.Writer).Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !w.Flushed {
+		t.Error("expected ResponseController to flush the underlying recorder")
+	}
+}
+
+func TestResponseWriter_ResetOnRelease(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	ctx.String(http.StatusTeapot, "short and stout")
+	ctx.Release()
+
+	ctx = NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	defer ctx.Release()
+
+	if ctx.Writer.Written() || ctx.Writer.Size() != 0 || ctx.Writer.Status() != http.StatusOK {
+		t.Errorf("expected fresh writer state, got written=%v size=%d status=%d",
+			ctx.Writer.Written(), ctx.Writer.Size(), ctx.Writer.Status())
+	}
+}